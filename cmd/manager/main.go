@@ -18,7 +18,10 @@ import (
 	"github.com/golang/glog"
 
 	"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis"
-	jenkinsimage "github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkinsimage"
+	_ "github.com/redhat-developer/openshift-jenkins-operator/pkg/controller"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/s3"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/webhook"
 
 	appsv1 "github.com/openshift/api/apps/v1"
 	buildv1 "github.com/openshift/api/build/v1"
@@ -52,10 +55,24 @@ var (
 var log = logf.Log.WithName("cmd")
 
 func main() {
+	// The operator image also runs the backups to the object storage, in jobs created by the operator
+	if len(os.Args) > 1 && os.Args[1] == s3.BackupCommand {
+		if err := s3.RunBackup(jenkins.JenkinsVolumeMountPath, "/dev/termination-log", os.Getenv); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	// Add the zap logger flag set to the CLI.
 	pflag.CommandLine.AddFlagSet(zap.FlagSet())
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	debug := pflag.Bool("debug", false, "Set log level to debug")
+	pflag.IntVar(&jenkins.MaxConcurrentReconciles, "max-concurrent-reconciles", jenkins.MaxConcurrentReconciles,
+		"Maximum number of Jenkins instances reconciled in parallel")
+	webhookOptions := webhook.Options{}
+	pflag.Int32Var(&webhookOptions.Port, "webhook-port", webhook.DefaultPort, "Port of the admission webhook server, 0 disables it")
+	pflag.StringVar(&webhookOptions.CertDir, "webhook-cert-dir", webhook.DefaultCertDir,
+		"Directory of the serving certificate of the webhook server, a self-signed one is generated when it is empty")
 
 	pflag.Parse()
 	logf.SetLogger(zapLogger(*debug))
@@ -79,10 +96,11 @@ func main() {
 
 	// Setup all Controllers , add here other calls to your controllers
 	log.Info("Registering controllers.")
-	setupControllerOrExit(mgr, controllerutil.AddToManager) // Setup jenkins-controller
-	setupControllerOrExit(mgr, jenkinsimage.Add)            // Setup jenkinsimage-controller
+	setupControllerOrExit(mgr, controllerutil.AddToManager) // Setup jenkins-controller and jenkinsimage-controller
 	log.Info("All controllers registered successfully.")
 
+	setupWebhookServerOrExit(mgr, webhookOptions)
+
 	log.Info("Intializing metrics server")
 	initializeMetricsServer(cfg, ctx, namespace)
 	log.Info("Metrics server initialization complete.")
@@ -154,6 +172,22 @@ func setupControllerOrExit(mgr manager.Manager, f func(manager.Manager) error) {
 	log.Info(fmt.Sprintf("Controller initialized: %+v", reflect.ValueOf(f)))
 }
 
+// Register the admission webhook server to a manager. It only runs in the cluster, where the webhook Service
+// of the operator namespace points to it.
+func setupWebhookServerOrExit(mgr manager.Manager, options webhook.Options) {
+	namespace, err := k8sutil.GetOperatorNamespace()
+	if err != nil {
+		log.Info("The webhook server only runs in the cluster, skipping it", "error", err.Error())
+		return
+	}
+	options.Namespace = namespace
+	if err := webhook.AddToManager(mgr, options); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+	log.Info("Webhook server initialized")
+}
+
 func initializeMetricsServer(cfg *rest.Config, ctx context.Context, namespace string) {
 	if err := serveCRMetrics(cfg); err != nil {
 		log.Info("Could not generate and serve custom resource metrics", "error", err.Error())
@@ -0,0 +1,80 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetCondition returns the condition with the given type, or nil if it is not set
+func (s *JenkinsStatus) GetCondition(conditionType JenkinsConditionType) *JenkinsCondition {
+	return getCondition(s.Conditions, conditionType)
+}
+
+// SetCondition adds or updates the condition with the given type.
+// LastTransitionTime is only bumped when the status of the condition changes.
+func (s *JenkinsStatus) SetCondition(conditionType JenkinsConditionType, status corev1.ConditionStatus, reason, message string) {
+	setCondition(&s.Conditions, conditionType, status, reason, message)
+}
+
+// RemoveCondition removes the condition with the given type
+func (s *JenkinsStatus) RemoveCondition(conditionType JenkinsConditionType) {
+	conditions := []JenkinsCondition{}
+	for _, condition := range s.Conditions {
+		if condition.Type != conditionType {
+			conditions = append(conditions, condition)
+		}
+	}
+	s.Conditions = conditions
+}
+
+// IsConditionTrue returns true when the condition with the given type is set to True
+func (s *JenkinsStatus) IsConditionTrue(conditionType JenkinsConditionType) bool {
+	condition := s.GetCondition(conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// GetCondition returns the condition with the given type, or nil if it is not set
+func (s *JenkinsImageStatus) GetCondition(conditionType JenkinsConditionType) *JenkinsCondition {
+	return getCondition(s.Conditions, conditionType)
+}
+
+// SetCondition adds or updates the condition with the given type.
+// LastTransitionTime is only bumped when the status of the condition changes.
+func (s *JenkinsImageStatus) SetCondition(conditionType JenkinsConditionType, status corev1.ConditionStatus, reason, message string) {
+	setCondition(&s.Conditions, conditionType, status, reason, message)
+}
+
+// IsConditionTrue returns true when the condition with the given type is set to True
+func (s *JenkinsImageStatus) IsConditionTrue(conditionType JenkinsConditionType) bool {
+	condition := s.GetCondition(conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+func getCondition(conditions []JenkinsCondition, conditionType JenkinsConditionType) *JenkinsCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func setCondition(conditions *[]JenkinsCondition, conditionType JenkinsConditionType, status corev1.ConditionStatus, reason, message string) {
+	existing := getCondition(*conditions, conditionType)
+	if existing == nil {
+		*conditions = append(*conditions, JenkinsCondition{
+			Type:               conditionType,
+			Status:             status,
+			LastTransitionTime: metav1.Now(),
+			Reason:             reason,
+			Message:            message,
+		})
+		return
+	}
+	if existing.Status != status {
+		existing.Status = status
+		existing.LastTransitionTime = metav1.Now()
+	}
+	existing.Reason = reason
+	existing.Message = message
+}
@@ -1,28 +1,341 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
-// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
-
 // JenkinsSpec defines the desired state of Jenkins
 // +k8s:openapi-gen=true
 type JenkinsSpec struct {
-	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
-	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
-	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
-	Persistence         JenkinsPersistence `json:"persistence"` // Configure Jenkins Persistence
-	UseDeploymentConfig bool               `json:"useDeploymentConfig,omitempty"`
+	// Persistence configures the volume holding JENKINS_HOME, an emptyDir when it is not enabled
+	Persistence JenkinsPersistence `json:"persistence"`
+	// UseDeploymentConfig runs Jenkins in a DeploymentConfig instead of a Deployment, it requires the OpenShift
+	// apps API
+	UseDeploymentConfig bool `json:"useDeploymentConfig,omitempty"`
+	// DeletionPolicy defines what happens to JENKINS_HOME when the Jenkins instance is deleted
+	// +kubebuilder:validation:Enum=Delete;Retain;Snapshot
+	DeletionPolicy JenkinsDeletionPolicy `json:"deletionPolicy,omitempty"`
+	// Image defines the image of the Jenkins container, the OpenShift Jenkins image by default
+	Image *JenkinsImageSource `json:"image,omitempty"`
+	// JenkinsImageRef is the name of a JenkinsImage of the namespace whose latest build is used as the image
+	// of the Jenkins container. Jenkins is rolled out again when a new image is built. Cannot be used with Image.
+	JenkinsImageRef string `json:"jenkinsImageRef,omitempty"`
+	// Resources replaces the default resource requirements (1Gi memory limit) of the Jenkins container
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// LivenessProbe overrides the timings of the liveness probe of the Jenkins container
+	LivenessProbe *JenkinsProbe `json:"livenessProbe,omitempty"`
+	// ReadinessProbe overrides the timings of the readiness probe of the Jenkins container
+	ReadinessProbe *JenkinsProbe `json:"readinessProbe,omitempty"`
+	// JavaOpts is passed to the Jenkins JVM through the JAVA_OPTS environment variable
+	JavaOpts string `json:"javaOpts,omitempty"`
+	// JenkinsOpts is passed to Jenkins through the JENKINS_OPTS environment variable
+	JenkinsOpts string `json:"jenkinsOpts,omitempty"`
+	// Env holds additional environment variables of the Jenkins container
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// EnvFrom holds additional sources of environment variables of the Jenkins container
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+	// Volumes holds additional volumes of the Jenkins pod
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// VolumeMounts holds additional mounts of the Jenkins container, of the volumes defined in Volumes
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+	// ConfigurationAsCode references the Jenkins Configuration as Code YAML files applied to the instance
+	ConfigurationAsCode *JenkinsConfigurationAsCode `json:"configurationAsCode,omitempty"`
+	// Backup schedules backups of JENKINS_HOME, it requires persistence to be enabled
+	Backup *JenkinsBackupSchedule `json:"backup,omitempty"`
+	// ObjectStorage defines an S3 compatible object storage where JENKINS_HOME is archived on demand, every time
+	// the jenkins.dev/object-storage-backup annotation gets a new value. It requires persistence to be enabled.
+	ObjectStorage *JenkinsObjectStorage `json:"objectStorage,omitempty"`
+	// Ingress defines the Ingress exposing the instance. An Ingress is always created when the Route API is not
+	// available, on OpenShift it is only created when enabled.
+	Ingress *JenkinsIngress `json:"ingress,omitempty"`
+	// Route customizes the Route exposing the instance on OpenShift
+	Route *JenkinsRoute `json:"route,omitempty"`
+	// Authentication is how users log in to Jenkins, openshiftOAuth by default. With builtin, the operator
+	// generates the credentials of an admin user in the <name>-admin Secret, which are rotated every time the
+	// jenkins.dev/rotate-admin-credentials annotation gets a new value.
+	// +kubebuilder:validation:Enum=openshiftOAuth;builtin
+	Authentication JenkinsAuthenticationMode `json:"authentication,omitempty"`
+	// ServiceAccount customizes the ServiceAccount of the instance and the role granted to it, the edit
+	// ClusterRole in the namespace of the instance by default
+	ServiceAccount *JenkinsServiceAccount `json:"serviceAccount,omitempty"`
+}
+
+// JenkinsServiceAccount customizes the ServiceAccount Jenkins and its agents run as
+type JenkinsServiceAccount struct {
+	// Role is bound to the ServiceAccount in the namespace of the instance and in the additional namespaces
+	Role *JenkinsRoleRef `json:"role,omitempty"`
+	// Namespaces lists additional namespaces where the role is bound, such as the targets of the pipelines
+	Namespaces []string `json:"namespaces,omitempty"`
+	// NamespaceSelector selects additional namespaces by label where the role is bound
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Annotations are added to the ServiceAccount
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// JenkinsRoleRef references the role bound to the ServiceAccount of the instance
+type JenkinsRoleRef struct {
+	// Kind is ClusterRole or Role. A Role must exist in every namespace where it is bound.
+	// +kubebuilder:validation:Enum=ClusterRole;Role
+	Kind string `json:"kind"`
+	// Name is the name of the role
+	Name string `json:"name"`
+}
+
+// JenkinsAuthenticationMode is how users log in to a Jenkins instance
+type JenkinsAuthenticationMode string
+
+const (
+	// JenkinsAuthenticationOpenShiftOAuth logs users in with their OpenShift account through the OpenShift
+	// Login plugin. This is the default.
+	JenkinsAuthenticationOpenShiftOAuth JenkinsAuthenticationMode = "openshiftOAuth"
+	// JenkinsAuthenticationBuiltin uses the user database of Jenkins, holding the admin user generated by the
+	// operator
+	JenkinsAuthenticationBuiltin JenkinsAuthenticationMode = "builtin"
+)
+
+// JenkinsRoute customizes the Route exposing the Jenkins web service. Unset fields keep their default value:
+// an edge terminated Route with a generated host, redirecting insecure requests.
+type JenkinsRoute struct {
+	// Host is the host name under which Jenkins is exposed, generated by the router when it is empty
+	Host string `json:"host,omitempty"`
+	// Termination is where TLS is terminated, edge by default. With reencrypt, Jenkins must serve TLS on its
+	// web port.
+	// +kubebuilder:validation:Enum=edge;reencrypt
+	Termination JenkinsRouteTermination `json:"termination,omitempty"`
+	// CertificateSecret is the name of a Secret of the namespace holding the certificate served by the router:
+	// tls.crt and tls.key, and optionally ca.crt for the CA chain and destination-ca.crt for the CA of the
+	// certificate of Jenkins with reencrypt. The certificate of the router is used when it is empty.
+	CertificateSecret string `json:"certificateSecret,omitempty"`
+	// Annotations are added to the Route, e.g. haproxy.router.openshift.io/timeout
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Labels are added to the Route, e.g. to select the routers of a shard
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// JenkinsRouteTermination is where TLS is terminated for the Route of an instance
+type JenkinsRouteTermination string
+
+const (
+	// JenkinsRouteTerminationEdge terminates TLS at the router, which forwards plain HTTP to Jenkins
+	JenkinsRouteTerminationEdge JenkinsRouteTermination = "edge"
+	// JenkinsRouteTerminationReencrypt terminates TLS at the router, which opens a new TLS connection to Jenkins
+	JenkinsRouteTerminationReencrypt JenkinsRouteTermination = "reencrypt"
+)
+
+// JenkinsIngress defines the Ingress exposing the Jenkins web service
+type JenkinsIngress struct {
+	// Enabled creates the Ingress even when the Route API is available
+	Enabled bool `json:"enabled,omitempty"`
+	// Host is the host name under which Jenkins is exposed, every host is matched when it is empty
+	Host string `json:"host,omitempty"`
+	// IngressClassName selects the ingress controller serving the Ingress, through the kubernetes.io/ingress.class
+	// annotation
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// TLSSecretName is the name of a Secret of the namespace holding the certificate of the host, TLS is
+	// terminated by the ingress controller when it is set
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+	// Annotations are added to the Ingress, e.g. to configure the ingress controller
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// JenkinsObjectStorage defines a bucket of an S3 compatible object storage, e.g. AWS S3 or MinIO
+type JenkinsObjectStorage struct {
+	// Endpoint is the URL of the object storage, e.g. https://s3.amazonaws.com or http://minio.minio.svc:9000
+	Endpoint string `json:"endpoint"`
+	// Region is the region of the bucket, us-east-1 by default
+	Region string `json:"region,omitempty"`
+	// Bucket is the name of the bucket holding the archives
+	Bucket string `json:"bucket"`
+	// Prefix is prepended to the keys of the archives, <namespace>/<name>/ by default
+	Prefix string `json:"prefix,omitempty"`
+	// CredentialsSecret is the name of a Secret of the namespace holding the AWS_ACCESS_KEY_ID and
+	// AWS_SECRET_ACCESS_KEY keys
+	CredentialsSecret string `json:"credentialsSecret"`
+	// Excludes lists patterns of the paths of JENKINS_HOME to skip, e.g. workspace
+	Excludes []string `json:"excludes,omitempty"`
+}
+
+// JenkinsBackupSchedule defines the backups of JENKINS_HOME run on a schedule
+type JenkinsBackupSchedule struct {
+	// Schedule is the cron expression of the backups, e.g. "0 2 * * *" for every night at 2am
+	Schedule string `json:"schedule"`
+	// Destination defines where the archives are stored
+	Destination JenkinsBackupDestination `json:"destination"`
+	// Excludes lists tar patterns of the paths of JENKINS_HOME to skip, e.g. workspace
+	Excludes []string `json:"excludes,omitempty"`
+	// Retention defines the archives pruned after each backup
+	Retention JenkinsBackupRetention `json:"retention,omitempty"`
+}
+
+// JenkinsBackupRetention defines how long the archives of the scheduled backups are kept. The most recent
+// archive is never pruned.
+type JenkinsBackupRetention struct {
+	// MaxCount is the number of archives kept
+	MaxCount *int32 `json:"maxCount,omitempty"`
+	// MaxAge is the age after which an archive is pruned, e.g. 336h for 14 days
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+}
+
+// JenkinsConfigurationAsCode references ConfigMaps and Secrets of the namespace whose keys are Jenkins
+// Configuration as Code YAML files. The configuration is reloaded by Jenkins when their content changes.
+type JenkinsConfigurationAsCode struct {
+	ConfigMaps []corev1.LocalObjectReference `json:"configMaps,omitempty"`
+	Secrets    []corev1.LocalObjectReference `json:"secrets,omitempty"`
+}
+
+// JenkinsImageSource defines the image of the Jenkins container. Only one of the fields can be set.
+type JenkinsImageSource struct {
+	// Reference is a pullable image reference, e.g. quay.io/openshift/origin-jenkins:latest
+	Reference string `json:"reference,omitempty"`
+	// ImageStreamTag is an ImageStreamTag (name:tag) resolved to the image it currently points to
+	ImageStreamTag string `json:"imageStreamTag,omitempty"`
+	// ImageStreamNamespace is the namespace of the ImageStreamTag, the namespace of the instance by default
+	ImageStreamNamespace string `json:"imageStreamNamespace,omitempty"`
+}
+
+// JenkinsProbe overrides the timings of a probe of the Jenkins container. Unset fields keep their default value.
+type JenkinsProbe struct {
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	TimeoutSeconds      *int32 `json:"timeoutSeconds,omitempty"`
+	PeriodSeconds       *int32 `json:"periodSeconds,omitempty"`
+	FailureThreshold    *int32 `json:"failureThreshold,omitempty"`
+}
+
+// JenkinsDeletionPolicy describes how the data of a Jenkins instance is handled when the instance is deleted
+type JenkinsDeletionPolicy string
+
+const (
+	// JenkinsDeletionPolicyDelete deletes the persistent volume claim with the instance. This is the default.
+	JenkinsDeletionPolicyDelete JenkinsDeletionPolicy = "Delete"
+	// JenkinsDeletionPolicyRetain keeps the persistent volume claim after the instance is deleted
+	JenkinsDeletionPolicyRetain JenkinsDeletionPolicy = "Retain"
+	// JenkinsDeletionPolicySnapshot archives JENKINS_HOME in a separate persistent volume claim
+	// before the instance and its persistent volume claim are deleted
+	JenkinsDeletionPolicySnapshot JenkinsDeletionPolicy = "Snapshot"
+)
+
 // JenkinsStatus defines the observed state of Jenkins
 // +k8s:openapi-gen=true
 type JenkinsStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
-	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
+	Phase              JenkinsPhase            `json:"phase,omitempty"`      // High level summary of the instance state
+	Conditions         []JenkinsCondition      `json:"conditions,omitempty"` // Detailed conditions of the instance
+	ObservedGeneration int64                   `json:"observedGeneration,omitempty"`
+	URL                string                  `json:"url,omitempty"`   // URL under which the Jenkins instance is exposed
+	Image              string                  `json:"image,omitempty"` // Image resolved for the Jenkins container
+	Resources          JenkinsManagedResources `json:"resources,omitempty"`
+	// ConfigurationAsCode reports the Jenkins Configuration as Code applied to the instance
+	ConfigurationAsCode *JenkinsConfigurationAsCodeStatus `json:"configurationAsCode,omitempty"`
+	// Backup reports the last runs of the scheduled backups
+	Backup *JenkinsBackupScheduleStatus `json:"backup,omitempty"`
+	// ObjectStorage reports the backups to the object storage and the archives available for a restore
+	ObjectStorage *JenkinsObjectStorageStatus `json:"objectStorage,omitempty"`
+	// Persistence reports the claim holding JENKINS_HOME and the progress of its expansion
+	Persistence *JenkinsPersistenceStatus `json:"persistence,omitempty"`
+}
+
+// JenkinsObjectStorageStatus reports the last backup to the object storage and the most recent archives
+type JenkinsObjectStorageStatus struct {
+	LastRequest  string            `json:"lastRequest,omitempty"`  // Value of the annotation of the last backup started
+	LastBackup   *JenkinsBackupRun `json:"lastBackup,omitempty"`   // Last backup started, in progress until it completes
+	Archives     []JenkinsArchive  `json:"archives,omitempty"`     // Most recent archives, the newest first
+	LastListTime *metav1.Time      `json:"lastListTime,omitempty"` // Time at which the archives were listed
+	ListError    string            `json:"listError,omitempty"`    // Reason why the archives could not be listed
+}
+
+// JenkinsArchive describes an archive of JENKINS_HOME stored in the object storage
+type JenkinsArchive struct {
+	Key          string       `json:"key"`
+	Size         int64        `json:"size,omitempty"`
+	LastModified *metav1.Time `json:"lastModified,omitempty"`
+}
+
+// JenkinsBackupScheduleStatus reports the last successful and last failed scheduled backups
+type JenkinsBackupScheduleStatus struct {
+	LastSuccessful *JenkinsBackupRun `json:"lastSuccessful,omitempty"`
+	LastFailed     *JenkinsBackupRun `json:"lastFailed,omitempty"`
+}
+
+// JenkinsBackupRun describes a run of a scheduled backup
+type JenkinsBackupRun struct {
+	Job            string       `json:"job"` // Name of the job which ran the backup
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	Archive        string       `json:"archive,omitempty"`  // Path of the archive in the destination
+	Size           int64        `json:"size,omitempty"`     // Size of the archive in bytes
+	Checksum       string       `json:"checksum,omitempty"` // Checksum of the archive, as sha256:<hex>
+	Message        string       `json:"message,omitempty"`  // Reason of the failure
+}
+
+// JenkinsConfigurationAsCodeStatus reports the state of the Jenkins Configuration as Code of an instance
+type JenkinsConfigurationAsCodeStatus struct {
+	AppliedHash    string       `json:"appliedHash,omitempty"`    // Hash of the configuration loaded by Jenkins
+	PendingHash    string       `json:"pendingHash,omitempty"`    // Hash of a configuration waiting to be reloaded
+	PendingSince   *metav1.Time `json:"pendingSince,omitempty"`   // Time at which the pending configuration was observed
+	LastReloadTime *metav1.Time `json:"lastReloadTime,omitempty"` // Time of the last successful reload
+	ReloadError    string       `json:"reloadError,omitempty"`    // Error returned by the last reload
+}
+
+// JenkinsPhase is a label for the condition of a Jenkins instance at the current time
+type JenkinsPhase string
+
+const (
+	// JenkinsPhaseProvisioning means the managed resources are being created or are not ready yet
+	JenkinsPhaseProvisioning JenkinsPhase = "Provisioning"
+	// JenkinsPhaseReady means the Jenkins instance is available
+	JenkinsPhaseReady JenkinsPhase = "Ready"
+	// JenkinsPhaseFailed means the operator could not bring the instance to the desired state
+	JenkinsPhaseFailed JenkinsPhase = "Failed"
+	// JenkinsPhaseTerminating means the instance is being deleted and its finalizer is running
+	JenkinsPhaseTerminating JenkinsPhase = "Terminating"
+)
+
+// JenkinsConditionType is a valid value for JenkinsCondition.Type
+type JenkinsConditionType string
+
+const (
+	// JenkinsAvailable means the Jenkins workload has at least one ready replica
+	JenkinsAvailable JenkinsConditionType = "Available"
+	// JenkinsProgressing means the Jenkins workload is being rolled out
+	JenkinsProgressing JenkinsConditionType = "Progressing"
+	// JenkinsDegraded means the operator failed to reconcile one of the managed resources
+	JenkinsDegraded JenkinsConditionType = "Degraded"
+	// JenkinsPersistenceReady means the volume holding JENKINS_HOME is ready to be used
+	JenkinsPersistenceReady JenkinsConditionType = "PersistenceReady"
+	// JenkinsRouteAdmitted means the Route exposing the instance has been admitted by a router
+	JenkinsRouteAdmitted JenkinsConditionType = "RouteAdmitted"
+	// JenkinsIngressAdmitted means the Ingress exposing the instance has been given an address by an ingress controller
+	JenkinsIngressAdmitted JenkinsConditionType = "IngressAdmitted"
+	// JenkinsMigrating means the instance is switching between a Deployment and a DeploymentConfig
+	JenkinsMigrating JenkinsConditionType = "Migrating"
+	// JenkinsFinalizing means the instance is being deleted and the deletion policy is being applied
+	JenkinsFinalizing JenkinsConditionType = "Finalizing"
+	// JenkinsValid means the spec of the resource passed the validation of the operator
+	JenkinsValid JenkinsConditionType = "Valid"
+)
+
+// JenkinsCondition describes the state of a Jenkins instance at a certain point
+type JenkinsCondition struct {
+	Type               JenkinsConditionType   `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
+
+// JenkinsManagedResources holds the names of the resources managed for a Jenkins instance
+type JenkinsManagedResources struct {
+	Deployment            string   `json:"deployment,omitempty"`
+	DeploymentConfig      string   `json:"deploymentConfig,omitempty"`
+	Services              []string `json:"services,omitempty"`
+	PersistentVolumeClaim string   `json:"persistentVolumeClaim,omitempty"`
+	ServiceAccount        string   `json:"serviceAccount,omitempty"`
+	RoleBinding           string   `json:"roleBinding,omitempty"`
+	Route                 string   `json:"route,omitempty"`
+	Ingress               string   `json:"ingress,omitempty"`
+	AdminSecret           string   `json:"adminSecret,omitempty"`
+	// BoundNamespaces lists the additional namespaces where the role of the ServiceAccount is bound
+	BoundNamespaces []string `json:"boundNamespaces,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -38,10 +351,54 @@ type Jenkins struct {
 	Status JenkinsStatus `json:"status,omitempty"`
 }
 
-// Defines Persistence of Jenkins Instance
+// JenkinsPersistence configures the claim holding JENKINS_HOME
 type JenkinsPersistence struct {
-	Enabled bool   `json:"enabled"`
-	Size    string `json:"size,omitempty"`
+	// Enabled stores JENKINS_HOME in a persistent volume claim
+	Enabled bool `json:"enabled"`
+	// Size is the requested size of the claim. A claim whose StorageClass allows volume expansion is expanded
+	// when the size grows.
+	Size string `json:"size,omitempty"`
+	// StorageClassName is the StorageClass of the claim, the default StorageClass when it is not set
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// AccessModes are the access modes of the claim, ReadWriteOnce by default
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	// VolumeMode is the volume mode of the claim
+	VolumeMode *corev1.PersistentVolumeMode `json:"volumeMode,omitempty"`
+	// Selector selects the persistent volumes which can be bound to the claim
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Labels are added to the claim
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are added to the claim
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// ExistingClaim is the name of a claim of the namespace used instead of a claim created by the operator.
+	// It is neither owned nor modified by the operator, and cannot be combined with the other claim fields.
+	ExistingClaim string `json:"existingClaim,omitempty"`
+}
+
+// JenkinsResizeState is the progress of the expansion of the claim holding JENKINS_HOME
+type JenkinsResizeState string
+
+const (
+	// JenkinsResizeInProgress means the volume is being expanded
+	JenkinsResizeInProgress JenkinsResizeState = "Resizing"
+	// JenkinsResizeFileSystemPending means the file system is expanded when Jenkins is restarted
+	JenkinsResizeFileSystemPending JenkinsResizeState = "FileSystemResizePending"
+	// JenkinsResizeNotSupported means the StorageClass of the claim does not allow expanding it
+	JenkinsResizeNotSupported JenkinsResizeState = "NotSupported"
+)
+
+// JenkinsPersistenceStatus reports the claim holding JENKINS_HOME
+type JenkinsPersistenceStatus struct {
+	// ClaimName is the name of the claim holding JENKINS_HOME
+	ClaimName string `json:"claimName"`
+	// RequestedSize is the size requested on the claim
+	RequestedSize string `json:"requestedSize,omitempty"`
+	// Capacity is the size of the volume bound to the claim
+	Capacity string `json:"capacity,omitempty"`
+	// Resize is the progress of the expansion of the claim, empty when the claim is not being expanded
+	Resize JenkinsResizeState `json:"resize,omitempty"`
+	// Message details the progress of the expansion
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -53,9 +410,6 @@ type JenkinsList struct {
 	Items           []Jenkins `json:"items"`
 }
 
-type JenkinsCRDDescriptor struct {
-}
-
 func init() {
 	SchemeBuilder.Register(&Jenkins{}, &JenkinsList{})
 }
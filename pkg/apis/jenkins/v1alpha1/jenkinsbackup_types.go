@@ -0,0 +1,84 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// JenkinsBackupSpec defines the desired state of JenkinsBackup
+// +k8s:openapi-gen=true
+type JenkinsBackupSpec struct {
+	// JenkinsRef is the name of the Jenkins instance of the namespace whose JENKINS_HOME is archived
+	JenkinsRef string `json:"jenkinsRef"`
+	// Destination defines where the archive is stored
+	Destination JenkinsBackupDestination `json:"destination"`
+	// Includes lists the paths of JENKINS_HOME to archive, shell patterns relative to JENKINS_HOME are
+	// accepted. The whole JENKINS_HOME is archived by default.
+	Includes []string `json:"includes,omitempty"`
+	// Excludes lists tar patterns of the paths to skip, e.g. workspace or jobs/*/builds/*/archive
+	Excludes []string `json:"excludes,omitempty"`
+}
+
+// JenkinsBackupDestination defines where a backup archive is stored
+type JenkinsBackupDestination struct {
+	// PersistentVolumeClaim is the name of a claim of the namespace receiving the archive. Archives are stored
+	// in a directory named after the Jenkins instance.
+	PersistentVolumeClaim string `json:"persistentVolumeClaim"`
+}
+
+// JenkinsBackupStatus defines the observed state of JenkinsBackup
+// +k8s:openapi-gen=true
+type JenkinsBackupStatus struct {
+	Phase          JenkinsBackupPhase `json:"phase,omitempty"`
+	Message        string             `json:"message,omitempty"`  // Details about the current phase
+	Archive        string             `json:"archive,omitempty"`  // Path of the archive in the destination
+	Size           int64              `json:"size,omitempty"`     // Size of the archive in bytes
+	Checksum       string             `json:"checksum,omitempty"` // Checksum of the archive, as sha256:<hex>
+	StartTime      *metav1.Time       `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time       `json:"completionTime,omitempty"`
+}
+
+// JenkinsBackupPhase is a label for the state of a backup or restore
+type JenkinsBackupPhase string
+
+const (
+	// JenkinsBackupPhasePending means the backup or restore has not started yet
+	JenkinsBackupPhasePending JenkinsBackupPhase = "Pending"
+	// JenkinsBackupPhaseRunning means the archive is being created or restored
+	JenkinsBackupPhaseRunning JenkinsBackupPhase = "Running"
+	// JenkinsBackupPhaseCompleted means the backup or restore succeeded
+	JenkinsBackupPhaseCompleted JenkinsBackupPhase = "Completed"
+	// JenkinsBackupPhaseFailed means the backup or restore failed and will not be retried
+	JenkinsBackupPhaseFailed JenkinsBackupPhase = "Failed"
+)
+
+// IsFinished returns true when the backup or restore reached a terminal phase
+func (p JenkinsBackupPhase) IsFinished() bool {
+	return p == JenkinsBackupPhaseCompleted || p == JenkinsBackupPhaseFailed
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JenkinsBackup is the Schema for the jenkinsbackups API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=jenkinsbackups,scope=Namespaced
+type JenkinsBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   JenkinsBackupSpec   `json:"spec,omitempty"`
+	Status JenkinsBackupStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JenkinsBackupList contains a list of JenkinsBackup
+type JenkinsBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []JenkinsBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&JenkinsBackup{}, &JenkinsBackupList{})
+}
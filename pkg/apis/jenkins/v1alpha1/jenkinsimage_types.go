@@ -4,28 +4,53 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
-// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
-// Defines Jenkins Plugin structure
+// JenkinsPlugin is a plugin installed in a JenkinsImage
 type JenkinsPlugin struct {
-	Name	   string `json:"name"`
-	Version    string `json:"version,omitempty"`
+	// Name is the short name of the plugin, e.g. git
+	Name string `json:"name"`
+	// Version is the version of the plugin, the latest version by default
+	Version string `json:"version,omitempty"`
 }
 
-
 // JenkinsImageSpec defines the desired state of JenkinsImage
 type JenkinsImageSpec struct {
-	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
-	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
-	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
-	Plugins []JenkinsPlugin `json:"plugins"` // Plugins list
+	// Plugins are installed in the image on top of the plugins of the OpenShift Jenkins image
+	Plugins []JenkinsPlugin `json:"plugins"`
 }
 
 // JenkinsImageStatus defines the observed state of JenkinsImage
 type JenkinsImageStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
-	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
+	Phase       JenkinsImagePhase   `json:"phase,omitempty"`       // Phase of the latest build
+	PluginsHash string              `json:"pluginsHash,omitempty"` // Hash of the plugins.txt used by the latest build
+	LatestBuild string              `json:"latestBuild,omitempty"` // Name of the latest build started for the image
+	Builds      []JenkinsImageBuild `json:"builds,omitempty"`      // Most recent builds of the image, newest first
+	Image       string              `json:"image,omitempty"`       // Pullspec by digest of the latest image built successfully
+	ImageDigest string              `json:"imageDigest,omitempty"` // Digest of the latest image built successfully
+	Conditions  []JenkinsCondition  `json:"conditions,omitempty"`  // Detailed conditions of the image
+}
+
+// JenkinsImagePhase is a label for the state of the latest build of a JenkinsImage
+type JenkinsImagePhase string
+
+const (
+	// JenkinsImagePhasePending means the latest build is not started yet
+	JenkinsImagePhasePending JenkinsImagePhase = "Pending"
+	// JenkinsImagePhaseBuilding means the latest build is running
+	JenkinsImagePhaseBuilding JenkinsImagePhase = "Building"
+	// JenkinsImagePhaseComplete means the latest build pushed the image
+	JenkinsImagePhaseComplete JenkinsImagePhase = "Complete"
+	// JenkinsImagePhaseFailed means the latest build failed, errored or was cancelled
+	JenkinsImagePhaseFailed JenkinsImagePhase = "Failed"
+)
+
+// JenkinsImageBuild summarizes a build of a JenkinsImage
+type JenkinsImageBuild struct {
+	Name           string            `json:"name"`
+	Phase          JenkinsImagePhase `json:"phase,omitempty"`
+	StartTime      *metav1.Time      `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time      `json:"completionTime,omitempty"`
+	Reason         string            `json:"reason,omitempty"`  // Reason of the build failure
+	Message        string            `json:"message,omitempty"` // Details about the build failure
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -0,0 +1,53 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// JenkinsRestoreSpec defines the desired state of JenkinsRestore
+// +k8s:openapi-gen=true
+type JenkinsRestoreSpec struct {
+	// JenkinsRef is the name of the Jenkins instance of the namespace whose JENKINS_HOME is replaced
+	JenkinsRef string `json:"jenkinsRef"`
+	// BackupRef is the name of the completed JenkinsBackup of the namespace to restore
+	BackupRef string `json:"backupRef"`
+}
+
+// JenkinsRestoreStatus defines the observed state of JenkinsRestore
+// +k8s:openapi-gen=true
+type JenkinsRestoreStatus struct {
+	Phase          JenkinsBackupPhase `json:"phase,omitempty"`
+	Message        string             `json:"message,omitempty"`  // Details about the current phase
+	Replicas       *int32             `json:"replicas,omitempty"` // Replicas of Jenkins before it was stopped
+	Size           int64              `json:"size,omitempty"`     // Size of the restored archive in bytes
+	Checksum       string             `json:"checksum,omitempty"` // Checksum of the restored archive, as sha256:<hex>
+	StartTime      *metav1.Time       `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time       `json:"completionTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JenkinsRestore is the Schema for the jenkinsrestores API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=jenkinsrestores,scope=Namespaced
+type JenkinsRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   JenkinsRestoreSpec   `json:"spec,omitempty"`
+	Status JenkinsRestoreStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JenkinsRestoreList contains a list of JenkinsRestore
+type JenkinsRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []JenkinsRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&JenkinsRestore{}, &JenkinsRestoreList{})
+}
@@ -11,9 +11,15 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.Jenkins":       schema_pkg_apis_jenkins_v1alpha1_Jenkins(ref),
-		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsSpec":   schema_pkg_apis_jenkins_v1alpha1_JenkinsSpec(ref),
-		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsStatus": schema_pkg_apis_jenkins_v1alpha1_JenkinsStatus(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.Jenkins":              schema_pkg_apis_jenkins_v1alpha1_Jenkins(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackup":        schema_pkg_apis_jenkins_v1alpha1_JenkinsBackup(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackupSpec":    schema_pkg_apis_jenkins_v1alpha1_JenkinsBackupSpec(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackupStatus":  schema_pkg_apis_jenkins_v1alpha1_JenkinsBackupStatus(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsRestore":       schema_pkg_apis_jenkins_v1alpha1_JenkinsRestore(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsRestoreSpec":   schema_pkg_apis_jenkins_v1alpha1_JenkinsRestoreSpec(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsRestoreStatus": schema_pkg_apis_jenkins_v1alpha1_JenkinsRestoreStatus(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsSpec":          schema_pkg_apis_jenkins_v1alpha1_JenkinsSpec(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsStatus":        schema_pkg_apis_jenkins_v1alpha1_JenkinsStatus(ref),
 	}
 }
 
@@ -62,6 +68,297 @@ func schema_pkg_apis_jenkins_v1alpha1_Jenkins(ref common.ReferenceCallback) comm
 	}
 }
 
+func schema_pkg_apis_jenkins_v1alpha1_JenkinsBackup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "JenkinsBackup is the Schema for the jenkinsbackups API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackupSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackupStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackupSpec", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackupStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_jenkins_v1alpha1_JenkinsBackupSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "JenkinsBackupSpec defines the desired state of JenkinsBackup",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"jenkinsRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "JenkinsRef is the name of the Jenkins instance of the namespace whose JENKINS_HOME is archived",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"destination": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Destination defines where the archive is stored",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackupDestination"),
+						},
+					},
+					"includes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Includes lists the paths of JENKINS_HOME to archive, shell patterns relative to JENKINS_HOME are accepted. The whole JENKINS_HOME is archived by default.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"excludes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Excludes lists tar patterns of the paths to skip, e.g. workspace or jobs/*/builds/*/archive",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"jenkinsRef", "destination"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackupDestination"},
+	}
+}
+
+func schema_pkg_apis_jenkins_v1alpha1_JenkinsBackupStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "JenkinsBackupStatus defines the observed state of JenkinsBackup",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"archive": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Details about the current phase",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path of the archive in the destination",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"checksum": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Size of the archive in bytes",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Checksum of the archive, as sha256:<hex>",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"completionTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_jenkins_v1alpha1_JenkinsRestore(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "JenkinsRestore is the Schema for the jenkinsrestores API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsRestoreSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsRestoreStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsRestoreSpec", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsRestoreStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_jenkins_v1alpha1_JenkinsRestoreSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "JenkinsRestoreSpec defines the desired state of JenkinsRestore",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"jenkinsRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "JenkinsRef is the name of the Jenkins instance of the namespace whose JENKINS_HOME is replaced",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"backupRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BackupRef is the name of the completed JenkinsBackup of the namespace to restore",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"jenkinsRef", "backupRef"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_jenkins_v1alpha1_JenkinsRestoreStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "JenkinsRestoreStatus defines the observed state of JenkinsRestore",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"replicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Details about the current phase",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Replicas of Jenkins before it was stopped",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"checksum": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Size of the restored archive in bytes",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Checksum of the restored archive, as sha256:<hex>",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"completionTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_jenkins_v1alpha1_JenkinsSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -71,23 +368,170 @@ func schema_pkg_apis_jenkins_v1alpha1_JenkinsSpec(ref common.ReferenceCallback)
 				Properties: map[string]spec.Schema{
 					"persistence": {
 						SchemaProps: spec.SchemaProps{
-							Description: "INSERT ADDITIONAL SPEC FIELDS - desired state of cluster Important: Run \"operator-sdk generate k8s\" to regenerate code after modifying this file Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html",
+							Description: "Persistence configures the volume holding JENKINS_HOME, an emptyDir when it is not enabled",
 							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsPersistence"),
 						},
 					},
 					"useDeploymentConfig": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Configure Jenkins Persistence",
+							Description: "UseDeploymentConfig runs Jenkins in a DeploymentConfig instead of a Deployment, it requires the OpenShift apps API",
 							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
+					"deletionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionPolicy defines what happens to JENKINS_HOME when the Jenkins instance is deleted",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image defines the image of the Jenkins container, the OpenShift Jenkins image by default",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsImageSource"),
+						},
+					},
+					"jenkinsImageRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "JenkinsImageRef is the name of a JenkinsImage of the namespace whose latest build is used as the image of the Jenkins container. Jenkins is rolled out again when a new image is built. Cannot be used with Image.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources replaces the default resource requirements (1Gi memory limit) of the Jenkins container",
+							Ref:         ref("k8s.io/api/core/v1.ResourceRequirements"),
+						},
+					},
+					"livenessProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LivenessProbe overrides the timings of the liveness probe of the Jenkins container",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsProbe"),
+						},
+					},
+					"readinessProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadinessProbe overrides the timings of the readiness probe of the Jenkins container",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsProbe"),
+						},
+					},
+					"javaOpts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "JavaOpts is passed to the Jenkins JVM through the JAVA_OPTS environment variable",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"jenkinsOpts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "JenkinsOpts is passed to Jenkins through the JENKINS_OPTS environment variable",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"env": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Env holds additional environment variables of the Jenkins container",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.EnvVar"),
+									},
+								},
+							},
+						},
+					},
+					"envFrom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EnvFrom holds additional sources of environment variables of the Jenkins container",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.EnvFromSource"),
+									},
+								},
+							},
+						},
+					},
+					"volumes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Volumes holds additional volumes of the Jenkins pod",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Volume"),
+									},
+								},
+							},
+						},
+					},
+					"volumeMounts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VolumeMounts holds additional mounts of the Jenkins container, of the volumes defined in Volumes",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.VolumeMount"),
+									},
+								},
+							},
+						},
+					},
+					"configurationAsCode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigurationAsCode references the Jenkins Configuration as Code YAML files applied to the instance",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsConfigurationAsCode"),
+						},
+					},
+					"backup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backup schedules backups of JENKINS_HOME, it requires persistence to be enabled",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackupSchedule"),
+						},
+					},
+					"objectStorage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObjectStorage defines an S3 compatible object storage where JENKINS_HOME is archived on demand, every time the jenkins.dev/object-storage-backup annotation gets a new value. It requires persistence to be enabled.",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsObjectStorage"),
+						},
+					},
+					"ingress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ingress defines the Ingress exposing the instance. An Ingress is always created when the Route API is not available, on OpenShift it is only created when enabled.",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsIngress"),
+						},
+					},
+					"route": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Route customizes the Route exposing the instance on OpenShift",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsRoute"),
+						},
+					},
+					"authentication": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Authentication is how users log in to Jenkins, openshiftOAuth by default. With builtin, the operator generates the credentials of an admin user in the <name>-admin Secret, which are rotated every time the jenkins.dev/rotate-admin-credentials annotation gets a new value.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"serviceAccount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServiceAccount customizes the ServiceAccount of the instance and the role granted to it, the edit ClusterRole in the namespace of the instance by default",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsServiceAccount"),
+						},
+					},
 				},
 				Required: []string{"persistence"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsPersistence"},
+			"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackupSchedule", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsConfigurationAsCode", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsImageSource", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsIngress", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsObjectStorage", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsPersistence", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsProbe", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsRoute", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsServiceAccount", "k8s.io/api/core/v1.EnvFromSource", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/api/core/v1.Volume", "k8s.io/api/core/v1.VolumeMount"},
 	}
 }
 
@@ -97,7 +541,80 @@ func schema_pkg_apis_jenkins_v1alpha1_JenkinsStatus(ref common.ReferenceCallback
 			SchemaProps: spec.SchemaProps{
 				Description: "JenkinsStatus defines the observed state of Jenkins",
 				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "High level summary of the instance state",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsCondition"),
+									},
+								},
+							},
+						},
+					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Detailed conditions of the instance",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL under which the Jenkins instance is exposed",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image resolved for the Jenkins container",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsManagedResources"),
+						},
+					},
+					"configurationAsCode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigurationAsCode reports the Jenkins Configuration as Code applied to the instance",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsConfigurationAsCodeStatus"),
+						},
+					},
+					"backup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backup reports the last runs of the scheduled backups",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackupScheduleStatus"),
+						},
+					},
+					"objectStorage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObjectStorage reports the backups to the object storage and the archives available for a restore",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsObjectStorageStatus"),
+						},
+					},
+					"persistence": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Persistence reports the claim holding JENKINS_HOME and the progress of its expansion",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsPersistenceStatus"),
+						},
+					},
+				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsBackupScheduleStatus", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsCondition", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsConfigurationAsCodeStatus", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsManagedResources", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsObjectStorageStatus", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1.JenkinsPersistenceStatus"},
 	}
 }
@@ -0,0 +1,262 @@
+package v1beta1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// v1alpha1 is the version stored in the cluster and reconciled by the operator. The objects are converted from
+// and to v1alpha1 only.
+//
+// The spec of one version cannot always be represented in the other one, e.g. a Deployment workload is
+// converted to useDeploymentConfig: false, which reads back as the default workload kind. The spec of the
+// source object is then kept in an annotation of the converted object, and restored when the object is
+// converted back as long as its spec was not modified in the meantime.
+//
+// The fields of the serialized source object which are not part of its version, e.g. set before the operator
+// was upgraded, are kept in an annotation of the converted object by the conversion webhook, and restored when
+// the object is converted back.
+const (
+	// V1alpha1SpecAnnotation holds the v1alpha1 spec of a v1beta1 object converted from v1alpha1
+	V1alpha1SpecAnnotation = "jenkins.dev/v1alpha1-spec"
+	// V1beta1SpecAnnotation holds the v1beta1 spec of a v1alpha1 object converted from v1beta1
+	V1beta1SpecAnnotation = "jenkins.dev/v1beta1-spec"
+	// V1alpha1FieldsAnnotation holds the unknown fields of a v1alpha1 object converted to v1beta1
+	V1alpha1FieldsAnnotation = "jenkins.dev/v1alpha1-fields"
+	// V1beta1FieldsAnnotation holds the unknown fields of a v1beta1 object converted to v1alpha1
+	V1beta1FieldsAnnotation = "jenkins.dev/v1beta1-fields"
+)
+
+// ConvertTo converts this Jenkins to v1alpha1
+func (src *Jenkins) ConvertTo(dst *v1alpha1.Jenkins) error {
+	dst.TypeMeta = metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "Jenkins"}
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	spec, err := jenkinsSpecToV1alpha1(&src.Spec)
+	if err != nil {
+		return err
+	}
+	dst.Spec = spec
+	saved := &v1alpha1.JenkinsSpec{}
+	found, err := popAnnotation(&dst.ObjectMeta, V1alpha1SpecAnnotation, saved)
+	if err != nil {
+		return err
+	}
+	if found {
+		restored, err := jenkinsSpecFromV1alpha1(saved)
+		if err != nil {
+			return err
+		}
+		if sameJSON(restored, &src.Spec) {
+			dst.Spec = *saved
+		}
+	}
+	roundTripped, err := jenkinsSpecFromV1alpha1(&dst.Spec)
+	if err != nil {
+		return err
+	}
+	if !sameJSON(roundTripped, &src.Spec) {
+		if err := setAnnotation(&dst.ObjectMeta, V1beta1SpecAnnotation, &src.Spec); err != nil {
+			return err
+		}
+	}
+	return convertJSON(&src.Status, &dst.Status)
+}
+
+// ConvertFrom converts a v1alpha1 Jenkins to this version
+func (dst *Jenkins) ConvertFrom(src *v1alpha1.Jenkins) error {
+	dst.TypeMeta = metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "Jenkins"}
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	spec, err := jenkinsSpecFromV1alpha1(&src.Spec)
+	if err != nil {
+		return err
+	}
+	dst.Spec = spec
+	saved := &JenkinsSpec{}
+	found, err := popAnnotation(&dst.ObjectMeta, V1beta1SpecAnnotation, saved)
+	if err != nil {
+		return err
+	}
+	if found {
+		restored, err := jenkinsSpecToV1alpha1(saved)
+		if err != nil {
+			return err
+		}
+		if sameJSON(restored, &src.Spec) {
+			dst.Spec = *saved
+		}
+	}
+	roundTripped, err := jenkinsSpecToV1alpha1(&dst.Spec)
+	if err != nil {
+		return err
+	}
+	if !sameJSON(roundTripped, &src.Spec) {
+		if err := setAnnotation(&dst.ObjectMeta, V1alpha1SpecAnnotation, &src.Spec); err != nil {
+			return err
+		}
+	}
+	return convertJSON(&src.Status, &dst.Status)
+}
+
+// ConvertTo converts this JenkinsImage to v1alpha1
+func (src *JenkinsImage) ConvertTo(dst *v1alpha1.JenkinsImage) error {
+	dst.TypeMeta = metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "JenkinsImage"}
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	if err := convertJSON(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertJSON(&src.Status, &dst.Status)
+}
+
+// ConvertFrom converts a v1alpha1 JenkinsImage to this version
+func (dst *JenkinsImage) ConvertFrom(src *v1alpha1.JenkinsImage) error {
+	dst.TypeMeta = metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "JenkinsImage"}
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	if err := convertJSON(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertJSON(&src.Status, &dst.Status)
+}
+
+func jenkinsSpecToV1alpha1(src *JenkinsSpec) (v1alpha1.JenkinsSpec, error) {
+	in := src.DeepCopy()
+	out := v1alpha1.JenkinsSpec{
+		UseDeploymentConfig: in.Workload.Kind == JenkinsWorkloadDeploymentConfig,
+		DeletionPolicy:      v1alpha1.JenkinsDeletionPolicy(in.Persistence.DeletionPolicy),
+		Resources:           in.Workload.Resources,
+		JavaOpts:            in.Workload.JavaOpts,
+		JenkinsOpts:         in.Workload.JenkinsOpts,
+		Env:                 in.Workload.Env,
+		EnvFrom:             in.Workload.EnvFrom,
+		Volumes:             in.Workload.Volumes,
+		VolumeMounts:        in.Workload.VolumeMounts,
+		Authentication:      v1alpha1.JenkinsAuthenticationMode(in.Authentication.Mode),
+	}
+	if in.Image != nil {
+		out.JenkinsImageRef = in.Image.JenkinsImage
+		if in.Image.JenkinsImage == "" || *in.Image != (JenkinsImageSource{JenkinsImage: in.Image.JenkinsImage}) {
+			out.Image = &v1alpha1.JenkinsImageSource{
+				Reference:            in.Image.Reference,
+				ImageStreamTag:       in.Image.ImageStreamTag,
+				ImageStreamNamespace: in.Image.ImageStreamNamespace,
+			}
+		}
+	}
+	// The other fields have the same schema in both versions
+	c := &jsonConverter{}
+	c.convert(in.Workload.LivenessProbe, &out.LivenessProbe)
+	c.convert(in.Workload.ReadinessProbe, &out.ReadinessProbe)
+	c.convert(&in.Persistence, &out.Persistence)
+	c.convert(in.Exposure.Route, &out.Route)
+	c.convert(in.Exposure.Ingress, &out.Ingress)
+	c.convert(in.ServiceAccount, &out.ServiceAccount)
+	c.convert(in.ConfigurationAsCode, &out.ConfigurationAsCode)
+	c.convert(in.Backup.Schedule, &out.Backup)
+	c.convert(in.Backup.ObjectStorage, &out.ObjectStorage)
+	return out, c.err
+}
+
+func jenkinsSpecFromV1alpha1(src *v1alpha1.JenkinsSpec) (JenkinsSpec, error) {
+	in := src.DeepCopy()
+	out := JenkinsSpec{
+		Workload: JenkinsWorkload{
+			Resources:    in.Resources,
+			JavaOpts:     in.JavaOpts,
+			JenkinsOpts:  in.JenkinsOpts,
+			Env:          in.Env,
+			EnvFrom:      in.EnvFrom,
+			Volumes:      in.Volumes,
+			VolumeMounts: in.VolumeMounts,
+		},
+		Authentication: JenkinsAuthentication{Mode: JenkinsAuthenticationMode(in.Authentication)},
+	}
+	if in.UseDeploymentConfig {
+		out.Workload.Kind = JenkinsWorkloadDeploymentConfig
+	}
+	if in.Image != nil || in.JenkinsImageRef != "" {
+		out.Image = &JenkinsImageSource{JenkinsImage: in.JenkinsImageRef}
+		if in.Image != nil {
+			out.Image.Reference = in.Image.Reference
+			out.Image.ImageStreamTag = in.Image.ImageStreamTag
+			out.Image.ImageStreamNamespace = in.Image.ImageStreamNamespace
+		}
+	}
+	c := &jsonConverter{}
+	c.convert(in.LivenessProbe, &out.Workload.LivenessProbe)
+	c.convert(in.ReadinessProbe, &out.Workload.ReadinessProbe)
+	c.convert(&in.Persistence, &out.Persistence)
+	out.Persistence.DeletionPolicy = JenkinsDeletionPolicy(in.DeletionPolicy)
+	c.convert(in.Route, &out.Exposure.Route)
+	c.convert(in.Ingress, &out.Exposure.Ingress)
+	c.convert(in.ServiceAccount, &out.ServiceAccount)
+	c.convert(in.ConfigurationAsCode, &out.ConfigurationAsCode)
+	c.convert(in.Backup, &out.Backup.Schedule)
+	c.convert(in.ObjectStorage, &out.Backup.ObjectStorage)
+	return out, c.err
+}
+
+// convertJSON converts between the types of the two versions which have the same schema. out is reset first,
+// json.Unmarshal would merge with its previous content otherwise.
+func convertJSON(in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	value := reflect.ValueOf(out).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	return json.Unmarshal(data, out)
+}
+
+// jsonConverter converts the fields of a spec with convertJSON, it keeps the first error so that the fields
+// are converted without checking every call
+type jsonConverter struct {
+	err error
+}
+
+func (c *jsonConverter) convert(in, out interface{}) {
+	if c.err != nil {
+		return
+	}
+	if err := convertJSON(in, out); err != nil {
+		c.err = fmt.Errorf("cannot convert %T to %T: %v", in, out, err)
+	}
+}
+
+// sameJSON returns whether both values have the same JSON representation
+func sameJSON(a, b interface{}) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
+
+func setAnnotation(meta *metav1.ObjectMeta, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[key] = string(data)
+	return nil
+}
+
+// popAnnotation decodes the value of the annotation and removes it, it returns false when it is not set
+func popAnnotation(meta *metav1.ObjectMeta, key string, value interface{}) (bool, error) {
+	data, found := meta.Annotations[key]
+	if !found {
+		return false, nil
+	}
+	delete(meta.Annotations, key)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+	if err := json.Unmarshal([]byte(data), value); err != nil {
+		return false, fmt.Errorf("cannot decode the %s annotation: %v", key, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,160 @@
+package v1beta1
+
+import (
+	"encoding/json"
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const fuzzIterations = 500
+
+// newFuzzer returns a fuzzer filling the objects with values which can be serialized, the conversion being
+// compared on the serialized objects
+func newFuzzer(seed int64) *fuzz.Fuzzer {
+	return fuzz.NewWithSeed(seed).NilChance(0.3).NumElements(1, 3).Funcs(
+		func(meta *metav1.TypeMeta, c fuzz.Continue) {},
+		func(t *metav1.Time, c fuzz.Continue) {
+			*t = metav1.Unix(c.Int63n(1<<32), 0)
+		},
+		func(q *resource.Quantity, c fuzz.Continue) {
+			// Canonical, as decoded from JSON
+			*q = resource.MustParse(resource.NewQuantity(c.Int63n(1<<40), resource.DecimalSI).String())
+		},
+		func(value *intstr.IntOrString, c fuzz.Continue) {
+			if c.RandBool() {
+				*value = intstr.FromInt(c.Intn(1 << 16))
+			} else {
+				*value = intstr.FromString(c.RandString())
+			}
+		},
+		func(kind *JenkinsWorkloadKind, c fuzz.Continue) {
+			*kind = []JenkinsWorkloadKind{"", JenkinsWorkloadDeployment, JenkinsWorkloadDeploymentConfig}[c.Intn(3)]
+		},
+		func(image *JenkinsImageSource, c fuzz.Continue) {
+			// Only one of the fields is set most of the time, as required by the validation
+			c.FuzzNoCustom(image)
+			if c.RandBool() {
+				*image = JenkinsImageSource{JenkinsImage: image.JenkinsImage}
+			}
+		},
+	)
+}
+
+func requireSameJSON(t *testing.T, expected, actual interface{}) {
+	expectedJSON, err := json.Marshal(expected)
+	require.NoError(t, err)
+	actualJSON, err := json.Marshal(actual)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(actualJSON))
+}
+
+func TestJenkinsConversion(t *testing.T) {
+	t.Run("TestV1alpha1RoundTrip", func(t *testing.T) {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &v1alpha1.Jenkins{}
+			newFuzzer(int64(i)).Fuzz(original)
+			original.TypeMeta = metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "Jenkins"}
+
+			converted := &Jenkins{}
+			require.NoError(t, converted.ConvertFrom(original.DeepCopy()))
+			require.Equal(t, SchemeGroupVersion.String(), converted.APIVersion)
+			roundTripped := &v1alpha1.Jenkins{}
+			require.NoError(t, converted.ConvertTo(roundTripped))
+			requireSameJSON(t, original, roundTripped)
+		}
+	})
+	t.Run("TestV1beta1RoundTrip", func(t *testing.T) {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &Jenkins{}
+			newFuzzer(int64(i)).Fuzz(original)
+			original.TypeMeta = metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "Jenkins"}
+
+			converted := &v1alpha1.Jenkins{}
+			require.NoError(t, original.DeepCopy().ConvertTo(converted))
+			require.Equal(t, v1alpha1.SchemeGroupVersion.String(), converted.APIVersion)
+			roundTripped := &Jenkins{}
+			require.NoError(t, roundTripped.ConvertFrom(converted))
+			requireSameJSON(t, original, roundTripped)
+		}
+	})
+	t.Run("TestSpecIsRestoredFromAnnotation", func(t *testing.T) {
+		original := &Jenkins{
+			TypeMeta:   metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "Jenkins"},
+			ObjectMeta: metav1.ObjectMeta{Name: "jenkins"},
+			Spec:       JenkinsSpec{Workload: JenkinsWorkload{Kind: JenkinsWorkloadDeployment, JavaOpts: "-Xmx1g"}},
+		}
+		converted := &v1alpha1.Jenkins{}
+		require.NoError(t, original.ConvertTo(converted))
+		require.False(t, converted.Spec.UseDeploymentConfig)
+		require.Equal(t, "-Xmx1g", converted.Spec.JavaOpts)
+		require.Contains(t, converted.Annotations, V1beta1SpecAnnotation)
+
+		roundTripped := &Jenkins{}
+		require.NoError(t, roundTripped.ConvertFrom(converted))
+		require.Equal(t, original, roundTripped)
+
+		// the saved spec is ignored once the v1alpha1 spec changed
+		converted.Spec.JavaOpts = "-Xmx2g"
+		require.NoError(t, roundTripped.ConvertFrom(converted))
+		require.Equal(t, JenkinsWorkload{JavaOpts: "-Xmx2g"}, roundTripped.Spec.Workload)
+		require.Empty(t, roundTripped.Annotations)
+	})
+	t.Run("TestSpecIsStructured", func(t *testing.T) {
+		size := "10Gi"
+		original := &v1alpha1.Jenkins{Spec: v1alpha1.JenkinsSpec{
+			Persistence:         v1alpha1.JenkinsPersistence{Enabled: true, Size: size},
+			DeletionPolicy:      v1alpha1.JenkinsDeletionPolicyRetain,
+			UseDeploymentConfig: true,
+			JenkinsImageRef:     "custom",
+			Route:               &v1alpha1.JenkinsRoute{Host: "jenkins.example.com"},
+			Authentication:      v1alpha1.JenkinsAuthenticationBuiltin,
+			ObjectStorage:       &v1alpha1.JenkinsObjectStorage{Bucket: "backups"},
+		}}
+		converted := &Jenkins{}
+		require.NoError(t, converted.ConvertFrom(original))
+		require.Equal(t, JenkinsSpec{
+			Image:          &JenkinsImageSource{JenkinsImage: "custom"},
+			Workload:       JenkinsWorkload{Kind: JenkinsWorkloadDeploymentConfig},
+			Persistence:    JenkinsPersistence{Enabled: true, Size: size, DeletionPolicy: JenkinsDeletionPolicyRetain},
+			Exposure:       JenkinsExposure{Route: &JenkinsRoute{Host: "jenkins.example.com"}},
+			Authentication: JenkinsAuthentication{Mode: JenkinsAuthenticationBuiltin},
+			Backup:         JenkinsBackup{ObjectStorage: &JenkinsObjectStorage{Bucket: "backups"}},
+		}, converted.Spec)
+		require.Empty(t, converted.Annotations)
+	})
+}
+
+func TestJenkinsImageConversion(t *testing.T) {
+	t.Run("TestV1alpha1RoundTrip", func(t *testing.T) {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &v1alpha1.JenkinsImage{}
+			newFuzzer(int64(i)).Fuzz(original)
+			original.TypeMeta = metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "JenkinsImage"}
+
+			converted := &JenkinsImage{}
+			require.NoError(t, converted.ConvertFrom(original.DeepCopy()))
+			roundTripped := &v1alpha1.JenkinsImage{}
+			require.NoError(t, converted.ConvertTo(roundTripped))
+			requireSameJSON(t, original, roundTripped)
+		}
+	})
+	t.Run("TestV1beta1RoundTrip", func(t *testing.T) {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &JenkinsImage{}
+			newFuzzer(int64(i)).Fuzz(original)
+			original.TypeMeta = metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "JenkinsImage"}
+
+			converted := &v1alpha1.JenkinsImage{}
+			require.NoError(t, original.DeepCopy().ConvertTo(converted))
+			roundTripped := &JenkinsImage{}
+			require.NoError(t, roundTripped.ConvertFrom(converted))
+			requireSameJSON(t, original, roundTripped)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// JenkinsImageSpec defines the desired state of JenkinsImage
+// +k8s:openapi-gen=true
+type JenkinsImageSpec struct {
+	// Plugins are installed in the image on top of the plugins of the OpenShift Jenkins image
+	Plugins []JenkinsPlugin `json:"plugins"`
+}
+
+// JenkinsPlugin is a plugin installed in a JenkinsImage
+type JenkinsPlugin struct {
+	// Name is the short name of the plugin, e.g. git
+	Name string `json:"name"`
+	// Version is the version of the plugin, the latest version by default
+	Version string `json:"version,omitempty"`
+}
+
+// JenkinsImageStatus defines the observed state of JenkinsImage
+// +k8s:openapi-gen=true
+type JenkinsImageStatus struct {
+	Phase       JenkinsImagePhase   `json:"phase,omitempty"`       // Phase of the latest build
+	PluginsHash string              `json:"pluginsHash,omitempty"` // Hash of the plugins.txt used by the latest build
+	LatestBuild string              `json:"latestBuild,omitempty"` // Name of the latest build started for the image
+	Builds      []JenkinsImageBuild `json:"builds,omitempty"`      // Most recent builds of the image, newest first
+	Image       string              `json:"image,omitempty"`       // Pullspec by digest of the latest image built successfully
+	ImageDigest string              `json:"imageDigest,omitempty"` // Digest of the latest image built successfully
+	Conditions  []JenkinsCondition  `json:"conditions,omitempty"`  // Detailed conditions of the image
+}
+
+// JenkinsImagePhase is a label for the state of the latest build of a JenkinsImage
+type JenkinsImagePhase string
+
+// JenkinsImageBuild summarizes a build of a JenkinsImage
+type JenkinsImageBuild struct {
+	Name           string            `json:"name"`
+	Phase          JenkinsImagePhase `json:"phase,omitempty"`
+	StartTime      *metav1.Time      `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time      `json:"completionTime,omitempty"`
+	Reason         string            `json:"reason,omitempty"`  // Reason of the build failure
+	Message        string            `json:"message,omitempty"` // Details about the build failure
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JenkinsImage is the Schema for the jenkinsimages API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=jenkinsimages,scope=Namespaced
+type JenkinsImage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   JenkinsImageSpec   `json:"spec,omitempty"`
+	Status JenkinsImageStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JenkinsImageList contains a list of JenkinsImage
+type JenkinsImageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []JenkinsImage `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&JenkinsImage{}, &JenkinsImageList{})
+}
@@ -0,0 +1,362 @@
+// +build !ignore_autogenerated
+
+// This file was autogenerated by openapi-gen. Do not edit it manually!
+
+package v1beta1
+
+import (
+	spec "github.com/go-openapi/spec"
+	common "k8s.io/kube-openapi/pkg/common"
+)
+
+func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+	return map[string]common.OpenAPIDefinition{
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.Jenkins":            schema_pkg_apis_jenkins_v1beta1_Jenkins(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsImage":       schema_pkg_apis_jenkins_v1beta1_JenkinsImage(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsImageSpec":   schema_pkg_apis_jenkins_v1beta1_JenkinsImageSpec(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsImageStatus": schema_pkg_apis_jenkins_v1beta1_JenkinsImageStatus(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsSpec":        schema_pkg_apis_jenkins_v1beta1_JenkinsSpec(ref),
+		"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsStatus":      schema_pkg_apis_jenkins_v1beta1_JenkinsStatus(ref),
+	}
+}
+
+func schema_pkg_apis_jenkins_v1beta1_Jenkins(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Jenkins is the Schema for the jenkins API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsSpec", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_jenkins_v1beta1_JenkinsImage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "JenkinsImage is the Schema for the jenkinsimages API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsImageSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsImageStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsImageSpec", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsImageStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_jenkins_v1beta1_JenkinsImageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "JenkinsImageSpec defines the desired state of JenkinsImage",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"plugins": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Plugins are installed in the image on top of the plugins of the OpenShift Jenkins image",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsPlugin"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"plugins"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsPlugin"},
+	}
+}
+
+func schema_pkg_apis_jenkins_v1beta1_JenkinsImageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "JenkinsImageStatus defines the observed state of JenkinsImage",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"pluginsHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase of the latest build",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"latestBuild": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hash of the plugins.txt used by the latest build",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"builds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the latest build started for the image",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsImageBuild"),
+									},
+								},
+							},
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Most recent builds of the image, newest first",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imageDigest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Pullspec by digest of the latest image built successfully",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Digest of the latest image built successfully",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsCondition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsCondition", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsImageBuild"},
+	}
+}
+
+func schema_pkg_apis_jenkins_v1beta1_JenkinsSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "JenkinsSpec defines the desired state of Jenkins",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image defines the image of the Jenkins container, the OpenShift Jenkins image by default",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsImageSource"),
+						},
+					},
+					"workload": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Workload configures the Deployment or DeploymentConfig running Jenkins and its container",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsWorkload"),
+						},
+					},
+					"persistence": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Persistence configures the volume holding JENKINS_HOME, an emptyDir when it is not enabled",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsPersistence"),
+						},
+					},
+					"exposure": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Exposure configures how the Jenkins web service is exposed outside of the cluster",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsExposure"),
+						},
+					},
+					"authentication": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Authentication configures how users log in to Jenkins",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsAuthentication"),
+						},
+					},
+					"serviceAccount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServiceAccount customizes the ServiceAccount of the instance and the role granted to it, the edit ClusterRole in the namespace of the instance by default",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsServiceAccount"),
+						},
+					},
+					"configurationAsCode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigurationAsCode references the Jenkins Configuration as Code YAML files applied to the instance",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsConfigurationAsCode"),
+						},
+					},
+					"backup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backup configures the backups of JENKINS_HOME, they require persistence to be enabled",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsBackup"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsAuthentication", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsBackup", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsConfigurationAsCode", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsExposure", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsImageSource", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsPersistence", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsServiceAccount", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsWorkload"},
+	}
+}
+
+func schema_pkg_apis_jenkins_v1beta1_JenkinsStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "JenkinsStatus defines the observed state of Jenkins",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase is a high level summary of the state of the instance",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions detail the state of the instance",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsCondition"),
+									},
+								},
+							},
+						},
+					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObservedGeneration is the generation of the spec last reconciled",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the URL under which the instance is exposed",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image is the image resolved for the Jenkins container",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources holds the names of the resources managed for the instance",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsManagedResources"),
+						},
+					},
+					"configurationAsCode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigurationAsCode reports the Jenkins Configuration as Code applied to the instance",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsConfigurationAsCodeStatus"),
+						},
+					},
+					"backup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backup reports the last runs of the scheduled backups",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsBackupScheduleStatus"),
+						},
+					},
+					"objectStorage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObjectStorage reports the backups to the object storage and the archives available for a restore",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsObjectStorageStatus"),
+						},
+					},
+					"persistence": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Persistence reports the claim holding JENKINS_HOME and the progress of its expansion",
+							Ref:         ref("github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsPersistenceStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsBackupScheduleStatus", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsCondition", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsConfigurationAsCodeStatus", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsManagedResources", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsObjectStorageStatus", "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1.JenkinsPersistenceStatus"},
+	}
+}
@@ -0,0 +1,132 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// MaxDiagnosticsErrors is the number of errors kept by Diagnostics, the next ones are only counted
+const MaxDiagnosticsErrors = 10
+
+// Diagnostics collects the outcome of a single reconcile of a custom resource. It is created for every
+// request and dropped with it, so that the errors of unrelated resources are never mixed. The errors of
+// the steps are aggregated to report the Degraded condition and to requeue the request, and every message
+// is logged with the namespace, name and kind of the reconciled resource.
+type Diagnostics struct {
+	logger       logr.Logger
+	values       []interface{}
+	errors       []error
+	dropped      int
+	requeue      bool
+	requeueAfter time.Duration
+}
+
+// NewDiagnostics returns the Diagnostics of the reconcile of the resource of the given kind. The values
+// identifying the resource are given to every log call: logger.WithValues is not safe for concurrent
+// reconciles before the logger of the manager is set.
+func NewDiagnostics(logger logr.Logger, kind string, key types.NamespacedName) *Diagnostics {
+	return &Diagnostics{
+		logger: logger,
+		values: []interface{}{"namespace", key.Namespace, "name", key.Name, "kind", kind},
+	}
+}
+
+// Info logs a step of the reconcile
+func (d *Diagnostics) Info(message string, keysAndValues ...interface{}) {
+	d.logger.Info(message, d.withValues(keysAndValues)...)
+}
+
+// Warning logs a problem which does not prevent the resources from being reconciled
+func (d *Diagnostics) Warning(message string, keysAndValues ...interface{}) {
+	d.logger.Info(message, d.withValues(append([]interface{}{"warning", true}, keysAndValues...))...)
+}
+
+// Error logs and records an error, the request is then requeued
+func (d *Diagnostics) Error(err error, message string, keysAndValues ...interface{}) {
+	d.logger.Error(err, message, d.withValues(keysAndValues)...)
+	if len(d.errors) < MaxDiagnosticsErrors {
+		d.errors = append(d.errors, err)
+	} else {
+		d.dropped++
+	}
+}
+
+func (d *Diagnostics) withValues(keysAndValues []interface{}) []interface{} {
+	return append(append([]interface{}{}, d.values...), keysAndValues...)
+}
+
+// ResourceError logs and records an error about a resource managed for the reconciled resource
+func (d *Diagnostics) ResourceError(err error, message string, obj runtime.Object) {
+	d.Error(err, message, "resource", ResourceName(obj))
+}
+
+// Requeue requests the request to be processed again, with the backoff of the controller
+func (d *Diagnostics) Requeue() {
+	d.requeue = true
+}
+
+// RequeueAfter requests the request to be processed again after the delay, the shortest delay is kept
+func (d *Diagnostics) RequeueAfter(delay time.Duration) {
+	if delay > 0 && (d.requeueAfter == 0 || delay < d.requeueAfter) {
+		d.requeueAfter = delay
+	}
+}
+
+// Failed returns true when an error was recorded
+func (d *Diagnostics) Failed() bool {
+	return len(d.errors) > 0
+}
+
+// Errors returns the errors recorded so far
+func (d *Diagnostics) Errors() []error {
+	return append([]error{}, d.errors...)
+}
+
+// Err aggregates the errors recorded so far, it is nil without error. Returned by the reconcile, it requeues
+// the request with the rate limiter of the controller.
+func (d *Diagnostics) Err() error {
+	return utilerrors.NewAggregate(d.errors)
+}
+
+// Message summarizes the errors recorded so far for the status conditions, it is empty without error
+func (d *Diagnostics) Message() string {
+	messages := []string{}
+	for _, err := range d.errors {
+		messages = append(messages, err.Error())
+	}
+	if d.dropped > 0 {
+		messages = append(messages, fmt.Sprintf("and %d more error(s)", d.dropped))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Result returns the result of the reconcile: the request is requeued with backoff when an error was
+// recorded or a requeue requested, otherwise after the shortest delay requested, if any
+func (d *Diagnostics) Result() reconcile.Result {
+	if d.Failed() || d.requeue {
+		return reconcile.Result{Requeue: true}
+	}
+	return reconcile.Result{RequeueAfter: d.requeueAfter}
+}
+
+// ResourceName identifies obj in the logs as <Kind> <namespace>/<name>
+func ResourceName(obj runtime.Object) string {
+	kind := reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return kind
+	}
+	if len(accessor.GetNamespace()) == 0 {
+		return kind + " " + accessor.GetName()
+	}
+	return kind + " " + accessor.GetNamespace() + "/" + accessor.GetName()
+}
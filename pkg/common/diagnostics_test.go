@@ -0,0 +1,64 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func newTestDiagnostics() *Diagnostics {
+	return NewDiagnostics(logf.Log, "Jenkins", types.NamespacedName{Namespace: "test", Name: "jenkins"})
+}
+
+func TestDiagnostics(t *testing.T) {
+	t.Run("TestSuccessIsNotRequeued", func(t *testing.T) {
+		d := newTestDiagnostics()
+		d.Info("Reconciling")
+		d.Warning("Cannot read the result of a backup")
+		require.False(t, d.Failed())
+		require.Empty(t, d.Message())
+		require.Equal(t, reconcile.Result{}, d.Result())
+	})
+
+	t.Run("TestShortestDelayIsKept", func(t *testing.T) {
+		d := newTestDiagnostics()
+		d.RequeueAfter(time.Minute)
+		d.RequeueAfter(0)
+		d.RequeueAfter(5 * time.Second)
+		d.RequeueAfter(time.Hour)
+		require.Equal(t, reconcile.Result{RequeueAfter: 5 * time.Second}, d.Result())
+
+		d.Requeue()
+		require.Equal(t, reconcile.Result{Requeue: true}, d.Result())
+	})
+
+	t.Run("TestErrorsAreRequeuedAndBounded", func(t *testing.T) {
+		d := newTestDiagnostics()
+		d.RequeueAfter(time.Minute)
+		for i := 0; i < MaxDiagnosticsErrors+2; i++ {
+			d.Error(fmt.Errorf("error %d", i), "Cannot reconcile")
+		}
+		require.True(t, d.Failed())
+		require.Equal(t, reconcile.Result{Requeue: true}, d.Result())
+		require.Len(t, d.Errors(), MaxDiagnosticsErrors)
+		require.Contains(t, d.Message(), "error 0; error 1")
+		require.Contains(t, d.Message(), "; and 2 more error(s)")
+	})
+
+	t.Run("TestResourceErrorNamesTheResource", func(t *testing.T) {
+		d := newTestDiagnostics()
+		service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "jenkins-jnlp"}}
+		d.ResourceError(errors.New("forbidden"), "Cannot reconcile the resource", service)
+		require.Equal(t, "forbidden", d.Message())
+		require.Equal(t, "Service test/jenkins-jnlp", ResourceName(service))
+		require.Equal(t, "Namespace test", ResourceName(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}}))
+	})
+}
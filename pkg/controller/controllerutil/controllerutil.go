@@ -3,10 +3,10 @@ package controllerutil
 import (
 	"fmt"
 
-	"github.com/redhat-developer/openshift-jenkins-operator/pkg/common"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -17,8 +17,7 @@ import (
 var (
 	// AddToManagerFuncs is a list of functions to add all Controllers to the Manager
 	AddToManagerFuncs []func(manager.Manager) error
-	logController      = logf.Log.WithName("jenkins/controller_util.go")
-	controllerMessages = common.NewMessages("Jenkins Controller")
+	logController     = logf.Log.WithName("jenkins/controller_util.go")
 )
 
 type NamedResource struct {
@@ -45,16 +44,59 @@ func AddToManager(m manager.Manager) error {
 	return nil
 }
 
-// WatchResourceOrStackError watch the resource passed as resource and set owner as the parent
-func WatchResourceOrStackError(controller controller.Controller, resource NamedResource, owner runtime.Object) {
-	namespaceNameLog := "| Namespace " + resource.Name + " | Name "
-	message := "watchResourceOrStackError\n" + namespaceNameLog
+// NewAPIReader returns a reader of the objects from the apiserver. Unlike the client of the manager, it does
+// not read from a cache: the objects are up to date and no watch is started, so that a forbidden request
+// fails at once.
+func NewAPIReader(m manager.Manager) (client.Reader, error) {
+	return client.New(m.GetConfig(), client.Options{Scheme: m.GetScheme(), Mapper: m.GetRESTMapper()})
+}
 
-	controllerMessages.LogInfo(message, logController)
-	err := controller.Watch(&source.Kind{Type: resource.Object.(runtime.Object)}, &handler.EnqueueRequestForObject{})
+// WatchResourceOrStackError watch the resource passed as resource and set owner as the parent. The events of a
+// resource enqueue its controller of type owner, or the resource itself when owner is nil.
+func WatchResourceOrStackError(controller controller.Controller, resource NamedResource, owner runtime.Object) {
+	kind := fmt.Sprintf("%T", resource.Object)
+	var eventHandler handler.EventHandler = &handler.EnqueueRequestForObject{}
+	if owner != nil {
+		eventHandler = &handler.EnqueueRequestForOwner{IsController: true, OwnerType: owner}
+	}
+	err := controller.Watch(&source.Kind{Type: resource.Object.(runtime.Object)}, eventHandler)
 	if err != nil {
-		controllerMessages.LogError(err, "Cannot watch component", logController)
+		logController.Error(err, "Cannot watch component", "resource", kind)
 	} else {
-		controllerMessages.LogInfo(fmt.Sprintf("Component %v of parent type %v is now being watched", resource.Object, owner), logController)
+		logController.Info("Component is now being watched", "resource", kind, "owner", fmt.Sprintf("%T", owner))
+	}
+}
+
+// HasFinalizer returns true when obj holds the given finalizer
+func HasFinalizer(obj metav1.Object, finalizer string) bool {
+	for _, f := range obj.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFinalizer adds the finalizer to obj if it is missing and returns true when obj changed
+func AddFinalizer(obj metav1.Object, finalizer string) bool {
+	if HasFinalizer(obj, finalizer) {
+		return false
+	}
+	obj.SetFinalizers(append(obj.GetFinalizers(), finalizer))
+	return true
+}
+
+// RemoveFinalizer removes the finalizer from obj and returns true when obj changed
+func RemoveFinalizer(obj metav1.Object, finalizer string) bool {
+	finalizers := []string{}
+	for _, f := range obj.GetFinalizers() {
+		if f != finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	if len(finalizers) == len(obj.GetFinalizers()) {
+		return false
 	}
+	obj.SetFinalizers(finalizers)
+	return true
 }
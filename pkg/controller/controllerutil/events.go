@@ -0,0 +1,59 @@
+package controllerutil
+
+import (
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// ResourceAction is an operation of a controller on a resource managed for a custom resource
+type ResourceAction string
+
+const (
+	ActionCreate ResourceAction = "Create"
+	ActionUpdate ResourceAction = "Update"
+	ActionDelete ResourceAction = "Delete"
+)
+
+// Reasons of the events recorded for the resources managed by the controllers, following the Kubernetes
+// controllers, e.g. SuccessfulCreate or FailedDelete
+const (
+	ReasonSuccessfulCreate = "Successful" + string(ActionCreate)
+	ReasonFailedCreate     = "Failed" + string(ActionCreate)
+	ReasonSuccessfulUpdate = "Successful" + string(ActionUpdate)
+	ReasonFailedUpdate     = "Failed" + string(ActionUpdate)
+	ReasonSuccessfulDelete = "Successful" + string(ActionDelete)
+	ReasonFailedDelete     = "Failed" + string(ActionDelete)
+)
+
+// RecordResourceEvent records on owner the outcome of the action on obj: a Normal event when err is nil, a
+// Warning event holding the error otherwise
+func RecordResourceEvent(recorder record.EventRecorder, owner, obj runtime.Object, action ResourceAction, err error) {
+	resource := ResourceName(owner, obj)
+	if err != nil {
+		recorder.Eventf(owner, corev1.EventTypeWarning, "Failed"+string(action), "Failed to %s %s: %v",
+			strings.ToLower(string(action)), resource, err)
+		return
+	}
+	recorder.Eventf(owner, corev1.EventTypeNormal, "Successful"+string(action), "%sd %s", action, resource)
+}
+
+// ResourceName returns the kind and name of obj for the messages of the events of owner, the namespace is
+// only given when it is not the namespace of owner
+func ResourceName(owner, obj runtime.Object) string {
+	kind := reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return kind
+	}
+	name := kind + " " + accessor.GetName()
+	if ownerAccessor, err := meta.Accessor(owner); err == nil && len(accessor.GetNamespace()) > 0 &&
+		ownerAccessor.GetNamespace() != accessor.GetNamespace() {
+		name += " in namespace " + accessor.GetNamespace()
+	}
+	return name
+}
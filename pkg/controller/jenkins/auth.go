@@ -0,0 +1,258 @@
+package jenkins
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/common"
+	j "github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/controllerutil"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// RotateAdminCredentialsAnnotation rotates the generated admin credentials every time its value changes
+	RotateAdminCredentialsAnnotation = "jenkins.dev/rotate-admin-credentials"
+	// AdminCredentialsRotationAnnotation records on the admin Secret the rotation request it was generated for
+	AdminCredentialsRotationAnnotation = "jenkins.dev/rotation-request"
+	// AdminCredentialsHashAnnotation rolls Jenkins out again when the admin credentials change
+	AdminCredentialsHashAnnotation = "jenkins.dev/admin-credentials-hash"
+
+	AdminSecretSuffix       = "-admin"
+	AdminUsernameKey        = "username"
+	AdminPasswordKey        = "password"
+	AdminAPITokenKey        = "apiToken"
+	DefaultAdminUsername    = "admin"
+	JenkinsAdminUsernameEnv = "JENKINS_ADMIN_USERNAME"
+	JenkinsAdminPasswordEnv = "JENKINS_ADMIN_PASSWORD"
+	JenkinsAdminAPITokenEnv = "JENKINS_ADMIN_API_TOKEN"
+
+	BuiltinAuthConfigMapSuffix = "-builtin-auth"
+	BuiltinAuthVolumeName      = "jenkins-builtin-auth"
+	BuiltinAuthScriptKey       = "operator-builtin-auth.groovy"
+	BuiltinAuthScriptPath      = JenkinsVolumeMountPath + "/init.groovy.d/" + BuiltinAuthScriptKey
+
+	// apiTokenVersion prefixes the API tokens accepted by ApiTokenStore.addFixedNewToken
+	apiTokenVersion = "11"
+)
+
+// builtinAuthScript is run by Jenkins at startup. It creates or updates the admin user from the credentials of
+// the environment, replaces its API token and only lets logged in users in.
+const builtinAuthScript = `import hudson.security.FullControlOnceLoggedInAuthorizationStrategy
+import hudson.security.HudsonPrivateSecurityRealm
+import jenkins.model.Jenkins
+import jenkins.security.ApiTokenProperty
+
+def env = System.getenv()
+def jenkins = Jenkins.get()
+def realm = jenkins.getSecurityRealm()
+if (!(realm instanceof HudsonPrivateSecurityRealm)) {
+    realm = new HudsonPrivateSecurityRealm(false)
+    jenkins.setSecurityRealm(realm)
+}
+def user = realm.getUser(env.` + JenkinsAdminUsernameEnv + `)
+if (user == null) {
+    user = realm.createAccount(env.` + JenkinsAdminUsernameEnv + `, env.` + JenkinsAdminPasswordEnv + `)
+} else {
+    user.addProperty(HudsonPrivateSecurityRealm.Details.fromPlainPassword(env.` + JenkinsAdminPasswordEnv + `))
+}
+def tokens = user.getProperty(ApiTokenProperty.class).getTokenStore()
+tokens.getTokenListSortedByName().findAll { it.name == "operator" }.each { tokens.revokeToken(it.uuid) }
+tokens.addFixedNewToken("operator", env.` + JenkinsAdminAPITokenEnv + `)
+user.save()
+if (!(jenkins.getAuthorizationStrategy() instanceof FullControlOnceLoggedInAuthorizationStrategy)) {
+    def strategy = new FullControlOnceLoggedInAuthorizationStrategy()
+    strategy.setAllowAnonymousRead(false)
+    jenkins.setAuthorizationStrategy(strategy)
+}
+jenkins.save()
+`
+
+// isBuiltinAuthentication returns true when the users of the instance are stored by Jenkins
+func isBuiltinAuthentication(cr *jenkinsv1alpha1.Jenkins) bool {
+	return cr.Spec.Authentication == jenkinsv1alpha1.JenkinsAuthenticationBuiltin
+}
+
+// randomReader is the source of the generated credentials
+var randomReader io.Reader = rand.Reader
+
+// randomHex returns n random bytes encoded in hexadecimal
+func randomHex(n int) (string, error) {
+	value := make([]byte, n)
+	if _, err := io.ReadFull(randomReader, value); err != nil {
+		return "", fmt.Errorf("cannot generate random bytes: %v", err)
+	}
+	return hex.EncodeToString(value), nil
+}
+
+// newAdminCredentialsData returns newly generated admin credentials
+func newAdminCredentialsData() (map[string][]byte, error) {
+	password, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	apiToken, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		AdminUsernameKey: []byte(DefaultAdminUsername),
+		AdminPasswordKey: []byte(password),
+		AdminAPITokenKey: []byte(apiTokenVersion + apiToken),
+	}, nil
+}
+
+// newAdminCredentialsSecret returns the Secret holding generated admin credentials, for the given rotation request
+func newAdminCredentialsSecret(cr *jenkinsv1alpha1.Jenkins, rotation string) (*corev1.Secret, error) {
+	data, err := newAdminCredentialsData()
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + AdminSecretSuffix,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				JenkinsAppLabel: cr.Name,
+			},
+			Annotations: map[string]string{
+				AdminCredentialsRotationAnnotation: rotation,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}, nil
+}
+
+// newBuiltinAuthConfigMap returns the ConfigMap holding the script configuring the admin user
+func newBuiltinAuthConfigMap(cr *jenkinsv1alpha1.Jenkins) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + BuiltinAuthConfigMapSuffix,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				JenkinsAppLabel: cr.Name,
+			},
+		},
+		Data: map[string]string{
+			BuiltinAuthScriptKey: builtinAuthScript,
+		},
+	}
+}
+
+// newBuiltinAuthEnvVars returns the variables passing the admin credentials to the Jenkins container
+func newBuiltinAuthEnvVars(cr *jenkinsv1alpha1.Jenkins) []corev1.EnvVar {
+	if !isBuiltinAuthentication(cr) {
+		return []corev1.EnvVar{}
+	}
+	fromSecret := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: cr.Name + AdminSecretSuffix},
+				Key:                  key,
+			},
+		}
+	}
+	return []corev1.EnvVar{
+		{Name: JenkinsAdminUsernameEnv, ValueFrom: fromSecret(AdminUsernameKey)},
+		{Name: JenkinsAdminPasswordEnv, ValueFrom: fromSecret(AdminPasswordKey)},
+		{Name: JenkinsAdminAPITokenEnv, ValueFrom: fromSecret(AdminAPITokenKey)},
+	}
+}
+
+// newBuiltinAuthVolumes returns the volume and mount of the script configuring the admin user at startup
+func newBuiltinAuthVolumes(cr *jenkinsv1alpha1.Jenkins) ([]corev1.Volume, []corev1.VolumeMount) {
+	if !isBuiltinAuthentication(cr) {
+		return []corev1.Volume{}, []corev1.VolumeMount{}
+	}
+	volume := corev1.Volume{
+		Name: BuiltinAuthVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: cr.Name + BuiltinAuthConfigMapSuffix},
+			},
+		},
+	}
+	mount := corev1.VolumeMount{Name: BuiltinAuthVolumeName, MountPath: BuiltinAuthScriptPath, SubPath: BuiltinAuthScriptKey, ReadOnly: true}
+	return []corev1.Volume{volume}, []corev1.VolumeMount{mount}
+}
+
+// reconcileAdminCredentials generates the admin credentials of an instance using the builtin authentication, or
+// rotates them when requested, and deletes them when they are not used anymore
+func (rc *ReconcileContext) reconcileAdminCredentials() error {
+	instance := rc.ControlledResources.JenkinsInstance
+	secret := &corev1.Secret{}
+	err := rc.Client.Get(context.TODO(), types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name + AdminSecretSuffix}, secret)
+	if err != nil && !kubeerrors.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	if !isBuiltinAuthentication(instance) {
+		rc.ControlledResources.AdminSecret = nil
+		if !found || !metav1.IsControlledBy(secret, instance) {
+			return nil
+		}
+		rc.Diagnostics.Info("Deleting the unused admin credentials", "resource", common.ResourceName(secret))
+		if err := rc.Client.Delete(context.TODO(), secret); err != nil && !kubeerrors.IsNotFound(err) {
+			rc.recordResourceEvent(secret, j.ActionDelete, err)
+			return err
+		}
+		rc.recordResourceEvent(secret, j.ActionDelete, nil)
+		return nil
+	}
+
+	rotation := instance.Annotations[RotateAdminCredentialsAnnotation]
+	if !found {
+		secret, err = newAdminCredentialsSecret(instance, rotation)
+		if err != nil {
+			return err
+		}
+		if err := controllerutil.SetControllerReference(instance, secret, rc.Scheme); err != nil {
+			return err
+		}
+		rc.Diagnostics.Info("Generating the admin credentials", "resource", common.ResourceName(secret))
+		err := rc.Client.Create(context.TODO(), secret)
+		rc.recordResourceEvent(secret, j.ActionCreate, err)
+		if err != nil {
+			return err
+		}
+	} else if len(rotation) > 0 && rotation != secret.Annotations[AdminCredentialsRotationAnnotation] {
+		rc.Diagnostics.Info("Rotating the admin credentials", "resource", common.ResourceName(secret))
+		data, err := newAdminCredentialsData()
+		if err != nil {
+			return err
+		}
+		secret.Data = data
+		secret.Annotations = mergeStringMap(secret.Annotations, map[string]string{AdminCredentialsRotationAnnotation: rotation})
+		err = rc.Client.Update(context.TODO(), secret)
+		rc.recordResourceEvent(secret, j.ActionUpdate, err)
+		if err != nil {
+			return err
+		}
+	}
+	rc.ControlledResources.AdminSecret = secret
+	return nil
+}
+
+// setAdminCredentialsHash records the hash of the admin credentials in the pod template so that Jenkins is
+// rolled out again with the new credentials when they are rotated
+func (rc *ReconcileContext) setAdminCredentialsHash(template *corev1.PodTemplateSpec) {
+	secret := rc.ControlledResources.AdminSecret
+	if template == nil || secret == nil || !isBuiltinAuthentication(rc.ControlledResources.JenkinsInstance) {
+		return
+	}
+	h := sha256.New()
+	writeHashEntries(h, "secret/"+secret.Name, secret.Data)
+	template.Annotations = mergeStringMap(template.Annotations, map[string]string{
+		AdminCredentialsHashAnnotation: hex.EncodeToString(h.Sum(nil))[:16],
+	})
+}
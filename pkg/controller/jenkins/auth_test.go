@@ -0,0 +1,162 @@
+package jenkins
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	kappsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// failingReader is an entropy source which cannot be read
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("entropy source unavailable")
+}
+
+func newBuiltinAuthJenkins() *jenkinsv1alpha1.Jenkins {
+	cr := mocks.JenkinsCRMock(test_ns, test_name)
+	cr.Spec.Authentication = jenkinsv1alpha1.JenkinsAuthenticationBuiltin
+	return cr
+}
+
+func TestReconcileAdminCredentials(t *testing.T) {
+	adminSecretName := types.NamespacedName{Namespace: test_ns, Name: test_name + AdminSecretSuffix}
+
+	t.Run("TestCredentialsAreGeneratedAndWired", func(t *testing.T) {
+		r, c := newTestReconciler(newBuiltinAuthJenkins())
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		secret := &corev1.Secret{}
+		require.NoError(t, c.Get(context.TODO(), adminSecretName, secret))
+		require.Equal(t, test_name, metav1.GetControllerOf(secret).Name)
+		require.Equal(t, DefaultAdminUsername, string(secret.Data[AdminUsernameKey]))
+		require.Len(t, secret.Data[AdminPasswordKey], 32)
+		require.Len(t, secret.Data[AdminAPITokenKey], 34)
+		require.True(t, strings.HasPrefix(string(secret.Data[AdminAPITokenKey]), apiTokenVersion))
+
+		deployment := &kappsv1.Deployment{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		template := deployment.Spec.Template
+		require.NotEmpty(t, template.Annotations[AdminCredentialsHashAnnotation])
+		container := template.Spec.Containers[0]
+		require.Contains(t, container.Env, corev1.EnvVar{Name: "OPENSHIFT_ENABLE_OAUTH", Value: "false"})
+		require.Contains(t, container.Env, newBuiltinAuthEnvVars(newBuiltinAuthJenkins())[1])
+		require.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: BuiltinAuthVolumeName, MountPath: BuiltinAuthScriptPath, SubPath: BuiltinAuthScriptKey, ReadOnly: true})
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: test_name + BuiltinAuthConfigMapSuffix}, &corev1.ConfigMap{}))
+
+		serviceAccount := &corev1.ServiceAccount{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, serviceAccount))
+		require.Empty(t, serviceAccount.Annotations["serviceaccounts.openshift.io/oauth-redirectreference."+test_name])
+
+		instance := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, instance))
+		require.Equal(t, adminSecretName.Name, instance.Status.Resources.AdminSecret)
+	})
+	t.Run("TestAnnotationRotatesCredentials", func(t *testing.T) {
+		r, c := newTestReconciler(newBuiltinAuthJenkins())
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		before := &corev1.Secret{}
+		require.NoError(t, c.Get(context.TODO(), adminSecretName, before))
+		deployment := &kappsv1.Deployment{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		hash := deployment.Spec.Template.Annotations[AdminCredentialsHashAnnotation]
+
+		// the credentials are kept until a rotation is requested
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		after := &corev1.Secret{}
+		require.NoError(t, c.Get(context.TODO(), adminSecretName, after))
+		require.Equal(t, before.Data, after.Data)
+
+		instance := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, instance))
+		instance.Annotations = map[string]string{RotateAdminCredentialsAnnotation: "1"}
+		require.NoError(t, c.Update(context.TODO(), instance))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), adminSecretName, after))
+		require.NotEqual(t, string(before.Data[AdminPasswordKey]), string(after.Data[AdminPasswordKey]))
+		require.NotEqual(t, string(before.Data[AdminAPITokenKey]), string(after.Data[AdminAPITokenKey]))
+		require.Equal(t, "1", after.Annotations[AdminCredentialsRotationAnnotation])
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		require.NotEqual(t, hash, deployment.Spec.Template.Annotations[AdminCredentialsHashAnnotation])
+	})
+	t.Run("TestEntropyFailureKeepsCredentials", func(t *testing.T) {
+		defer func(reader io.Reader) { randomReader = reader }(randomReader)
+		randomReader = failingReader{}
+		r, c := newTestReconciler(newBuiltinAuthJenkins())
+
+		// no credentials are generated
+		_, err := r.Reconcile(testRequest())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot generate random bytes")
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), adminSecretName, &corev1.Secret{})))
+
+		randomReader = rand.Reader
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		before := &corev1.Secret{}
+		require.NoError(t, c.Get(context.TODO(), adminSecretName, before))
+
+		// nor rotated
+		randomReader = failingReader{}
+		instance := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, instance))
+		instance.Annotations = map[string]string{RotateAdminCredentialsAnnotation: "1"}
+		require.NoError(t, c.Update(context.TODO(), instance))
+		_, err = r.Reconcile(testRequest())
+		require.Error(t, err)
+		after := &corev1.Secret{}
+		require.NoError(t, c.Get(context.TODO(), adminSecretName, after))
+		require.Equal(t, before.Data, after.Data)
+		require.Empty(t, after.Annotations[AdminCredentialsRotationAnnotation])
+	})
+	t.Run("TestOpenShiftOAuthByDefault", func(t *testing.T) {
+		r, c := newTestReconciler(mocks.JenkinsCRMock(test_ns, test_name))
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), adminSecretName, &corev1.Secret{})))
+		deployment := &kappsv1.Deployment{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		require.Contains(t, deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "OPENSHIFT_ENABLE_OAUTH", Value: "true"})
+		require.Empty(t, deployment.Spec.Template.Annotations[AdminCredentialsHashAnnotation])
+	})
+	t.Run("TestCredentialsAreDeletedWithOpenShiftOAuth", func(t *testing.T) {
+		r, c := newTestReconciler(newBuiltinAuthJenkins())
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+
+		instance := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, instance))
+		instance.Spec.Authentication = jenkinsv1alpha1.JenkinsAuthenticationOpenShiftOAuth
+		require.NoError(t, c.Update(context.TODO(), instance))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), adminSecretName, &corev1.Secret{})))
+		deployment := &kappsv1.Deployment{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		container := deployment.Spec.Template.Spec.Containers[0]
+		require.Contains(t, container.Env, corev1.EnvVar{Name: "OPENSHIFT_ENABLE_OAUTH", Value: "true"})
+		for _, env := range container.Env {
+			require.NotEqual(t, JenkinsAdminPasswordEnv, env.Name)
+		}
+		for _, volume := range deployment.Spec.Template.Spec.Volumes {
+			require.NotEqual(t, BuiltinAuthVolumeName, volume.Name)
+		}
+	})
+}
@@ -0,0 +1,358 @@
+package jenkins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/common"
+	j "github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/controllerutil"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// BackupMountPath is the directory where the destination of the archives is mounted in the jobs
+	BackupMountPath = "/backup"
+	// BackupVolumeName is the name of the volume of the destination in the jobs
+	BackupVolumeName = "jenkins-backup"
+	// ArchiveExtension is the extension of the archives created by the backups
+	ArchiveExtension = ".tar.gz"
+	// JobNameLabel is set by the job controller on the pods of a job
+	JobNameLabel = "job-name"
+
+	BackupContainerName   = "backup"
+	BackupArchiveEnv      = "BACKUP_ARCHIVE"
+	BackupIncludesEnv     = "BACKUP_INCLUDES"
+	BackupExcludesEnv     = "BACKUP_EXCLUDES"
+	DefaultBackupIncludes = "."
+	HostnameTopologyKey   = "kubernetes.io/hostname"
+
+	ScheduledBackupSuffix         = "-scheduled-backup"
+	ScheduledBackupLabel          = "jenkins.dev/scheduled-backup"
+	ScheduledArchivePrefix        = "scheduled-"
+	ScheduledArchivePrefixEnv     = "BACKUP_ARCHIVE_PREFIX"
+	ScheduledMaxCountEnv          = "BACKUP_MAX_COUNT"
+	ScheduledMaxAgeEnv            = "BACKUP_MAX_AGE_MINUTES"
+	ScheduledBackupHistoryLimit   = 3
+	ScheduledBackupAffinityWeight = 100
+	// MaxCronJobNameLength leaves room for the suffix appended by the CronJob controller to the names of the jobs
+	MaxCronJobNameLength = 52
+)
+
+// BackupScript archives the includes of JENKINS_HOME, skipping the excludes, then reports the archive in the
+// termination message. Includes are shell patterns expanded from JENKINS_HOME, excludes are tar patterns.
+const BackupScript = `set -euo pipefail
+cd ` + JenkinsVolumeMountPath + `
+archive="` + BackupMountPath + `/${BACKUP_ARCHIVE}"
+mkdir -p "$(dirname "$archive")"
+printf '%s\n' "${BACKUP_EXCLUDES:-}" | sed '/^$/d' > /tmp/excludes
+members=()
+IFS=$'\n'
+for pattern in ${BACKUP_INCLUDES}; do
+  for member in $pattern; do
+    if [ -e "$member" ]; then members+=("$member"); fi
+  done
+done
+unset IFS
+if [ ${#members[@]} -eq 0 ]; then
+  echo "no file of JENKINS_HOME matches the includes" > /dev/termination-log
+  exit 1
+fi
+tar -czf "$archive.partial" --exclude-from=/tmp/excludes "${members[@]}"
+mv "$archive.partial" "$archive"
+size=$(stat -c %s "$archive")
+checksum=$(sha256sum "$archive" | cut -d ' ' -f 1)
+printf '{"archive":"%s","size":%s,"checksum":"sha256:%s"}' "$BACKUP_ARCHIVE" "$size" "$checksum" > /dev/termination-log
+`
+
+// scheduledBackupScript names the archive after the time of the run, runs the backup and prunes the archives
+// of the previous runs by age and by count. A failed pruning does not fail the backup, it is retried by the
+// next run.
+const scheduledBackupScript = `BACKUP_ARCHIVE="${BACKUP_ARCHIVE_PREFIX}$(date -u +%Y%m%d%H%M%S)` + ArchiveExtension + `"
+` + BackupScript + `(
+  cd "$(dirname "$archive")"
+  pattern="$(basename "$BACKUP_ARCHIVE_PREFIX")*` + ArchiveExtension + `"
+  if [ -n "${BACKUP_MAX_AGE_MINUTES:-}" ]; then
+    find . -maxdepth 1 -name "$pattern" ! -name "$(basename "$archive")" -mmin "+${BACKUP_MAX_AGE_MINUTES}" -delete
+  fi
+  if [ -n "${BACKUP_MAX_COUNT:-}" ]; then
+    ls -1 $pattern | sort -r | tail -n "+$((BACKUP_MAX_COUNT + 1))" | xargs -r rm -f
+  fi
+) || echo "pruning of the archives failed" >&2
+`
+
+// ArchiveResult is written by the backup and restore jobs to their termination message
+type ArchiveResult struct {
+	Archive  string `json:"archive"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// JobCompletion returns true once the job finished, and the reason of its failure if it failed
+func JobCompletion(job *batchv1.Job) (bool, string) {
+	if job.Status.Succeeded > 0 {
+		return true, ""
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true, fmt.Sprintf("Job %s failed: %s", job.Name, condition.Message)
+		}
+	}
+	return false, ""
+}
+
+// JobTerminationMessages returns the termination messages of the pods of the job, the messages of the
+// succeeded pods first
+func JobTerminationMessages(c client.Client, job *batchv1.Job) ([]string, error) {
+	pods := &corev1.PodList{}
+	opts := client.InNamespace(job.Namespace).MatchingLabels(map[string]string{JobNameLabel: job.Name})
+	if err := c.List(context.TODO(), opts, pods); err != nil {
+		return nil, err
+	}
+	succeeded, failed := []string{}, []string{}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated == nil || len(status.State.Terminated.Message) == 0 {
+				continue
+			}
+			if status.State.Terminated.ExitCode == 0 {
+				succeeded = append(succeeded, status.State.Terminated.Message)
+			} else {
+				failed = append(failed, status.State.Terminated.Message)
+			}
+		}
+	}
+	return append(succeeded, failed...), nil
+}
+
+// ReadArchiveResult returns the result written by the succeeded pod of a backup or restore job
+func ReadArchiveResult(c client.Client, job *batchv1.Job) (*ArchiveResult, error) {
+	messages, err := JobTerminationMessages(c, job)
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range messages {
+		result := &ArchiveResult{}
+		if err := json.Unmarshal([]byte(message), result); err == nil && len(result.Checksum) > 0 {
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("job %s did not report the archive it processed", job.Name)
+}
+
+// JobFailureMessage returns the reason of the failure of the job completed with the message written by its
+// failed pods, if any
+func JobFailureMessage(c client.Client, job *batchv1.Job, reason string) string {
+	messages, err := JobTerminationMessages(c, job)
+	if err != nil || len(messages) == 0 {
+		return reason
+	}
+	return reason + ": " + messages[len(messages)-1]
+}
+
+// NewClaimVolume returns a volume mounting the claim
+func NewClaimVolume(name, claimName string, readOnly bool) corev1.Volume {
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName, ReadOnly: readOnly},
+		},
+	}
+}
+
+// NewJenkinsPodAffinityTerm returns the term selecting the node running the pods of the Jenkins instance, so
+// that the jobs mounting its ReadWriteOnce volume run on the same node
+func NewJenkinsPodAffinityTerm(cr *jenkinsv1alpha1.Jenkins) corev1.PodAffinityTerm {
+	return corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+			JenkinsAppLabelName: cr.Name,
+			JenkinsNameLabel:    cr.Name,
+		}},
+		TopologyKey: HostnameTopologyKey,
+	}
+}
+
+// newJenkinsBackupCronJob returns the CronJob archiving JENKINS_HOME on the schedule of the instance. The jobs
+// prefer the node of Jenkins but can run anywhere while it is stopped.
+func newJenkinsBackupCronJob(cr *jenkinsv1alpha1.Jenkins, image string) *batchv1beta1.CronJob {
+	backup := cr.Spec.Backup
+	backoffLimit := int32(2)
+	historyLimit := int32(ScheduledBackupHistoryLimit)
+	labels := map[string]string{
+		JenkinsAppLabel:      cr.Name,
+		ScheduledBackupLabel: cr.Name,
+	}
+	env := []corev1.EnvVar{
+		{Name: ScheduledArchivePrefixEnv, Value: cr.Name + "/" + ScheduledArchivePrefix},
+		{Name: BackupIncludesEnv, Value: DefaultBackupIncludes},
+		{Name: BackupExcludesEnv, Value: strings.Join(backup.Excludes, "\n")},
+	}
+	if backup.Retention.MaxCount != nil {
+		env = append(env, corev1.EnvVar{Name: ScheduledMaxCountEnv, Value: strconv.Itoa(int(*backup.Retention.MaxCount))})
+	}
+	if backup.Retention.MaxAge != nil {
+		minutes := int(backup.Retention.MaxAge.Minutes())
+		if minutes < 1 {
+			minutes = 1
+		}
+		env = append(env, corev1.EnvVar{Name: ScheduledMaxAgeEnv, Value: strconv.Itoa(minutes)})
+	}
+	return &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + ScheduledBackupSuffix,
+			Namespace: cr.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:                   backup.Schedule,
+			ConcurrencyPolicy:          batchv1beta1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &historyLimit,
+			FailedJobsHistoryLimit:     &historyLimit,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers: []corev1.Container{
+								{
+									Name:    BackupContainerName,
+									Image:   image,
+									Command: []string{"/bin/bash", "-c", scheduledBackupScript},
+									Env:     env,
+									VolumeMounts: []corev1.VolumeMount{
+										{Name: JenkinsVolumeName, MountPath: JenkinsVolumeMountPath, ReadOnly: true},
+										{Name: BackupVolumeName, MountPath: BackupMountPath},
+									},
+								},
+							},
+							Volumes: []corev1.Volume{
+								NewClaimVolume(JenkinsVolumeName, JenkinsClaimName(cr), true),
+								NewClaimVolume(BackupVolumeName, backup.Destination.PersistentVolumeClaim, false),
+							},
+							Affinity: &corev1.Affinity{
+								PodAffinity: &corev1.PodAffinity{
+									PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+										{Weight: ScheduledBackupAffinityWeight, PodAffinityTerm: NewJenkinsPodAffinityTerm(cr)},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// reconcileScheduledBackups removes the backup CronJob of an instance which does not schedule backups anymore,
+// and computes the status of the scheduled backups from the jobs of the CronJob.
+func (rc *ReconcileContext) reconcileScheduledBackups() error {
+	instance := rc.ControlledResources.JenkinsInstance
+	if instance.Spec.Backup == nil || !rc.isPersistent() {
+		rc.BackupStatus = nil
+		cronJob := &batchv1beta1.CronJob{}
+		err := rc.Client.Get(context.TODO(), types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name + ScheduledBackupSuffix}, cronJob)
+		if kubeerrors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		rc.Diagnostics.Info("Deleting the unused backup CronJob", "resource", common.ResourceName(cronJob))
+		if err := rc.Client.Delete(context.TODO(), cronJob); err != nil && !kubeerrors.IsNotFound(err) {
+			rc.recordResourceEvent(cronJob, j.ActionDelete, err)
+			return err
+		}
+		rc.recordResourceEvent(cronJob, j.ActionDelete, nil)
+		return nil
+	}
+
+	status := &jenkinsv1alpha1.JenkinsBackupScheduleStatus{}
+	if instance.Status.Backup != nil {
+		status = instance.Status.Backup.DeepCopy()
+	}
+	rc.BackupStatus = status
+	jobs := &batchv1.JobList{}
+	opts := client.InNamespace(instance.Namespace).MatchingLabels(map[string]string{ScheduledBackupLabel: instance.Name})
+	if err := rc.Client.List(context.TODO(), opts, jobs); err != nil {
+		return err
+	}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		finished, failure := JobCompletion(job)
+		if !finished {
+			continue
+		}
+		run := &jenkinsv1alpha1.JenkinsBackupRun{Job: job.Name, StartTime: job.Status.StartTime, CompletionTime: jobFinishTime(job)}
+		if len(failure) > 0 {
+			if isNewerBackupRun(run, status.LastFailed) {
+				run.Message = JobFailureMessage(rc.Client, job, failure)
+				status.LastFailed = run
+			}
+			continue
+		}
+		if !isNewerBackupRun(run, status.LastSuccessful) {
+			continue
+		}
+		result, err := ReadArchiveResult(rc.Client, job)
+		if err != nil {
+			// The pods of the job were removed, the archive is not known anymore
+			rc.Diagnostics.Warning("Cannot read the result of a scheduled backup", "error", err.Error())
+		} else {
+			run.Archive = result.Archive
+			run.Size = result.Size
+			run.Checksum = result.Checksum
+		}
+		status.LastSuccessful = run
+	}
+	return nil
+}
+
+// jobFinishTime returns the time at which the job succeeded or failed
+func jobFinishTime(job *batchv1.Job) *metav1.Time {
+	if job.Status.CompletionTime != nil {
+		return job.Status.CompletionTime
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			failedAt := condition.LastTransitionTime
+			return &failedAt
+		}
+	}
+	return nil
+}
+
+// isNewerBackupRun returns true when run is not the reported run and finished after it
+func isNewerBackupRun(run, reported *jenkinsv1alpha1.JenkinsBackupRun) bool {
+	if reported == nil || reported.CompletionTime == nil {
+		return true
+	}
+	if run.CompletionTime == nil {
+		return false
+	}
+	if run.Job == reported.Job {
+		return false
+	}
+	return reported.CompletionTime.Before(run.CompletionTime)
+}
+
+// scheduledBackupMapper enqueues the Jenkins instance of the scheduled backup a job belongs to
+func scheduledBackupMapper(obj handler.MapObject) []reconcile.Request {
+	name, found := obj.Meta.GetLabels()[ScheduledBackupLabel]
+	if !found {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.Meta.GetNamespace(), Name: name}}}
+}
@@ -0,0 +1,172 @@
+package jenkins
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func newBackupScheduleJenkins() *jenkinsv1alpha1.Jenkins {
+	cr := mocks.JenkinsCRMock(test_ns, test_name)
+	cr.Spec.Persistence.Enabled = true
+	cr.Spec.Backup = &jenkinsv1alpha1.JenkinsBackupSchedule{
+		Schedule:    "0 2 * * *",
+		Destination: jenkinsv1alpha1.JenkinsBackupDestination{PersistentVolumeClaim: "jenkins-backups"},
+		Excludes:    []string{"workspace"},
+		Retention: jenkinsv1alpha1.JenkinsBackupRetention{
+			MaxCount: int32Ptr(14),
+			MaxAge:   &metav1.Duration{Duration: 14 * 24 * time.Hour},
+		},
+	}
+	return cr
+}
+
+// newScheduledBackupJob returns a finished job of the backup CronJob and the pod which ran it
+func newScheduledBackupJob(name string, finishedAt time.Time, exitCode int32, message string) (*batchv1.Job, *corev1.Pod) {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: test_ns,
+			Name:      name,
+			Labels:    map[string]string{ScheduledBackupLabel: test_name},
+		},
+	}
+	if exitCode == 0 {
+		job.Status.Succeeded = 1
+		job.Status.CompletionTime = &metav1.Time{Time: finishedAt}
+	} else {
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, LastTransitionTime: metav1.Time{Time: finishedAt}, Message: "BackoffLimitExceeded"},
+		}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: name + "-pod", Labels: map[string]string{JobNameLabel: name}},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message}}},
+			},
+		},
+	}
+	return job, pod
+}
+
+func TestReconcileScheduledBackups(t *testing.T) {
+	t.Run("TestCronJobFollowsSpec", func(t *testing.T) {
+		r, c := newTestReconciler(newBackupScheduleJenkins())
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		cronJob := &batchv1beta1.CronJob{}
+		key := types.NamespacedName{Namespace: test_ns, Name: test_name + ScheduledBackupSuffix}
+		require.NoError(t, c.Get(context.TODO(), key, cronJob))
+		require.Equal(t, "0 2 * * *", cronJob.Spec.Schedule)
+		require.Equal(t, batchv1beta1.ForbidConcurrent, cronJob.Spec.ConcurrencyPolicy)
+		require.Equal(t, test_name, metav1.GetControllerOf(cronJob).Name)
+		podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		require.Equal(t, test_name, podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		require.Equal(t, "jenkins-backups", podSpec.Volumes[1].PersistentVolumeClaim.ClaimName)
+		env := podSpec.Containers[0].Env
+		require.Contains(t, env, corev1.EnvVar{Name: ScheduledArchivePrefixEnv, Value: test_name + "/" + ScheduledArchivePrefix})
+		require.Contains(t, env, corev1.EnvVar{Name: BackupExcludesEnv, Value: "workspace"})
+		require.Contains(t, env, corev1.EnvVar{Name: ScheduledMaxCountEnv, Value: "14"})
+		require.Contains(t, env, corev1.EnvVar{Name: ScheduledMaxAgeEnv, Value: "20160"})
+
+		// the retention by count is removed from the jobs
+		cr := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		cr.Spec.Backup.Retention.MaxCount = nil
+		cr.Spec.Backup.Schedule = "@daily"
+		require.NoError(t, c.Update(context.TODO(), cr))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), key, cronJob))
+		require.Equal(t, "@daily", cronJob.Spec.Schedule)
+		for _, envVar := range cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env {
+			require.NotEqual(t, ScheduledMaxCountEnv, envVar.Name)
+		}
+
+		// the CronJob is removed with the schedule
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		cr.Spec.Backup = nil
+		require.NoError(t, c.Update(context.TODO(), cr))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), key, cronJob)))
+	})
+
+	t.Run("TestCronJobRunsTheImageOfTheInstance", func(t *testing.T) {
+		cr := newBackupScheduleJenkins()
+		cr.Spec.Image = &jenkinsv1alpha1.JenkinsImageSource{Reference: "quay.io/example/jenkins:2.235"}
+		r, c := newTestReconciler(cr)
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		cronJob := &batchv1beta1.CronJob{}
+		key := types.NamespacedName{Namespace: test_ns, Name: test_name + ScheduledBackupSuffix}
+		require.NoError(t, c.Get(context.TODO(), key, cronJob))
+		require.Equal(t, "quay.io/example/jenkins:2.235", cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Image)
+
+		// the CronJob is kept as is while the image cannot be resolved
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		cr.Spec.Image = &jenkinsv1alpha1.JenkinsImageSource{ImageStreamTag: "jenkins:2", ImageStreamNamespace: "openshift"}
+		cr.Spec.Backup.Schedule = "@daily"
+		require.NoError(t, c.Update(context.TODO(), cr))
+		_, err = r.Reconcile(testRequest())
+		require.Error(t, err)
+		require.NoError(t, c.Get(context.TODO(), key, cronJob))
+		require.Equal(t, "quay.io/example/jenkins:2.235", cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Image)
+		require.Equal(t, "0 2 * * *", cronJob.Spec.Schedule)
+	})
+	t.Run("TestStatusReportsLastRuns", func(t *testing.T) {
+		now := time.Now().Truncate(time.Second)
+		older, olderPod := newScheduledBackupJob("backup-1", now.Add(-2*time.Hour), 0,
+			`{"archive":"test-jenkins/scheduled-1.tar.gz","size":1024,"checksum":"sha256:1111"}`)
+		newer, newerPod := newScheduledBackupJob("backup-2", now.Add(-time.Hour), 0,
+			`{"archive":"test-jenkins/scheduled-2.tar.gz","size":2048,"checksum":"sha256:2222"}`)
+		failed, failedPod := newScheduledBackupJob("backup-3", now, 1, "tar: write error: No space left on device")
+		r, c := newTestReconciler(newBackupScheduleJenkins(), older, olderPod, newer, newerPod, failed, failedPod)
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		cr := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.NotNil(t, cr.Status.Backup)
+		lastSuccessful := cr.Status.Backup.LastSuccessful
+		require.Equal(t, "backup-2", lastSuccessful.Job)
+		require.Equal(t, "test-jenkins/scheduled-2.tar.gz", lastSuccessful.Archive)
+		require.Equal(t, int64(2048), lastSuccessful.Size)
+		require.Equal(t, "sha256:2222", lastSuccessful.Checksum)
+		require.Equal(t, "backup-3", cr.Status.Backup.LastFailed.Job)
+		require.Contains(t, cr.Status.Backup.LastFailed.Message, "No space left on device")
+
+		// the last runs are still reported once their jobs are removed by the history limit
+		for _, obj := range []*batchv1.Job{older, newer, failed} {
+			require.NoError(t, c.Delete(context.TODO(), obj))
+		}
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, "backup-2", cr.Status.Backup.LastSuccessful.Job)
+		require.Equal(t, "backup-3", cr.Status.Backup.LastFailed.Job)
+	})
+
+	t.Run("TestMapperEnqueuesInstance", func(t *testing.T) {
+		job, _ := newScheduledBackupJob("backup-1", time.Now(), 0, "")
+		requests := scheduledBackupMapper(handler.MapObject{Meta: job, Object: job})
+		require.Len(t, requests, 1)
+		require.Equal(t, testRequest().NamespacedName, requests[0].NamespacedName)
+
+		job.Labels = nil
+		require.Empty(t, scheduledBackupMapper(handler.MapObject{Meta: job, Object: job}))
+	})
+}
@@ -0,0 +1,290 @@
+package jenkins
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	JenkinsCascConfigEnv            = "CASC_JENKINS_CONFIG"
+	JenkinsCascReloadTokenEnv       = "CASC_RELOAD_TOKEN"
+	JenkinsCascMountPath            = "/var/lib/jenkins-casc"
+	JenkinsCascVolumePrefix         = "jenkins-casc-"
+	JenkinsCascReloadSecretSuffix   = "-casc-reload"
+	JenkinsCascReloadTokenKey       = "token"
+	JenkinsCascReloadPath           = "/reload-configuration-as-code/"
+	JenkinsCascReloadTokenParameter = "casc-reload-token"
+)
+
+var (
+	// ConfigurationReloadDelay leaves time to the kubelet to update the mounted ConfigMaps and Secrets
+	// before Jenkins is asked to reload its configuration
+	ConfigurationReloadDelay = time.Minute
+	// ConfigurationReloadRetryDelay is the delay before a failed or postponed reload is tried again
+	ConfigurationReloadRetryDelay = 30 * time.Second
+)
+
+// ConfigurationReloader asks a Jenkins instance to reload its Configuration as Code
+type ConfigurationReloader interface {
+	// Reload calls the reload endpoint of the Jenkins instance served at jenkinsURL with the given token
+	Reload(jenkinsURL, token string) error
+}
+
+// httpConfigurationReloader calls the reload endpoint of the Configuration as Code plugin
+type httpConfigurationReloader struct {
+	client *http.Client
+}
+
+func newHTTPConfigurationReloader() ConfigurationReloader {
+	return &httpConfigurationReloader{client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (h *httpConfigurationReloader) Reload(jenkinsURL, token string) error {
+	query := url.Values{JenkinsCascReloadTokenParameter: []string{token}}
+	resp, err := h.client.Post(jenkinsURL+JenkinsCascReloadPath+"?"+query.Encode(), "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("configuration reload returned %s", resp.Status)
+	}
+	return nil
+}
+
+// jenkinsServiceURL returns the URL of the Jenkins web service inside the cluster
+func jenkinsServiceURL(cr *jenkinsv1alpha1.Jenkins) string {
+	return "http://" + cr.Name + "." + cr.Namespace + ".svc:" + strconv.Itoa(JenkinsWebPort)
+}
+
+// newCascVolumes returns the volumes and mounts of the ConfigMaps and Secrets holding the Configuration as Code.
+// Each source is mounted in its own directory under the directory read by the plugin.
+func newCascVolumes(cr *jenkinsv1alpha1.Jenkins) ([]corev1.Volume, []corev1.VolumeMount) {
+	volumes := []corev1.Volume{}
+	mounts := []corev1.VolumeMount{}
+	casc := cr.Spec.ConfigurationAsCode
+	if casc == nil {
+		return volumes, mounts
+	}
+	for i, ref := range casc.ConfigMaps {
+		name := JenkinsCascVolumePrefix + "configmap-" + strconv.Itoa(i)
+		volumes = append(volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: ref},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: JenkinsCascMountPath + "/configmaps/" + ref.Name, ReadOnly: true})
+	}
+	for i, ref := range casc.Secrets {
+		name := JenkinsCascVolumePrefix + "secret-" + strconv.Itoa(i)
+		volumes = append(volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: ref.Name},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: JenkinsCascMountPath + "/secrets/" + ref.Name, ReadOnly: true})
+	}
+	return volumes, mounts
+}
+
+// newCascEnvVars returns the variables pointing the plugin to the mounted configuration and reload token
+func newCascEnvVars(cr *jenkinsv1alpha1.Jenkins) []corev1.EnvVar {
+	if cr.Spec.ConfigurationAsCode == nil {
+		return []corev1.EnvVar{}
+	}
+	return []corev1.EnvVar{
+		{Name: JenkinsCascConfigEnv, Value: JenkinsCascMountPath},
+		{
+			Name: JenkinsCascReloadTokenEnv,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: cr.Name + JenkinsCascReloadSecretSuffix},
+					Key:                  JenkinsCascReloadTokenKey,
+				},
+			},
+		},
+	}
+}
+
+// newJenkinsCascReloadSecret returns the Secret holding the token allowing the operator to reload the
+// configuration. The token is only generated when the Secret is created.
+func newJenkinsCascReloadSecret(cr *jenkinsv1alpha1.Jenkins) *corev1.Secret {
+	token := make([]byte, 24)
+	rand.Read(token)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + JenkinsCascReloadSecretSuffix,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				JenkinsAppLabel: cr.Name,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			JenkinsCascReloadTokenKey: []byte(hex.EncodeToString(token)),
+		},
+	}
+}
+
+// reconcileConfigurationAsCode reloads the Configuration as Code of a running Jenkins when the content of its
+// ConfigMaps and Secrets changed, and returns the delay after which the request must be processed again.
+// The configuration read by Jenkins at startup is considered as applied.
+func (rc *ReconcileContext) reconcileConfigurationAsCode() time.Duration {
+	instance := rc.ControlledResources.JenkinsInstance
+	casc := instance.Spec.ConfigurationAsCode
+	if casc == nil {
+		rc.ConfigurationAsCodeStatus = nil
+		return 0
+	}
+	status := &jenkinsv1alpha1.JenkinsConfigurationAsCodeStatus{}
+	if instance.Status.ConfigurationAsCode != nil {
+		status = instance.Status.ConfigurationAsCode.DeepCopy()
+	}
+	rc.ConfigurationAsCodeStatus = status
+
+	hash, err := rc.configurationAsCodeHash(casc)
+	if err != nil {
+		rc.Diagnostics.Error(err, "Cannot read the Configuration as Code")
+		return ConfigurationReloadRetryDelay
+	}
+	if hash == status.AppliedHash || len(status.AppliedHash) == 0 {
+		status.AppliedHash = hash
+		status.PendingHash = ""
+		status.PendingSince = nil
+		return 0
+	}
+
+	now := metav1.Now()
+	if status.PendingHash != hash {
+		status.PendingHash = hash
+		status.PendingSince = &now
+	}
+	if wait := ConfigurationReloadDelay - now.Sub(status.PendingSince.Time); wait > 0 {
+		return wait
+	}
+	if !rc.workloadAvailable() {
+		rc.Diagnostics.Info("Jenkins is not available, postponing the Configuration as Code reload")
+		return ConfigurationReloadRetryDelay
+	}
+	token := ""
+	if secret := rc.ControlledResources.CascReloadSecret; secret != nil {
+		token = string(secret.Data[JenkinsCascReloadTokenKey])
+	}
+	if err := rc.Reloader.Reload(jenkinsServiceURL(instance), token); err != nil {
+		rc.Diagnostics.Warning("Configuration as Code reload failed", "error", err.Error())
+		status.ReloadError = err.Error()
+		return ConfigurationReloadRetryDelay
+	}
+	status.AppliedHash = hash
+	status.PendingHash = ""
+	status.PendingSince = nil
+	status.LastReloadTime = &now
+	status.ReloadError = ""
+	return 0
+}
+
+// configurationAsCodeHash returns the hash of the content of the ConfigMaps and Secrets of the configuration
+func (rc *ReconcileContext) configurationAsCodeHash(casc *jenkinsv1alpha1.JenkinsConfigurationAsCode) (string, error) {
+	namespace := rc.ControlledResources.JenkinsInstance.Namespace
+	h := sha256.New()
+	for _, ref := range casc.ConfigMaps {
+		configMap := &corev1.ConfigMap{}
+		if err := rc.Client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: ref.Name}, configMap); err != nil {
+			return "", fmt.Errorf("cannot read ConfigMap %s of configurationAsCode: %v", ref.Name, err)
+		}
+		data := map[string][]byte{}
+		for key, value := range configMap.Data {
+			data[key] = []byte(value)
+		}
+		for key, value := range configMap.BinaryData {
+			data[key] = value
+		}
+		writeHashEntries(h, "configmap/"+ref.Name, data)
+	}
+	for _, ref := range casc.Secrets {
+		secret := &corev1.Secret{}
+		if err := rc.Client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+			return "", fmt.Errorf("cannot read Secret %s of configurationAsCode: %v", ref.Name, err)
+		}
+		writeHashEntries(h, "secret/"+ref.Name, secret.Data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeHashEntries writes the entries of data sorted by key so that the hash does not depend on the map order
+func writeHashEntries(h hash.Hash, source string, data map[string][]byte) {
+	keys := []string{}
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(h, "%s\n", source)
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s\n%d\n", key, len(data[key]))
+		h.Write(data[key])
+	}
+}
+
+// workloadAvailable returns true when the workload of the instance has an available replica
+func (rc *ReconcileContext) workloadAvailable() bool {
+	var available int32
+	if rc.useDeploymentConfig() && rc.ControlledResources.DeploymentConfig != nil {
+		_, _, available = deploymentConfigReplicas(rc.ControlledResources.DeploymentConfig)
+	} else if rc.ControlledResources.Deployment != nil {
+		_, _, available = deploymentReplicas(rc.ControlledResources.Deployment)
+	}
+	return available > 0
+}
+
+// configurationAsCodeMapper enqueues the Jenkins instances of the namespace referencing a ConfigMap or Secret
+type configurationAsCodeMapper struct {
+	client client.Client
+}
+
+var _ handler.Mapper = &configurationAsCodeMapper{}
+
+func (m *configurationAsCodeMapper) Map(obj handler.MapObject) []reconcile.Request {
+	instances := &jenkinsv1alpha1.JenkinsList{}
+	if err := m.client.List(context.TODO(), client.InNamespace(obj.Meta.GetNamespace()), instances); err != nil {
+		logController.Error(err, "Cannot list the Jenkins instances referencing the Configuration as Code sources")
+		return nil
+	}
+	_, isSecret := obj.Object.(*corev1.Secret)
+	requests := []reconcile.Request{}
+	for _, instance := range instances.Items {
+		casc := instance.Spec.ConfigurationAsCode
+		if casc == nil {
+			continue
+		}
+		refs := casc.ConfigMaps
+		if isSecret {
+			refs = casc.Secrets
+		}
+		for _, ref := range refs {
+			if ref.Name == obj.Meta.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
@@ -0,0 +1,187 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	kappsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// fakeReloader records the reloads of the configuration
+type fakeReloader struct {
+	urls   []string
+	tokens []string
+	err    error
+}
+
+func (f *fakeReloader) Reload(jenkinsURL, token string) error {
+	f.urls = append(f.urls, jenkinsURL)
+	f.tokens = append(f.tokens, token)
+	return f.err
+}
+
+func newCascConfigMap(content string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: "casc"},
+		Data:       map[string]string{"jenkins.yaml": content},
+	}
+}
+
+func newCascJenkins() *jenkinsv1alpha1.Jenkins {
+	cr := mocks.JenkinsCRMock(test_ns, test_name)
+	cr.Spec.ConfigurationAsCode = &jenkinsv1alpha1.JenkinsConfigurationAsCode{
+		ConfigMaps: []corev1.LocalObjectReference{{Name: "casc"}},
+	}
+	return cr
+}
+
+func TestNewPodTemplateSpecConfigurationAsCode(t *testing.T) {
+	t.Run("TestSourcesAreMounted", func(t *testing.T) {
+		cr := newCascJenkins()
+		cr.Spec.ConfigurationAsCode.Secrets = []corev1.LocalObjectReference{{Name: "casc-credentials"}}
+		podTemplate := newPodTemplateSpec(cr, JenkinsImage, test_name, test_name+JenkinsJnlpServiceSuffix, false)
+
+		container := podTemplate.Spec.Containers[0]
+		require.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "jenkins-casc-configmap-0", MountPath: JenkinsCascMountPath + "/configmaps/casc", ReadOnly: true})
+		require.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "jenkins-casc-secret-0", MountPath: JenkinsCascMountPath + "/secrets/casc-credentials", ReadOnly: true})
+		require.Equal(t, "casc", podTemplate.Spec.Volumes[1].ConfigMap.Name)
+		require.Equal(t, "casc-credentials", podTemplate.Spec.Volumes[2].Secret.SecretName)
+		require.Contains(t, container.Env, corev1.EnvVar{Name: JenkinsCascConfigEnv, Value: JenkinsCascMountPath})
+		require.Equal(t, "jenkins-casc-configmap-0,jenkins-casc-secret-0", podTemplate.Annotations[JenkinsExtraVolumesAnnotation])
+		require.Equal(t, JenkinsCascConfigEnv+","+JenkinsCascReloadTokenEnv, podTemplate.Annotations[JenkinsExtraEnvAnnotation])
+	})
+}
+
+func TestReconcileConfigurationAsCode(t *testing.T) {
+	defer func(delay time.Duration) { ConfigurationReloadDelay = delay }(ConfigurationReloadDelay)
+
+	t.Run("TestReloadsChangedConfiguration", func(t *testing.T) {
+		ConfigurationReloadDelay = time.Minute
+		configMap := newCascConfigMap("jenkins:\n  systemMessage: hello\n")
+		r, c := newTestReconciler(newCascJenkins(), configMap)
+		reloader := &fakeReloader{}
+		r.Reloader = reloader
+
+		// the configuration read at startup is applied
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		cr := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		appliedHash := cr.Status.ConfigurationAsCode.AppliedHash
+		require.NotEmpty(t, appliedHash)
+		require.Empty(t, reloader.urls)
+		secret := &corev1.Secret{}
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: test_name + JenkinsCascReloadSecretSuffix}, secret))
+		require.NotEmpty(t, secret.Data[JenkinsCascReloadTokenKey])
+
+		// a change is only reloaded once the kubelet had time to update the volume
+		configMap.Data["jenkins.yaml"] = "jenkins:\n  systemMessage: updated\n"
+		require.NoError(t, c.Update(context.TODO(), configMap))
+		result, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= ConfigurationReloadDelay)
+		require.Empty(t, reloader.urls)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, appliedHash, cr.Status.ConfigurationAsCode.AppliedHash)
+		require.NotEmpty(t, cr.Status.ConfigurationAsCode.PendingHash)
+
+		// and when Jenkins is available
+		ConfigurationReloadDelay = 0
+		result, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, ConfigurationReloadRetryDelay, result.RequeueAfter)
+		require.Empty(t, reloader.urls)
+
+		deployment := &kappsv1.Deployment{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		deployment.Status.AvailableReplicas = 1
+		deployment.Status.UpdatedReplicas = 1
+		require.NoError(t, c.Status().Update(context.TODO(), deployment))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, []string{"http://" + test_name + "." + test_ns + ".svc:80"}, reloader.urls)
+		require.Equal(t, []string{string(secret.Data[JenkinsCascReloadTokenKey])}, reloader.tokens)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, cr.Status.ConfigurationAsCode.PendingHash, "")
+		require.NotEqual(t, appliedHash, cr.Status.ConfigurationAsCode.AppliedHash)
+		require.NotNil(t, cr.Status.ConfigurationAsCode.LastReloadTime)
+	})
+
+	t.Run("TestReloadErrorIsReported", func(t *testing.T) {
+		ConfigurationReloadDelay = 0
+		configMap := newCascConfigMap("jenkins: {}\n")
+		r, c := newTestReconciler(newCascJenkins(), configMap)
+		r.Reloader = &fakeReloader{err: errors.New("configuration reload returned 401 Unauthorized")}
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+
+		deployment := &kappsv1.Deployment{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		deployment.Status.AvailableReplicas = 1
+		require.NoError(t, c.Status().Update(context.TODO(), deployment))
+		configMap.Data["jenkins.yaml"] = "jenkins:\n  numExecutors: 0\n"
+		require.NoError(t, c.Update(context.TODO(), configMap))
+
+		result, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, ConfigurationReloadRetryDelay, result.RequeueAfter)
+		cr := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, "configuration reload returned 401 Unauthorized", cr.Status.ConfigurationAsCode.ReloadError)
+	})
+
+	t.Run("TestMissingConfigMapDegradesInstance", func(t *testing.T) {
+		r, c := newTestReconciler(newCascJenkins())
+		// retried with the rate limiter of the controller, and as soon as the ConfigMap is created
+		_, err := r.Reconcile(testRequest())
+		require.Error(t, err)
+		cr := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, corev1.ConditionTrue, cr.Status.GetCondition(jenkinsv1alpha1.JenkinsDegraded).Status)
+		require.Empty(t, cr.Status.ConfigurationAsCode.AppliedHash)
+	})
+}
+
+func TestHTTPConfigurationReloader(t *testing.T) {
+	t.Run("TestPostsReloadToken", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			require.Equal(t, http.MethodPost, req.Method)
+			require.Equal(t, JenkinsCascReloadPath, req.URL.Path)
+			if req.URL.Query().Get(JenkinsCascReloadTokenParameter) != "secret-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}))
+		defer server.Close()
+
+		reloader := newHTTPConfigurationReloader()
+		require.NoError(t, reloader.Reload(server.URL, "secret-token"))
+		require.EqualError(t, reloader.Reload(server.URL, "wrong-token"), "configuration reload returned 401 Unauthorized")
+	})
+}
+
+func TestConfigurationAsCodeMapper(t *testing.T) {
+	t.Run("TestMapsReferencingInstances", func(t *testing.T) {
+		other := mocks.JenkinsCRMock(test_ns, "other")
+		s := mocks.NewScheme()
+		mapper := &configurationAsCodeMapper{client: mocks.NewFakeClient(s, newCascJenkins(), other)}
+
+		configMap := newCascConfigMap("")
+		requests := mapper.Map(handler.MapObject{Meta: configMap, Object: configMap})
+		require.Len(t, requests, 1)
+		require.Equal(t, testRequest(), requests[0])
+
+		secret := &corev1.Secret{ObjectMeta: configMap.ObjectMeta}
+		require.Empty(t, mapper.Map(handler.MapObject{Meta: secret, Object: secret}))
+	})
+}
@@ -0,0 +1,18 @@
+package jenkins
+
+import (
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+)
+
+// SetJenkinsDefaults fills the fields of the spec left empty with the values the controller would use,
+// so that they are visible on the instance. It is called by the defaulting admission webhook.
+func SetJenkinsDefaults(cr *jenkinsv1alpha1.Jenkins, apis DiscoveredAPIs) {
+	persistence := &cr.Spec.Persistence
+	if persistence.Enabled && !usesExistingClaim(cr) && len(persistence.Size) == 0 {
+		persistence.Size = JenkinsPvcDefaultSize
+	}
+	// A DeploymentConfig is only created when the cluster serves the OpenShift apps API
+	if cr.Spec.UseDeploymentConfig && !apis.DeploymentConfig {
+		cr.Spec.UseDeploymentConfig = false
+	}
+}
@@ -0,0 +1,282 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/common"
+	j "github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/controllerutil"
+	kappsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// JenkinsFinalizer is set on every Jenkins instance so that the deletion policy is applied before it is removed
+	JenkinsFinalizer = "jenkins.dev/finalizer"
+
+	// Labels set on the resources which cannot be garbage collected through owner references
+	// (cluster scoped or living in another namespace) so that the finalizer can find them
+	JenkinsOwnerNamespaceLabel = "jenkins.dev/owner-namespace"
+	JenkinsOwnerNameLabel      = "jenkins.dev/owner-name"
+
+	// JenkinsRetainedFromAnnotation is set on the persistent volume claim kept by the Retain deletion policy
+	JenkinsRetainedFromAnnotation = "jenkins.dev/retained-from"
+
+	// FinalizerRequeueDelay is the delay between two checks of a running finalizer
+	FinalizerRequeueDelay = 5 * time.Second
+
+	// Reasons used in the Finalizing condition
+	ReasonScalingDownForSnapshot = "ScalingDownForSnapshot"
+	ReasonSnapshotInProgress     = "SnapshotInProgress"
+	ReasonSnapshotFailed         = "SnapshotFailed"
+)
+
+// externalOwnerLabels returns the labels identifying the resources of the instance which are not garbage collected
+func externalOwnerLabels(cr *jenkinsv1alpha1.Jenkins) map[string]string {
+	return map[string]string{
+		JenkinsOwnerNamespaceLabel: cr.Namespace,
+		JenkinsOwnerNameLabel:      cr.Name,
+	}
+}
+
+func deletionPolicy(cr *jenkinsv1alpha1.Jenkins) jenkinsv1alpha1.JenkinsDeletionPolicy {
+	if len(cr.Spec.DeletionPolicy) == 0 {
+		return jenkinsv1alpha1.JenkinsDeletionPolicyDelete
+	}
+	return cr.Spec.DeletionPolicy
+}
+
+// ensureFinalizer adds the Jenkins finalizer to the instance if it is missing
+func (rc *ReconcileContext) ensureFinalizer() error {
+	instance := rc.ControlledResources.JenkinsInstance
+	if !j.AddFinalizer(instance, JenkinsFinalizer) {
+		return nil
+	}
+	rc.Diagnostics.Info("Adding the finalizer", "finalizer", JenkinsFinalizer)
+	return rc.Client.Update(context.TODO(), instance)
+}
+
+// finalize applies the deletion policy of an instance being deleted, removes the resources which are not
+// garbage collected and finally releases the instance by removing the Jenkins finalizer.
+func (rc *ReconcileContext) finalize() (reconcile.Result, error) {
+	instance := rc.ControlledResources.JenkinsInstance
+	if !j.HasFinalizer(instance, JenkinsFinalizer) {
+		return reconcile.Result{}, nil
+	}
+	policy := deletionPolicy(instance)
+	rc.Diagnostics.Info("Applying the deletion policy", "deletionPolicy", policy)
+
+	switch policy {
+	case jenkinsv1alpha1.JenkinsDeletionPolicySnapshot:
+		done, reason, message, err := rc.snapshotJenkinsHome()
+		if err != nil {
+			rc.Diagnostics.Error(err, "Snapshot of JENKINS_HOME failed")
+			return reconcile.Result{}, err
+		}
+		if !done {
+			if err := rc.updateFinalizingStatus(reason, message); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{RequeueAfter: FinalizerRequeueDelay}, nil
+		}
+	case jenkinsv1alpha1.JenkinsDeletionPolicyRetain:
+		if err := rc.retainPersistentVolumeClaim(); err != nil {
+			rc.Diagnostics.Error(err, "Cannot retain the persistent volume claim")
+			return reconcile.Result{}, err
+		}
+	}
+
+	if err := rc.cleanupExternalResources(); err != nil {
+		rc.Diagnostics.Error(err, "Cannot remove the external resources")
+		return reconcile.Result{}, err
+	}
+
+	j.RemoveFinalizer(instance, JenkinsFinalizer)
+	if err := rc.Client.Update(context.TODO(), instance); err != nil && !kubeerrors.IsNotFound(err) {
+		rc.Diagnostics.Error(err, "Cannot remove the finalizer")
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+func (rc *ReconcileContext) updateFinalizingStatus(reason, message string) error {
+	instance := rc.ControlledResources.JenkinsInstance
+	status := instance.Status.DeepCopy()
+	status.Phase = jenkinsv1alpha1.JenkinsPhaseTerminating
+	status.SetCondition(jenkinsv1alpha1.JenkinsFinalizing, corev1.ConditionTrue, reason, message)
+	instance.Status = *status
+	err := rc.Client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		rc.Diagnostics.Error(err, "Cannot update the status of the Jenkins instance being deleted")
+	}
+	return err
+}
+
+// retainPersistentVolumeClaim removes the owner reference to the instance from its persistent volume claim,
+// so that the claim is not garbage collected. A Jenkins instance created later with the same name adopts it.
+func (rc *ReconcileContext) retainPersistentVolumeClaim() error {
+	instance := rc.ControlledResources.JenkinsInstance
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := rc.Client.Get(context.TODO(), types.NamespacedName{Namespace: instance.Namespace, Name: JenkinsClaimName(instance)}, pvc)
+	if kubeerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	ownerReferences := []metav1.OwnerReference{}
+	for _, ref := range pvc.OwnerReferences {
+		if ref.UID != instance.UID {
+			ownerReferences = append(ownerReferences, ref)
+		}
+	}
+	if len(ownerReferences) == len(pvc.OwnerReferences) {
+		return nil
+	}
+	rc.Diagnostics.Info("Releasing the persistent volume claim", "resource", common.ResourceName(pvc))
+	pvc.OwnerReferences = ownerReferences
+	if pvc.Annotations == nil {
+		pvc.Annotations = map[string]string{}
+	}
+	pvc.Annotations[JenkinsRetainedFromAnnotation] = instance.Namespace + "/" + instance.Name
+	return rc.Client.Update(context.TODO(), pvc)
+}
+
+// snapshotJenkinsHome stops Jenkins and archives JENKINS_HOME into a claim which survives the instance.
+// It returns true once the archive is complete, otherwise the reason and message describing the progress.
+func (rc *ReconcileContext) snapshotJenkinsHome() (bool, string, string, error) {
+	instance := rc.ControlledResources.JenkinsInstance
+	if !rc.isPersistent() {
+		// JENKINS_HOME lives in an emptyDir volume, there is nothing left to archive
+		return true, "", "", nil
+	}
+
+	// Jenkins must be stopped so that the archive is consistent and the volume can be mounted by the job
+	workload, err := rc.getCurrentWorkload()
+	if err != nil {
+		return false, "", "", err
+	}
+	if workload != nil {
+		if scaledDown, err := rc.scaleDownWorkload(workload); err != nil || !scaledDown {
+			return false, ReasonScalingDownForSnapshot, "Stopping Jenkins before archiving JENKINS_HOME", err
+		}
+	}
+	if running, err := rc.hasRunningJenkinsPods(); err != nil || running {
+		return false, ReasonScalingDownForSnapshot, "Waiting for the Jenkins pods to terminate", err
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	err = rc.Client.Get(context.TODO(), types.NamespacedName{Namespace: instance.Namespace, Name: JenkinsClaimName(instance)}, pvc)
+	if kubeerrors.IsNotFound(err) {
+		return true, "", "", nil
+	} else if err != nil {
+		return false, "", "", err
+	}
+	snapshotPvc := newJenkinsSnapshotPvc(instance, pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+	if err := rc.createIfNotExists(snapshotPvc); err != nil {
+		return false, "", "", err
+	}
+
+	image, err := rc.resolveJenkinsImage()
+	if err != nil {
+		return false, "", "", err
+	}
+	job := newJenkinsSnapshotJob(instance, image)
+	if err := controllerutil.SetControllerReference(instance, job, rc.Scheme); err != nil {
+		return false, "", "", err
+	}
+	if err := rc.createIfNotExists(job); err != nil {
+		return false, "", "", err
+	}
+	if job.Status.Succeeded > 0 {
+		rc.Diagnostics.Info("JENKINS_HOME archived", "resource", common.ResourceName(snapshotPvc))
+		return true, "", "", nil
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			// Keep the finalizer: the data is still there and the deletion policy can be changed to proceed
+			return false, ReasonSnapshotFailed, fmt.Sprintf("Job %s failed: %s. Change the deletionPolicy to complete the deletion",
+				job.Name, condition.Message), nil
+		}
+	}
+	return false, ReasonSnapshotInProgress, fmt.Sprintf("Archiving JENKINS_HOME into PersistentVolumeClaim %s", snapshotPvc.Name), nil
+}
+
+// getCurrentWorkload returns the Deployment or DeploymentConfig of the instance if it exists
+func (rc *ReconcileContext) getCurrentWorkload() (runtime.Object, error) {
+	instance := rc.ControlledResources.JenkinsInstance
+	var workload runtime.Object = &kappsv1.Deployment{}
+	if rc.useDeploymentConfig() {
+		workload = &appsv1.DeploymentConfig{}
+	}
+	err := rc.Client.Get(context.TODO(), types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}, workload)
+	if kubeerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return workload, err
+}
+
+// createIfNotExists creates obj when it is missing, otherwise it loads the live object into obj
+func (rc *ReconcileContext) createIfNotExists(obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	key := types.NamespacedName{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}
+	err = rc.Client.Get(context.TODO(), key, obj)
+	if kubeerrors.IsNotFound(err) {
+		rc.Diagnostics.Info("Creating the resource", "resource", common.ResourceName(obj))
+		err := rc.Client.Create(context.TODO(), obj)
+		rc.recordResourceEvent(obj, j.ActionCreate, err)
+		return err
+	}
+	return err
+}
+
+// cleanupExternalResources deletes the resources labelled as belonging to the instance which cannot be
+// garbage collected through owner references: cluster scoped resources and resources in other namespaces.
+// They are listed from the apiserver: the cache would wait for a watch the operator may not be allowed to
+// start instead of failing with Forbidden.
+func (rc *ReconcileContext) cleanupExternalResources() error {
+	instance := rc.ControlledResources.JenkinsInstance
+	opts := client.MatchingLabels(externalOwnerLabels(instance))
+	lists := []runtime.Object{
+		&rbacv1.RoleBindingList{},
+		&rbacv1.ClusterRoleBindingList{},
+		&rbacv1.ClusterRoleList{},
+	}
+	for _, list := range lists {
+		if err := rc.APIReader.List(context.TODO(), opts, list); err != nil {
+			if kubeerrors.IsForbidden(err) {
+				// The operator cannot have created resources it is not allowed to list
+				rc.Diagnostics.Warning("Cannot list the external resources", "list", fmt.Sprintf("%T", list), "error", err.Error())
+				continue
+			}
+			return err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			rc.Diagnostics.Info("Deleting the external resource", "resource", common.ResourceName(item))
+			if err := rc.Client.Delete(context.TODO(), item); err != nil && !kubeerrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	kappsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// deleteJenkins reconciles a persistent instance with the given deletion policy, then deletes it
+func deleteJenkins(t *testing.T, policy jenkinsv1alpha1.JenkinsDeletionPolicy) (*JenkinsReconciler, *mocks.FakeClient) {
+	cr := mocks.JenkinsCRMock(test_ns, test_name)
+	cr.Spec.Persistence.Enabled = true
+	cr.Spec.DeletionPolicy = policy
+	r, c := newTestReconciler(cr)
+
+	_, err := r.Reconcile(testRequest())
+	require.NoError(t, err)
+	require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+	require.Contains(t, cr.Finalizers, JenkinsFinalizer)
+
+	require.NoError(t, c.Delete(context.TODO(), cr))
+	return r, c
+}
+
+func testRequest() reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: test_ns, Name: test_name}}
+}
+
+func requireJenkinsDeleted(t *testing.T, c *mocks.FakeClient) {
+	err := c.Get(context.TODO(), testRequest().NamespacedName, &jenkinsv1alpha1.Jenkins{})
+	require.True(t, kubeerrors.IsNotFound(err))
+}
+
+func TestFinalizeDeletionPolicy(t *testing.T) {
+	t.Run("TestDeleteKeepsOwnerReference", func(t *testing.T) {
+		r, c := deleteJenkins(t, jenkinsv1alpha1.JenkinsDeletionPolicyDelete)
+		pvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, pvc))
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		requireJenkinsDeleted(t, c)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, pvc))
+		require.NotNil(t, metav1.GetControllerOf(pvc))
+	})
+
+	t.Run("TestRetainReleasesPvc", func(t *testing.T) {
+		r, c := deleteJenkins(t, jenkinsv1alpha1.JenkinsDeletionPolicyRetain)
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		requireJenkinsDeleted(t, c)
+		pvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, pvc))
+		require.Empty(t, pvc.OwnerReferences)
+		require.Equal(t, test_ns+"/"+test_name, pvc.Annotations[JenkinsRetainedFromAnnotation])
+	})
+
+	t.Run("TestSnapshotArchivesJenkinsHome", func(t *testing.T) {
+		r, c := deleteJenkins(t, jenkinsv1alpha1.JenkinsDeletionPolicySnapshot)
+		key := testRequest().NamespacedName
+		snapshotKey := types.NamespacedName{Namespace: test_ns, Name: test_name + JenkinsSnapshotSuffix}
+
+		// Jenkins is stopped first
+		result, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, FinalizerRequeueDelay, result.RequeueAfter)
+		deployment := &kappsv1.Deployment{}
+		require.NoError(t, c.Get(context.TODO(), key, deployment))
+		require.Equal(t, int32(0), *deployment.Spec.Replicas)
+		cr := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), key, cr))
+		require.Equal(t, jenkinsv1alpha1.JenkinsPhaseTerminating, cr.Status.Phase)
+
+		// then the archive job is started
+		result, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, FinalizerRequeueDelay, result.RequeueAfter)
+		job := &batchv1.Job{}
+		require.NoError(t, c.Get(context.TODO(), snapshotKey, job))
+		require.Equal(t, test_name, job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		require.Equal(t, snapshotKey.Name, job.Spec.Template.Spec.Volumes[1].PersistentVolumeClaim.ClaimName)
+		require.NoError(t, c.Get(context.TODO(), key, cr))
+		require.Equal(t, ReasonSnapshotInProgress, cr.Status.GetCondition(jenkinsv1alpha1.JenkinsFinalizing).Reason)
+
+		// and the instance is released once the archive is complete
+		job.Status.Succeeded = 1
+		require.NoError(t, c.Status().Update(context.TODO(), job))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		requireJenkinsDeleted(t, c)
+		snapshotPvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, c.Get(context.TODO(), snapshotKey, snapshotPvc))
+		require.Nil(t, metav1.GetControllerOf(snapshotPvc))
+	})
+
+	t.Run("TestSnapshotArchivesExistingClaim", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, ExistingClaim: "jenkins-home"}
+		cr.Spec.DeletionPolicy = jenkinsv1alpha1.JenkinsDeletionPolicySnapshot
+		cr.Spec.Image = &jenkinsv1alpha1.JenkinsImageSource{Reference: "quay.io/example/jenkins:2.235"}
+		claim := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: "jenkins-home"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")}},
+			},
+		}
+		r, c := newTestReconciler(cr, claim)
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.NoError(t, c.Delete(context.TODO(), cr))
+
+		snapshotKey := types.NamespacedName{Namespace: test_ns, Name: test_name + JenkinsSnapshotSuffix}
+		for i := 0; i < 2; i++ {
+			_, err = r.Reconcile(testRequest())
+			require.NoError(t, err)
+		}
+		job := &batchv1.Job{}
+		require.NoError(t, c.Get(context.TODO(), snapshotKey, job))
+		require.Equal(t, claim.Name, job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		require.Equal(t, "quay.io/example/jenkins:2.235", job.Spec.Template.Spec.Containers[0].Image)
+		snapshotPvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, c.Get(context.TODO(), snapshotKey, snapshotPvc))
+		size := snapshotPvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		require.Equal(t, "5Gi", size.String())
+
+		job.Status.Succeeded = 1
+		require.NoError(t, c.Status().Update(context.TODO(), job))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		requireJenkinsDeleted(t, c)
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: claim.Name}, claim))
+	})
+
+	t.Run("TestSnapshotFailureKeepsFinalizer", func(t *testing.T) {
+		r, c := deleteJenkins(t, jenkinsv1alpha1.JenkinsDeletionPolicySnapshot)
+		for i := 0; i < 2; i++ {
+			_, err := r.Reconcile(testRequest())
+			require.NoError(t, err)
+		}
+		job := &batchv1.Job{}
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: test_name + JenkinsSnapshotSuffix}, job))
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+		require.NoError(t, c.Status().Update(context.TODO(), job))
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		cr := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, ReasonSnapshotFailed, cr.Status.GetCondition(jenkinsv1alpha1.JenkinsFinalizing).Reason)
+
+		// switching to Retain completes the deletion
+		cr.Spec.DeletionPolicy = jenkinsv1alpha1.JenkinsDeletionPolicyRetain
+		require.NoError(t, c.Update(context.TODO(), cr))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		requireJenkinsDeleted(t, c)
+	})
+
+	t.Run("TestRemovesExternalResources", func(t *testing.T) {
+		r, c := deleteJenkins(t, jenkinsv1alpha1.JenkinsDeletionPolicyDelete)
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		external := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: test_name, Labels: externalOwnerLabels(cr)}}
+		clusterScoped := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: test_name, Labels: externalOwnerLabels(cr)}}
+		unrelated := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "unrelated"}}
+		for _, obj := range []*rbacv1.ClusterRoleBinding{clusterScoped, unrelated} {
+			require.NoError(t, c.Create(context.TODO(), obj))
+		}
+		require.NoError(t, c.Create(context.TODO(), external))
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		requireJenkinsDeleted(t, c)
+		err = c.Get(context.TODO(), types.NamespacedName{Namespace: "other", Name: test_name}, &rbacv1.RoleBinding{})
+		require.True(t, kubeerrors.IsNotFound(err))
+		err = c.Get(context.TODO(), types.NamespacedName{Name: test_name}, &rbacv1.ClusterRoleBinding{})
+		require.True(t, kubeerrors.IsNotFound(err))
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "unrelated"}, &rbacv1.ClusterRoleBinding{}))
+	})
+
+	t.Run("TestForbiddenExternalResourcesAreSkipped", func(t *testing.T) {
+		r, c := deleteJenkins(t, jenkinsv1alpha1.JenkinsDeletionPolicyDelete)
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		external := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: test_name, Labels: externalOwnerLabels(cr)}}
+		require.NoError(t, c.Create(context.TODO(), external))
+		apiServer := mocks.NewFakeClient(r.Scheme, external)
+		r.APIReader = apiServer
+		// the operator is not allowed to list the cluster scoped resources, the cache would never be synced
+		for _, kind := range []string{"ClusterRoleBinding", "ClusterRole"} {
+			apiServer.InjectFailure(mocks.VerbList, kind, "", kubeerrors.NewForbidden(
+				schema.GroupResource{Group: rbacv1.GroupName, Resource: strings.ToLower(kind) + "s"}, "", errors.New("cannot list")))
+			c.InjectFailure(mocks.VerbList, kind, "", errors.New("timed out waiting for the cache to sync"))
+		}
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		requireJenkinsDeleted(t, c)
+		err = c.Get(context.TODO(), types.NamespacedName{Namespace: "other", Name: test_name}, &rbacv1.RoleBinding{})
+		require.True(t, kubeerrors.IsNotFound(err))
+	})
+}
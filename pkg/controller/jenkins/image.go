@@ -0,0 +1,92 @@
+package jenkins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkinsimage"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// ImageTriggersAnnotation is read by the OpenShift image trigger controller to update the image of a
+	// Deployment when an ImageStreamTag changes
+	ImageTriggersAnnotation = "image.openshift.io/triggers"
+	ImageStreamTagKind      = "ImageStreamTag"
+)
+
+// imageTrigger is an item of the image.openshift.io/triggers annotation
+type imageTrigger struct {
+	From      corev1.ObjectReference `json:"from"`
+	FieldPath string                 `json:"fieldPath"`
+	Paused    bool                   `json:"paused,omitempty"`
+}
+
+// jenkinsImageStreamTag returns the ImageStreamTag the Jenkins container image comes from, if any
+func jenkinsImageStreamTag(cr *jenkinsv1alpha1.Jenkins) *corev1.ObjectReference {
+	if len(cr.Spec.JenkinsImageRef) > 0 {
+		return &corev1.ObjectReference{
+			Kind:      ImageStreamTagKind,
+			Name:      jenkinsimage.OutputImageStreamTag(cr.Spec.JenkinsImageRef),
+			Namespace: cr.Namespace,
+		}
+	}
+	source := cr.Spec.Image
+	if source == nil || len(source.ImageStreamTag) == 0 {
+		return nil
+	}
+	namespace := source.ImageStreamNamespace
+	if len(namespace) == 0 {
+		namespace = cr.Namespace
+	}
+	return &corev1.ObjectReference{Kind: ImageStreamTagKind, Name: source.ImageStreamTag, Namespace: namespace}
+}
+
+// newImageTriggersAnnotation returns the image.openshift.io/triggers annotation rolling out the Jenkins
+// container when the ImageStreamTag changes
+func newImageTriggersAnnotation(from corev1.ObjectReference) string {
+	triggers := []imageTrigger{
+		{
+			From:      from,
+			FieldPath: fmt.Sprintf("spec.template.spec.containers[?(@.name==\"%s\")].image", JenkinsContainerName),
+		},
+	}
+	annotation, _ := json.Marshal(triggers)
+	return string(annotation)
+}
+
+// resolveJenkinsImage returns the image of the Jenkins container defined in the cr. An ImageStreamTag, or the
+// output of a JenkinsImage, is resolved to the image it currently points to.
+func (rc *ReconcileContext) resolveJenkinsImage() (string, error) {
+	instance := rc.ControlledResources.JenkinsInstance
+	if instance.Spec.Image != nil && len(instance.Spec.Image.Reference) > 0 {
+		return instance.Spec.Image.Reference, nil
+	}
+	from := jenkinsImageStreamTag(instance)
+	if from == nil {
+		return JenkinsImage, nil
+	}
+	if !rc.APIs.ImageStream {
+		return "", fmt.Errorf("cannot resolve ImageStreamTag %s: the %s API is not available", from.Name, imagev1.GroupName)
+	}
+
+	if len(instance.Spec.JenkinsImageRef) > 0 {
+		image := &jenkinsv1alpha1.JenkinsImage{}
+		key := types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.JenkinsImageRef}
+		if err := rc.Client.Get(context.TODO(), key, image); err != nil {
+			return "", fmt.Errorf("cannot get JenkinsImage %s: %s", key.Name, err.Error())
+		}
+	}
+	tag := &imagev1.ImageStreamTag{}
+	if err := rc.APIReader.Get(context.TODO(), types.NamespacedName{Namespace: from.Namespace, Name: from.Name}, tag); err != nil {
+		return "", fmt.Errorf("cannot resolve ImageStreamTag %s/%s: %s", from.Namespace, from.Name, err.Error())
+	}
+	if len(tag.Image.DockerImageReference) == 0 {
+		return "", fmt.Errorf("ImageStreamTag %s/%s does not point to any image yet", from.Namespace, from.Name)
+	}
+	return tag.Image.DockerImageReference, nil
+}
@@ -6,26 +6,34 @@ import (
 	appsv1 "github.com/openshift/api/apps/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
-	common "github.com/redhat-developer/openshift-jenkins-operator/pkg/common"
 	kappsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	j "github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/controllerutil"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
 var (
-	logController      = logf.Log.WithName("jenkins/jenkins_controller.go")
-	controllerMessages = common.NewMessages("Jenkins Controller")
+	logController = logf.Log.WithName("jenkins/jenkins_controller.go")
 )
 
 const JenkinsControllerName = "jenkins-controller"
 
+// MaxConcurrentReconciles is the number of Jenkins instances which can be reconciled in parallel
+var MaxConcurrentReconciles = 2
+
 type ControlledResources struct {
 	JenkinsInstance       *jenkinsv1alpha1.Jenkins
 	DeploymentConfig      *appsv1.DeploymentConfig
@@ -36,25 +44,33 @@ type ControlledResources struct {
 	Volume                *corev1.Volume
 	PersistentVolumeClaim *corev1.PersistentVolumeClaim `json:",omitempty" bson:",omitempty"`
 	Route                 *routev1.Route
+	Ingress               *extensionsv1beta1.Ingress
 	RoleBinding           *rbacv1.RoleBinding
 	ServiceAccount        *corev1.ServiceAccount
+	CascReloadSecret      *corev1.Secret
+	AdminSecret           *corev1.Secret
+	BuiltinAuthConfigMap  *corev1.ConfigMap
+	BackupCronJob         *batchv1beta1.CronJob
 }
 
 
 // Add creates a new Jenkins Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	JenkinsReconciler := newReconciler(mgr, *controllerMessages)
+	JenkinsReconciler, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
 	return add(mgr, JenkinsReconciler)
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new Jenkins Controller
-	controllerMessages.LogInfo("Creating Jenkins Controller", logController)
-	c, err := controller.New(JenkinsControllerName, mgr, controller.Options{Reconciler: r})
+	logController.Info("Creating Jenkins Controller")
+	c, err := controller.New(JenkinsControllerName, mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: MaxConcurrentReconciles})
 	if err != nil {
-		controllerMessages.LogError(err, "Failed at creation of controller", logController)
+		logController.Error(err, "Failed at creation of controller")
 		return err
 	}
 
@@ -62,15 +78,18 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	ownerRef := &jenkinsv1alpha1.Jenkins{}
 
 	resourcesToWatch := []j.NamedResource{
-		j.NamedResource{ownerRef, ""},
-		j.NamedResource{&appsv1.DeploymentConfig{}, ""},
-		j.NamedResource{&kappsv1.Deployment{}, ""},
-		j.NamedResource{&imagev1.ImageStream{}, ""},
-		j.NamedResource{&corev1.ServiceAccount{}, ""},
-		j.NamedResource{&corev1.PersistentVolumeClaim{}, ""},
-		j.NamedResource{&routev1.Route{}, ""},
-		j.NamedResource{&rbacv1.RoleBinding{}, ""},
-		j.NamedResource{&corev1.ServiceAccount{}, ""},
+		{Object: ownerRef},
+		{Object: &appsv1.DeploymentConfig{}},
+		{Object: &kappsv1.Deployment{}},
+		{Object: &imagev1.ImageStream{}},
+		{Object: &corev1.Service{}},
+		{Object: &corev1.ServiceAccount{}},
+		{Object: &corev1.PersistentVolumeClaim{}},
+		{Object: &batchv1.Job{}},
+		{Object: &batchv1beta1.CronJob{}},
+		{Object: &routev1.Route{}},
+		{Object: &extensionsv1beta1.Ingress{}},
+		{Object: &rbacv1.RoleBinding{}},
 	}
 
 	for _, resource := range resourcesToWatch {
@@ -80,6 +99,43 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 		j.WatchResourceOrStackError(c, resource, ownerReference)
 	}
+
+	// Reload the Configuration as Code of the instances referencing a ConfigMap or Secret when it changes
+	mapper := &handler.EnqueueRequestsFromMapFunc{ToRequests: &configurationAsCodeMapper{client: mgr.GetClient()}}
+	for _, obj := range []runtime.Object{&corev1.ConfigMap{}, &corev1.Secret{}} {
+		if err := c.Watch(&source.Kind{Type: obj}, mapper); err != nil {
+			logController.Error(err, "Cannot watch Configuration as Code sources")
+			return err
+		}
+	}
+
+	// Update the Route of the instances using a Secret for its certificates when it changes
+	routeMapper := &handler.EnqueueRequestsFromMapFunc{ToRequests: &routeCertificateMapper{client: mgr.GetClient()}}
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, routeMapper); err != nil {
+		logController.Error(err, "Cannot watch Route certificates")
+		return err
+	}
+
+	// Restore the RoleBindings of the instances in the other namespaces, which cannot be owned by them
+	if err := c.Watch(&source.Kind{Type: &rbacv1.RoleBinding{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(externalOwnerMapper)}); err != nil {
+		logController.Error(err, "Cannot watch external RoleBindings")
+		return err
+	}
+
+	// Bind the roles in the namespaces selected by label as they are created, which requires watching all namespaces
+	if namespace, err := k8sutil.GetWatchNamespace(); err == nil && len(namespace) == 0 {
+		namespaceMapper := &handler.EnqueueRequestsFromMapFunc{ToRequests: &namespaceSelectorMapper{client: mgr.GetClient()}}
+		if err := c.Watch(&source.Kind{Type: &corev1.Namespace{}}, namespaceMapper); err != nil {
+			logController.Error(err, "Cannot watch namespaces")
+			return err
+		}
+	}
+
+	// Report the runs of the scheduled backups, whose jobs are owned by the backup CronJob
+	if err := c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(scheduledBackupMapper)}); err != nil {
+		logController.Error(err, "Cannot watch scheduled backup jobs")
+		return err
+	}
 	return nil
 }
 
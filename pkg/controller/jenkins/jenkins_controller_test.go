@@ -0,0 +1,447 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	kappsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newTestReconciler(objs ...runtime.Object) (*JenkinsReconciler, *mocks.FakeClient) {
+	s := mocks.NewScheme()
+	c := mocks.NewFakeClient(s, objs...)
+	return &JenkinsReconciler{Client: c, APIReader: c, Scheme: s, APIs: DiscoveredAPIs{Route: true}, Recorder: mocks.NewFakeRecorder()}, c
+}
+
+func TestReconcileConcurrently(t *testing.T) {
+	t.Run("TestInstancesDoNotShareState", func(t *testing.T) {
+		const instances = 20
+		objs := []runtime.Object{}
+		for i := 0; i < instances; i++ {
+			cr := mocks.JenkinsCRMock(test_ns, fmt.Sprintf("%s-%d", test_name, i))
+			cr.Spec.Persistence.Enabled = i%2 == 0
+			objs = append(objs, cr)
+		}
+		r, c := newTestReconciler(objs...)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, instances*2)
+		for i := 0; i < instances; i++ {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				// reconcile twice so that the second pass goes through the update path
+				for pass := 0; pass < 2; pass++ {
+					request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: test_ns, Name: name}}
+					if _, err := r.Reconcile(request); err != nil {
+						errs <- err
+					}
+				}
+			}(fmt.Sprintf("%s-%d", test_name, i))
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		for i := 0; i < instances; i++ {
+			name := fmt.Sprintf("%s-%d", test_name, i)
+			key := types.NamespacedName{Namespace: test_ns, Name: name}
+			cr := &jenkinsv1alpha1.Jenkins{}
+			require.NoError(t, c.Get(context.TODO(), key, cr))
+
+			deployment := &kappsv1.Deployment{}
+			require.NoError(t, c.Get(context.TODO(), key, deployment))
+			require.Equal(t, cr.UID, metav1.GetControllerOf(deployment).UID)
+			require.Equal(t, name, deployment.Spec.Template.Spec.ServiceAccountName)
+
+			volume := deployment.Spec.Template.Spec.Volumes[0]
+			if cr.Spec.Persistence.Enabled {
+				require.NotNil(t, volume.PersistentVolumeClaim)
+				require.Equal(t, name, volume.PersistentVolumeClaim.ClaimName)
+				pvc := &corev1.PersistentVolumeClaim{}
+				require.NoError(t, c.Get(context.TODO(), key, pvc))
+				require.Equal(t, cr.UID, metav1.GetControllerOf(pvc).UID)
+			} else {
+				require.Nil(t, volume.PersistentVolumeClaim)
+			}
+
+			for _, serviceName := range []string{name, name + JenkinsJnlpServiceSuffix} {
+				service := &corev1.Service{}
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: serviceName}, service))
+				require.Equal(t, name, service.Spec.Selector[JenkinsAppLabel])
+				require.Equal(t, cr.UID, metav1.GetControllerOf(service).UID)
+			}
+
+			require.Equal(t, name, cr.Status.Resources.Deployment)
+		}
+	})
+}
+
+func TestReconcileImage(t *testing.T) {
+	t.Run("TestResolvesImageStreamTag", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Image = &jenkinsv1alpha1.JenkinsImageSource{ImageStreamTag: "jenkins:2", ImageStreamNamespace: "openshift"}
+		tag := &imagev1.ImageStreamTag{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift", Name: "jenkins:2"},
+			Image:      imagev1.Image{DockerImageReference: "quay.io/openshift/jenkins@sha256:1234"},
+		}
+		r, c := newTestReconciler(cr)
+		r.APIs.ImageStream = true
+		// the tags cannot be watched, they are read from the apiserver instead of the cache
+		apiServer := mocks.NewFakeClient(r.Scheme)
+		r.APIReader = apiServer
+
+		// the workload is not created until the tag exists
+		_, err := r.Reconcile(testRequest())
+		require.Error(t, err)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), testRequest().NamespacedName, &kappsv1.Deployment{})))
+
+		require.NoError(t, apiServer.Create(context.TODO(), tag))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		deployment := &kappsv1.Deployment{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		require.Equal(t, tag.Image.DockerImageReference, deployment.Spec.Template.Spec.Containers[0].Image)
+	})
+
+	t.Run("TestResolvesJenkinsImageRef", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.JenkinsImageRef = "custom-jenkins"
+		image := &jenkinsv1alpha1.JenkinsImage{ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: "custom-jenkins"}}
+		tag := &imagev1.ImageStreamTag{
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: "custom-jenkins:latest"},
+			Image:      imagev1.Image{DockerImageReference: "image-registry.openshift-image-registry.svc:5000/test/custom-jenkins@sha256:5678"},
+		}
+		r, c := newTestReconciler(cr, image, tag)
+		r.APIs.ImageStream = true
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		deployment := &kappsv1.Deployment{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		require.Equal(t, tag.Image.DockerImageReference, deployment.Spec.Template.Spec.Containers[0].Image)
+		require.JSONEq(t, `[{"from":{"kind":"ImageStreamTag","namespace":"test","name":"custom-jenkins:latest"},
+			"fieldPath":"spec.template.spec.containers[?(@.name==\"jenkins\")].image"}]`,
+			deployment.Annotations[ImageTriggersAnnotation])
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, tag.Image.DockerImageReference, cr.Status.Image)
+
+		// the trigger is removed with the reference
+		cr.Spec.JenkinsImageRef = ""
+		require.NoError(t, c.Update(context.TODO(), cr))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		require.Equal(t, JenkinsImage, deployment.Spec.Template.Spec.Containers[0].Image)
+		require.NotContains(t, deployment.Annotations, ImageTriggersAnnotation)
+	})
+
+	t.Run("TestInvalidSpecIsReported", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Env = []corev1.EnvVar{{Name: JenkinsJavaOptsEnv, Value: "-Xmx1g"}}
+		r, c := newTestReconciler(cr)
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, jenkinsv1alpha1.JenkinsPhaseFailed, cr.Status.Phase)
+		require.Equal(t, ReasonInvalidSpec, cr.Status.GetCondition(jenkinsv1alpha1.JenkinsDegraded).Reason)
+		require.Equal(t, corev1.ConditionFalse, cr.Status.GetCondition(jenkinsv1alpha1.JenkinsValid).Status)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), testRequest().NamespacedName, &kappsv1.Deployment{})))
+		require.Contains(t, r.Recorder.(*mocks.FakeRecorder).Reasons(), "Warning "+ReasonInvalidSpec)
+
+		// the condition is cleared once the spec is fixed
+		cr.Spec.Env = nil
+		require.NoError(t, c.Update(context.TODO(), cr))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.True(t, cr.Status.IsConditionTrue(jenkinsv1alpha1.JenkinsValid))
+	})
+
+	t.Run("TestInvalidSizeDoesNotPanic", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, Size: "ten gigabytes"}
+		r, c := newTestReconciler(cr)
+
+		require.NotPanics(t, func() {
+			_, err := r.Reconcile(testRequest())
+			require.NoError(t, err)
+		})
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Contains(t, cr.Status.GetCondition(jenkinsv1alpha1.JenkinsValid).Message, "spec.persistence.size")
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), testRequest().NamespacedName, &corev1.PersistentVolumeClaim{})))
+		size := newJenkinsPvc(cr, test_name).Spec.Resources.Requests[corev1.ResourceStorage]
+		require.Equal(t, JenkinsPvcDefaultSize, size.String())
+	})
+}
+
+func TestReconcileIngress(t *testing.T) {
+	t.Run("TestIngressWithoutRouteAPI", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Ingress = &jenkinsv1alpha1.JenkinsIngress{
+			Host:             "jenkins.example.com",
+			IngressClassName: "nginx",
+			TLSSecretName:    "jenkins-tls",
+			Annotations:      map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "50m"},
+		}
+		r, c := newTestReconciler(cr)
+		r.APIs.Route = false
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		ingress := &extensionsv1beta1.Ingress{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, ingress))
+		require.Equal(t, test_name, metav1.GetControllerOf(ingress).Name)
+		require.Equal(t, "nginx", ingress.Annotations[IngressClassAnnotation])
+		require.Equal(t, "50m", ingress.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"])
+		require.Equal(t, "jenkins.example.com", ingress.Spec.Rules[0].Host)
+		require.Equal(t, test_name, ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName)
+		require.Equal(t, []extensionsv1beta1.IngressTLS{{Hosts: []string{"jenkins.example.com"}, SecretName: "jenkins-tls"}}, ingress.Spec.TLS)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), testRequest().NamespacedName, &routev1.Route{})))
+
+		// the URL is reported once an ingress controller serves the Ingress
+		ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+		require.NoError(t, c.Status().Update(context.TODO(), ingress))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, "https://jenkins.example.com", cr.Status.URL)
+		require.Equal(t, test_name, cr.Status.Resources.Ingress)
+		require.True(t, cr.Status.IsConditionTrue(jenkinsv1alpha1.JenkinsIngressAdmitted))
+	})
+
+	t.Run("TestIngressOnRequest", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Ingress = &jenkinsv1alpha1.JenkinsIngress{Enabled: true}
+		r, c := newTestReconciler(cr)
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, &routev1.Route{}))
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, &extensionsv1beta1.Ingress{}))
+
+		// the Ingress is removed when it is not requested anymore
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		cr.Spec.Ingress.Enabled = false
+		require.NoError(t, c.Update(context.TODO(), cr))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), testRequest().NamespacedName, &extensionsv1beta1.Ingress{})))
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Empty(t, cr.Status.Resources.Ingress)
+	})
+}
+
+func TestReconcileRoute(t *testing.T) {
+	t.Run("TestDefaultRoute", func(t *testing.T) {
+		r, c := newTestReconciler(mocks.JenkinsCRMock(test_ns, test_name))
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		route := &routev1.Route{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, route))
+		require.Empty(t, route.Spec.Host)
+		require.Equal(t, routev1.TLSTerminationEdge, route.Spec.TLS.Termination)
+		require.Equal(t, routev1.InsecureEdgeTerminationPolicyRedirect, route.Spec.TLS.InsecureEdgeTerminationPolicy)
+		require.Empty(t, route.Spec.TLS.Certificate)
+	})
+
+	t.Run("TestCustomRouteFollowsSpec", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Route = &jenkinsv1alpha1.JenkinsRoute{
+			Host:              "jenkins.example.com",
+			Termination:       jenkinsv1alpha1.JenkinsRouteTerminationReencrypt,
+			CertificateSecret: "jenkins-certificates",
+			Annotations:       map[string]string{"haproxy.router.openshift.io/timeout": "5m"},
+			Labels:            map[string]string{"router": "internal"},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: "jenkins-certificates"},
+			Data: map[string][]byte{
+				corev1.TLSCertKey:                []byte("certificate"),
+				corev1.TLSPrivateKeyKey:          []byte("key"),
+				RouteCACertificateKey:            []byte("ca"),
+				RouteDestinationCACertificateKey: []byte("destination ca"),
+			},
+		}
+		r, c := newTestReconciler(cr, secret)
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		route := &routev1.Route{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, route))
+		require.Equal(t, "jenkins.example.com", route.Spec.Host)
+		require.Equal(t, "5m", route.Annotations["haproxy.router.openshift.io/timeout"])
+		require.Equal(t, "internal", route.Labels["router"])
+		require.Equal(t, &routev1.TLSConfig{
+			Termination:                   routev1.TLSTerminationReencrypt,
+			Certificate:                   "certificate",
+			Key:                           "key",
+			CACertificate:                 "ca",
+			DestinationCACertificate:      "destination ca",
+			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+		}, route.Spec.TLS)
+
+		// changes of the spec and of the certificates are applied to the Route
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		cr.Spec.Route.Termination = jenkinsv1alpha1.JenkinsRouteTerminationEdge
+		cr.Spec.Route.Annotations["haproxy.router.openshift.io/timeout"] = "10m"
+		require.NoError(t, c.Update(context.TODO(), cr))
+		secret.Data[corev1.TLSCertKey] = []byte("renewed certificate")
+		require.NoError(t, c.Update(context.TODO(), secret))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, route))
+		require.Equal(t, routev1.TLSTerminationEdge, route.Spec.TLS.Termination)
+		require.Equal(t, "renewed certificate", route.Spec.TLS.Certificate)
+		require.Empty(t, route.Spec.TLS.DestinationCACertificate)
+		require.Equal(t, "10m", route.Annotations["haproxy.router.openshift.io/timeout"])
+	})
+
+	t.Run("TestMissingCertificatesAreReported", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Route = &jenkinsv1alpha1.JenkinsRoute{CertificateSecret: "missing"}
+		r, c := newTestReconciler(cr)
+
+		_, err := r.Reconcile(testRequest())
+		require.Error(t, err)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), testRequest().NamespacedName, &routev1.Route{})))
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.True(t, cr.Status.IsConditionTrue(jenkinsv1alpha1.JenkinsDegraded))
+	})
+}
+
+func TestRouteCertificateMapper(t *testing.T) {
+	t.Run("TestInstancesUsingSecretAreEnqueued", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Route = &jenkinsv1alpha1.JenkinsRoute{CertificateSecret: "jenkins-certificates"}
+		_, c := newTestReconciler(cr, mocks.JenkinsCRMock(test_ns, "other"))
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: "jenkins-certificates"}}
+
+		requests := (&routeCertificateMapper{client: c}).Map(handler.MapObject{Meta: secret, Object: secret})
+		require.Equal(t, []reconcile.Request{testRequest()}, requests)
+	})
+}
+
+func TestReconcileRecordsEvents(t *testing.T) {
+	t.Run("TestResourcesChangesAreRecorded", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Persistence.Enabled = true
+		r, c := newTestReconciler(cr)
+		recorder := r.Recorder.(*mocks.FakeRecorder)
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Contains(t, recorder.Events(), "Normal SuccessfulCreate Created PersistentVolumeClaim "+test_name)
+		require.Contains(t, recorder.Events(), "Normal SuccessfulCreate Created Service "+test_name+JenkinsJnlpServiceSuffix)
+
+		// nothing is recorded while the resources are up to date
+		recorded := len(recorder.Events())
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Len(t, recorder.Events(), recorded)
+
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		cr.Spec.JavaOpts = "-Xmx1g"
+		require.NoError(t, c.Update(context.TODO(), cr))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, []string{"Normal SuccessfulUpdate Updated Deployment " + test_name}, recorder.Events()[recorded:])
+	})
+}
+
+func TestReconcileFailures(t *testing.T) {
+	serverTimeout := kubeerrors.NewServerTimeout(schema.GroupResource{Resource: "jenkins"}, "get", 1)
+
+	t.Run("TestGetFailureIsReturned", func(t *testing.T) {
+		r, c := newTestReconciler(mocks.JenkinsCRMock(test_ns, test_name))
+		c.InjectFailure(mocks.VerbGet, "Jenkins", test_name, serverTimeout)
+
+		_, err := r.Reconcile(testRequest())
+		require.Equal(t, serverTimeout, err)
+	})
+
+	t.Run("TestCreateFailureIsReturnedAndReported", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Persistence.Enabled = true
+		r, c := newTestReconciler(cr)
+		forbidden := kubeerrors.NewForbidden(schema.GroupResource{Resource: "persistentvolumeclaims"}, test_name, fmt.Errorf("quota exceeded"))
+		c.InjectFailure(mocks.VerbCreate, "PersistentVolumeClaim", "", forbidden)
+
+		_, err := r.Reconcile(testRequest())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "quota exceeded")
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		degraded := cr.Status.GetCondition(jenkinsv1alpha1.JenkinsDegraded)
+		require.Equal(t, corev1.ConditionTrue, degraded.Status)
+		require.Contains(t, degraded.Message, "quota exceeded")
+		require.Contains(t, r.Recorder.(*mocks.FakeRecorder).Reasons(), "Warning FailedCreate")
+		// the other resources are reconciled anyway
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, &kappsv1.Deployment{}))
+
+		// the next reconcile succeeds once the API server accepts the claim
+		c.ClearFailures()
+		result, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, HealthCheckInterval, result.RequeueAfter)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, &corev1.PersistentVolumeClaim{}))
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, corev1.ConditionFalse, cr.Status.GetCondition(jenkinsv1alpha1.JenkinsDegraded).Status)
+	})
+
+	t.Run("TestUpdateFailureIsReturned", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		r, c := newTestReconciler(cr)
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		cr.Spec.JavaOpts = "-Xmx1g"
+		require.NoError(t, c.Update(context.TODO(), cr))
+		conflict := kubeerrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, test_name, fmt.Errorf("the object has been modified"))
+		c.InjectFailure(mocks.VerbUpdate, "Deployment", test_name, conflict)
+
+		_, err = r.Reconcile(testRequest())
+		require.Error(t, err)
+		require.True(t, kubeerrors.IsConflict(err.(utilerrors.Aggregate).Errors()[0]))
+		require.Contains(t, r.Recorder.(*mocks.FakeRecorder).Reasons(), "Warning FailedUpdate")
+	})
+
+	t.Run("TestStatusUpdateFailureIsReturned", func(t *testing.T) {
+		r, c := newTestReconciler(mocks.JenkinsCRMock(test_ns, test_name))
+		c.InjectFailure(mocks.VerbUpdateStatus, "Jenkins", "", serverTimeout)
+
+		_, err := r.Reconcile(testRequest())
+		require.Error(t, err)
+		// the managed resources are reconciled anyway
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, &kappsv1.Deployment{}))
+	})
+
+	t.Run("TestHealthyInstanceIsCheckedPeriodically", func(t *testing.T) {
+		r, _ := newTestReconciler(mocks.JenkinsCRMock(test_ns, test_name))
+
+		result, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, reconcile.Result{RequeueAfter: HealthCheckInterval}, result)
+	})
+}
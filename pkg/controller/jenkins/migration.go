@@ -0,0 +1,171 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/common"
+	j "github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/controllerutil"
+	kappsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// MigrationRequeueDelay is the delay between two checks of a workload migration
+	MigrationRequeueDelay = 5 * time.Second
+
+	// Reasons used in the Migrating condition
+	ReasonScalingDown       = "ScalingDownPreviousWorkload"
+	ReasonWaitingForPods    = "WaitingForPodsTermination"
+	ReasonDeletingWorkload  = "DeletingPreviousWorkload"
+	ReasonMigrationComplete = "MigrationComplete"
+)
+
+// migrateWorkload makes sure that only one kind of workload (Deployment or DeploymentConfig) runs for the
+// Jenkins instance. When the instance switched from one kind to the other, the previous workload is scaled
+// down, its pods are awaited and it is deleted before the new one is created, so that both never mount the
+// same ReadWriteOnce volume. The persistent volume claim is owned by the Jenkins instance and is never touched.
+// It returns true while the migration is in progress.
+func (rc *ReconcileContext) migrateWorkload() (bool, error) {
+	instance := rc.ControlledResources.JenkinsInstance
+	previous, err := rc.getPreviousWorkload()
+	if err != nil {
+		return false, err
+	}
+	if previous == nil {
+		if condition := instance.Status.GetCondition(jenkinsv1alpha1.JenkinsMigrating); condition != nil && condition.Status == corev1.ConditionTrue {
+			rc.setMigratingCondition(corev1.ConditionFalse, ReasonMigrationComplete, fmt.Sprintf("Jenkins now runs as a %s", rc.workloadKind()))
+		}
+		return false, nil
+	}
+
+	previousMeta := previous.(metav1.Object)
+	previousKind := kindOf(previous)
+	message := fmt.Sprintf("Migrating from %s %s to %s", previousKind, previousMeta.GetName(), rc.workloadKind())
+	rc.Diagnostics.Info("Migrating the workload", "migration", message)
+
+	if scaledDown, err := rc.scaleDownWorkload(previous); err != nil {
+		return true, err
+	} else if !scaledDown {
+		rc.setMigratingCondition(corev1.ConditionTrue, ReasonScalingDown, message)
+		return true, nil
+	}
+
+	if running, err := rc.hasRunningJenkinsPods(); err != nil {
+		return true, err
+	} else if running {
+		rc.setMigratingCondition(corev1.ConditionTrue, ReasonWaitingForPods, message)
+		return true, nil
+	}
+
+	rc.setMigratingCondition(corev1.ConditionTrue, ReasonDeletingWorkload, message)
+	err = rc.Client.Delete(context.TODO(), previous, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !kubeerrors.IsNotFound(err) {
+		rc.recordResourceEvent(previous, j.ActionDelete, err)
+		return true, err
+	}
+	rc.recordResourceEvent(previous, j.ActionDelete, nil)
+	return true, nil
+}
+
+// setMigratingCondition records the Migrating condition, it is reported with the status of the instance
+func (rc *ReconcileContext) setMigratingCondition(status corev1.ConditionStatus, reason, message string) {
+	rc.MigratingCondition = &jenkinsv1alpha1.JenkinsCondition{
+		Type:    jenkinsv1alpha1.JenkinsMigrating,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// getPreviousWorkload returns the workload of the kind which is not used anymore, if it still exists and is
+// controlled by the Jenkins instance
+func (rc *ReconcileContext) getPreviousWorkload() (runtime.Object, error) {
+	instance := rc.ControlledResources.JenkinsInstance
+	var previous runtime.Object
+	if rc.useDeploymentConfig() {
+		previous = &kappsv1.Deployment{}
+	} else if rc.APIs.DeploymentConfig {
+		previous = &appsv1.DeploymentConfig{}
+	} else {
+		return nil, nil
+	}
+
+	key := types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}
+	if err := rc.Client.Get(context.TODO(), key, previous); err != nil {
+		if kubeerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !metav1.IsControlledBy(previous.(metav1.Object), instance) {
+		rc.Diagnostics.Warning("The previous workload is not controlled by the instance, leaving it alone",
+			"resource", common.ResourceName(previous))
+		return nil, nil
+	}
+	return previous, nil
+}
+
+// scaleDownWorkload sets the replicas of the workload to zero and returns true once no replica is left
+func (rc *ReconcileContext) scaleDownWorkload(workload runtime.Object) (bool, error) {
+	switch w := workload.(type) {
+	case *kappsv1.Deployment:
+		if w.Spec.Replicas == nil || *w.Spec.Replicas != 0 {
+			w.Spec.Replicas = int32Ptr(0)
+			return false, rc.Client.Update(context.TODO(), w)
+		}
+		return w.Status.Replicas == 0, nil
+	case *appsv1.DeploymentConfig:
+		if w.Spec.Replicas != 0 {
+			w.Spec.Replicas = 0
+			return false, rc.Client.Update(context.TODO(), w)
+		}
+		return w.Status.Replicas == 0, nil
+	}
+	return false, fmt.Errorf("unsupported workload %T", workload)
+}
+
+// hasRunningJenkinsPods returns true while pods of the Jenkins instance have not terminated
+func (rc *ReconcileContext) hasRunningJenkinsPods() (bool, error) {
+	instance := rc.ControlledResources.JenkinsInstance
+	pods := &corev1.PodList{}
+	labels := map[string]string{
+		JenkinsAppLabelName: instance.Name,
+		JenkinsNameLabel:    instance.Name,
+	}
+	opts := client.InNamespace(instance.Namespace).MatchingLabels(labels)
+	if err := rc.Client.List(context.TODO(), opts, pods); err != nil {
+		return false, err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (rc *ReconcileContext) workloadKind() string {
+	if rc.useDeploymentConfig() {
+		return "DeploymentConfig"
+	}
+	return "Deployment"
+}
+
+func kindOf(obj runtime.Object) string {
+	switch obj.(type) {
+	case *kappsv1.Deployment:
+		return "Deployment"
+	case *appsv1.DeploymentConfig:
+		return "DeploymentConfig"
+	}
+	return fmt.Sprintf("%T", obj)
+}
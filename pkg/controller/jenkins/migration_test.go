@@ -0,0 +1,145 @@
+package jenkins
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	kappsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newMigratingJenkins returns the test instance running as a DeploymentConfig when useDeploymentConfig is set,
+// as a Deployment otherwise
+func newMigratingJenkins(useDeploymentConfig bool) *jenkinsv1alpha1.Jenkins {
+	cr := mocks.JenkinsCRMock(test_ns, test_name)
+	cr.Spec.UseDeploymentConfig = useDeploymentConfig
+	return cr
+}
+
+// newPreviousDeployment returns a Deployment of the instance running the given replicas, controlled by the
+// instance when owned is set. The instance must have been created to have its uid.
+func newPreviousDeployment(cr *jenkinsv1alpha1.Jenkins, replicas int32, owned bool) *kappsv1.Deployment {
+	deployment := &kappsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: cr.Namespace, Name: cr.Name},
+		Spec:       kappsv1.DeploymentSpec{Replicas: int32Ptr(replicas)},
+		Status:     kappsv1.DeploymentStatus{Replicas: replicas},
+	}
+	if owned {
+		deployment.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(cr, jenkinsv1alpha1.SchemeGroupVersion.WithKind("Jenkins"))}
+	}
+	return deployment
+}
+
+func newJenkinsPod(cr *jenkinsv1alpha1.Jenkins, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cr.Namespace,
+			Name:      cr.Name + "-1-abcde",
+			Labels:    map[string]string{JenkinsAppLabelName: cr.Name, JenkinsNameLabel: cr.Name},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+// requireMigrating checks the Migrating condition of the test instance
+func requireMigrating(t *testing.T, c *mocks.FakeClient, status corev1.ConditionStatus, reason string) {
+	cr := &jenkinsv1alpha1.Jenkins{}
+	require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+	condition := cr.Status.GetCondition(jenkinsv1alpha1.JenkinsMigrating)
+	require.NotNil(t, condition)
+	require.Equal(t, status, condition.Status)
+	require.Equal(t, reason, condition.Reason)
+}
+
+func TestMigrateWorkload(t *testing.T) {
+	t.Run("TestDeploymentIsReplacedByDeploymentConfig", func(t *testing.T) {
+		cr := newMigratingJenkins(true)
+		r, c := newTestReconciler(cr, newJenkinsPod(cr, corev1.PodRunning))
+		r.APIs.DeploymentConfig = true
+		require.NoError(t, c.Create(context.TODO(), newPreviousDeployment(cr, 1, true)))
+		deployment := &kappsv1.Deployment{}
+		deploymentConfig := &appsv1.DeploymentConfig{}
+
+		// the Deployment is scaled down first
+		result, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, MigrationRequeueDelay, result.RequeueAfter)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		require.Equal(t, int32(0), *deployment.Spec.Replicas)
+		requireMigrating(t, c, corev1.ConditionTrue, ReasonScalingDown)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), testRequest().NamespacedName, deploymentConfig)))
+
+		// the pods of the Deployment are awaited once it has no replica left
+		deployment.Status.Replicas = 0
+		require.NoError(t, c.Status().Update(context.TODO(), deployment))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		requireMigrating(t, c, corev1.ConditionTrue, ReasonWaitingForPods)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), testRequest().NamespacedName, deploymentConfig)))
+
+		// the Deployment is deleted once its pods terminated
+		require.NoError(t, c.Status().Update(context.TODO(), newJenkinsPod(cr, corev1.PodSucceeded)))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), testRequest().NamespacedName, deployment)))
+		requireMigrating(t, c, corev1.ConditionTrue, ReasonDeletingWorkload)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), testRequest().NamespacedName, deploymentConfig)))
+
+		// the DeploymentConfig is created once the Deployment is gone
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		requireMigrating(t, c, corev1.ConditionFalse, ReasonMigrationComplete)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deploymentConfig))
+	})
+	t.Run("TestDeploymentConfigIsReplacedByDeployment", func(t *testing.T) {
+		cr := newMigratingJenkins(false)
+		r, c := newTestReconciler(cr)
+		r.APIs.DeploymentConfig = true
+		previous := &appsv1.DeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cr, jenkinsv1alpha1.SchemeGroupVersion.WithKind("Jenkins"))}},
+			Spec: appsv1.DeploymentConfigSpec{Replicas: 1},
+		}
+		require.NoError(t, c.Create(context.TODO(), previous))
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, previous))
+		require.Equal(t, int32(0), previous.Spec.Replicas)
+		requireMigrating(t, c, corev1.ConditionTrue, ReasonScalingDown)
+
+		// without replica nor pod left, the DeploymentConfig is deleted at once
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), testRequest().NamespacedName, previous)))
+		requireMigrating(t, c, corev1.ConditionTrue, ReasonDeletingWorkload)
+
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		requireMigrating(t, c, corev1.ConditionFalse, ReasonMigrationComplete)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, &kappsv1.Deployment{}))
+	})
+	t.Run("TestWorkloadOfAnotherControllerIsLeftAlone", func(t *testing.T) {
+		cr := newMigratingJenkins(true)
+		r, c := newTestReconciler(cr)
+		r.APIs.DeploymentConfig = true
+		require.NoError(t, c.Create(context.TODO(), newPreviousDeployment(cr, 1, false)))
+
+		for pass := 0; pass < 2; pass++ {
+			_, err := r.Reconcile(testRequest())
+			require.NoError(t, err)
+		}
+		deployment := &kappsv1.Deployment{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		require.Equal(t, int32(1), *deployment.Spec.Replicas)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Nil(t, cr.Status.GetCondition(jenkinsv1alpha1.JenkinsMigrating))
+	})
+}
@@ -1,12 +1,17 @@
 package jenkins
 
 import (
+	"strings"
+
 	appsv1 "github.com/openshift/api/apps/v1"
 	buildv1 "github.com/openshift/api/build/v1"
+	imagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
 	kappsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	resource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,20 +23,22 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
-var (
-	routeAPIFound            = false
-	deploymentConfigAPIFound = false
-	buildAPIFound            = false
-)
+// DiscoveredAPIs tells which of the optional OpenShift APIs are served by the cluster
+type DiscoveredAPIs struct {
+	Route            bool
+	DeploymentConfig bool
+	Build            bool
+	ImageStream      bool
+}
 
 // newDeploymentConfigForCR returns a jenkins DeploymentConfig with the same name/namespace as the cr
-func newJenkinsDeploymentConfig(cr *jenkinsv1alpha1.Jenkins, jenkinsService, jenkinsJNLPService string, isPersistent bool) *appsv1.DeploymentConfig {
+func newJenkinsDeploymentConfig(cr *jenkinsv1alpha1.Jenkins, image, jenkinsService, jenkinsJNLPService string, isPersistent bool) *appsv1.DeploymentConfig {
 	jenkinsInstanceName := cr.Name
 	labels := map[string]string{
 		JenkinsAppLabelName: cr.Name,
 		JenkinsNameLabel:    cr.Name,
 	}
-	podTemplate := newPodTemplateSpec(cr, jenkinsService, jenkinsJNLPService, isPersistent)
+	podTemplate := newPodTemplateSpec(cr, image, jenkinsService, jenkinsJNLPService, isPersistent)
 	dc := &appsv1.DeploymentConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      jenkinsInstanceName,
@@ -45,11 +52,24 @@ func newJenkinsDeploymentConfig(cr *jenkinsv1alpha1.Jenkins, jenkinsService, jen
 			Template: &podTemplate,
 		},
 	}
+	if from := jenkinsImageStreamTag(cr); from != nil {
+		dc.Spec.Triggers = appsv1.DeploymentTriggerPolicies{
+			{Type: appsv1.DeploymentTriggerOnConfigChange},
+			{
+				Type: appsv1.DeploymentTriggerOnImageChange,
+				ImageChangeParams: &appsv1.DeploymentTriggerImageChangeParams{
+					Automatic:      true,
+					ContainerNames: []string{JenkinsContainerName},
+					From:           *from,
+				},
+			},
+		}
+	}
 	return dc
 }
 
 // newJenkinsDeployment returns a jenkins Deployment with the same name/namespace as the cr
-func newJenkinsDeployment(cr *jenkinsv1alpha1.Jenkins, jenkinsService, jenkinsJNLPService string, isPersistent bool) *kappsv1.Deployment {
+func newJenkinsDeployment(cr *jenkinsv1alpha1.Jenkins, image, jenkinsService, jenkinsJNLPService string, isPersistent bool) *kappsv1.Deployment {
 	jenkinsInstanceName := cr.Name
 	labels := map[string]string{
 		JenkinsAppLabelName: cr.Name,
@@ -66,15 +86,24 @@ func newJenkinsDeployment(cr *jenkinsv1alpha1.Jenkins, jenkinsService, jenkinsJN
 			Replicas: int32Ptr(1),
 			Selector: selector,
 			Strategy: kappsv1.DeploymentStrategy{Type: kappsv1.RecreateDeploymentStrategyType},
-			Template: newPodTemplateSpec(cr, jenkinsService, jenkinsJNLPService, isPersistent),
+			Template: newPodTemplateSpec(cr, image, jenkinsService, jenkinsJNLPService, isPersistent),
 		},
 	}
+	if from := jenkinsImageStreamTag(cr); from != nil {
+		dc.Annotations = map[string]string{ImageTriggersAnnotation: newImageTriggersAnnotation(*from)}
+	}
 	return dc
 }
 
-func newEnvVars(jenkinsService string, jenkinsJNLPService string) []corev1.EnvVar {
+// newEnvVars returns the environment of the Jenkins container: the variables required by the operator,
+// then the JVM options and the additional variables of the cr, which override the defaults of the same name
+func newEnvVars(cr *jenkinsv1alpha1.Jenkins, jenkinsService string, jenkinsJNLPService string) []corev1.EnvVar {
+	enableOAuth := "true"
+	if isBuiltinAuthentication(cr) {
+		enableOAuth = "false"
+	}
 	envVars := []corev1.EnvVar{
-		corev1.EnvVar{Name: "OPENSHIFT_ENABLE_OAUTH", Value: "true"},
+		corev1.EnvVar{Name: "OPENSHIFT_ENABLE_OAUTH", Value: enableOAuth},
 		corev1.EnvVar{Name: "OPENSHIFT_ENABLE_REDIRECT_PROMPT", Value: "true"},
 		corev1.EnvVar{Name: "DISABLE_ADMINISTRATIVE_MONITORS", Value: "false"},
 		corev1.EnvVar{Name: "KUBERNETES_MASTER", Value: "https://kubernetes.default:443"},
@@ -85,42 +114,84 @@ func newEnvVars(jenkinsService string, jenkinsJNLPService string) []corev1.EnvVa
 		corev1.EnvVar{Name: "JENKINS_UC_INSECURE", Value: "false"},
 		corev1.EnvVar{Name: "USE_JAVA_VERSION", Value: "java-11"},
 	}
-	return envVars
+	if len(cr.Spec.JavaOpts) > 0 {
+		envVars = append(envVars, corev1.EnvVar{Name: JenkinsJavaOptsEnv, Value: cr.Spec.JavaOpts})
+	}
+	if len(cr.Spec.JenkinsOpts) > 0 {
+		envVars = append(envVars, corev1.EnvVar{Name: JenkinsOptsEnv, Value: cr.Spec.JenkinsOpts})
+	}
+	envVars = append(envVars, newCascEnvVars(cr)...)
+	envVars = append(envVars, newBuiltinAuthEnvVars(cr)...)
+	return mergeEnvVars(cr.Spec.Env, envVars)
 }
 
-func newPodTemplateSpec(cr *jenkinsv1alpha1.Jenkins, jenkinsService string, jenkinsJNLPService string, isPersistent bool) corev1.PodTemplateSpec {
+func newPodTemplateSpec(cr *jenkinsv1alpha1.Jenkins, image, jenkinsService string, jenkinsJNLPService string, isPersistent bool) corev1.PodTemplateSpec {
 	labels := map[string]string{
 		JenkinsAppLabelName: cr.Name,
 		JenkinsNameLabel:    cr.Name,
 	}
 	livenessProbe := newProbe("/login", 8080, 420, 240, 360)
+	overrideProbe(&livenessProbe, cr.Spec.LivenessProbe)
 	readinessProbe := newProbe("/login", 8080, 3, 240, 0)
-	jenkinsVolume := newVolume(isPersistent)
-	envVars := newEnvVars(jenkinsService, jenkinsJNLPService)
-	volumeMounts := []corev1.VolumeMount{{Name: JenkinsVolumeName, MountPath: JenkinsVolumeMountPath}}
+	overrideProbe(&readinessProbe, cr.Spec.ReadinessProbe)
+	jenkinsVolume := newVolume(cr, isPersistent)
+	envVars := newEnvVars(cr, jenkinsService, jenkinsJNLPService)
+	cascVolumes, cascMounts := newCascVolumes(cr)
+	authVolumes, authMounts := newBuiltinAuthVolumes(cr)
+	cascVolumes = append(cascVolumes, authVolumes...)
+	cascMounts = append(cascMounts, authMounts...)
+	volumeMounts := append([]corev1.VolumeMount{{Name: JenkinsVolumeName, MountPath: JenkinsVolumeMountPath}}, cascMounts...)
+	volumeMounts = append(volumeMounts, cr.Spec.VolumeMounts...)
+	volumes := append([]corev1.Volume{*jenkinsVolume}, cascVolumes...)
+	volumes = append(volumes, cr.Spec.Volumes...)
+	resources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse(JenkinsContainerMemory),
+		},
+	}
+	if cr.Spec.Resources != nil {
+		resources = *cr.Spec.Resources.DeepCopy()
+	}
+
+	extraEnv := []string{}
+	for _, env := range cr.Spec.Env {
+		extraEnv = append(extraEnv, env.Name)
+	}
+	for _, env := range envVars {
+		if env.Name == JenkinsJavaOptsEnv || env.Name == JenkinsOptsEnv || env.Name == JenkinsCascConfigEnv || env.Name == JenkinsCascReloadTokenEnv ||
+			env.Name == JenkinsAdminUsernameEnv || env.Name == JenkinsAdminPasswordEnv || env.Name == JenkinsAdminAPITokenEnv {
+			extraEnv = append(extraEnv, env.Name)
+		}
+	}
+	extraVolumes := []string{}
+	for _, volume := range append(cascVolumes, cr.Spec.Volumes...) {
+		extraVolumes = append(extraVolumes, volume.Name)
+	}
 
 	podTemplate := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels: labels,
+			// The additional env variables and volumes are recorded so that they can be removed with the cr fields
+			Annotations: map[string]string{
+				JenkinsExtraEnvAnnotation:     strings.Join(extraEnv, ","),
+				JenkinsExtraVolumesAnnotation: strings.Join(extraVolumes, ","),
+			},
 		},
 		Spec: corev1.PodSpec{
 			Containers: []corev1.Container{
 				{
-					Image:                  JenkinsImage,
+					Image:                  image,
 					Name:                   JenkinsContainerName,
 					VolumeMounts:           volumeMounts,
 					Env:                    envVars,
+					EnvFrom:                cr.Spec.EnvFrom,
 					LivenessProbe:          &livenessProbe,
 					ReadinessProbe:         &readinessProbe,
 					TerminationMessagePath: "/dev/termination-log",
-					Resources: corev1.ResourceRequirements{
-						Limits: corev1.ResourceList{
-							corev1.ResourceMemory: resource.MustParse("1Gi"),
-						},
-					},
+					Resources:              resources,
 				},
 			},
-			Volumes:            []corev1.Volume{*jenkinsVolume},
+			Volumes:            volumes,
 			ServiceAccountName: cr.Name,
 		},
 	}
@@ -145,6 +216,25 @@ func newProbe(path string, port int, initialDelaySeconds, timeoutSeconds, period
 	return probe
 }
 
+// overrideProbe sets the timings defined in the cr on the probe
+func overrideProbe(probe *corev1.Probe, override *jenkinsv1alpha1.JenkinsProbe) {
+	if override == nil {
+		return
+	}
+	if override.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *override.InitialDelaySeconds
+	}
+	if override.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *override.TimeoutSeconds
+	}
+	if override.PeriodSeconds != nil {
+		probe.PeriodSeconds = *override.PeriodSeconds
+	}
+	if override.FailureThreshold != nil {
+		probe.FailureThreshold = *override.FailureThreshold
+	}
+}
+
 // newJenkinsService creates new Service for Jenkins
 func newJenkinsService(cr *jenkinsv1alpha1.Jenkins, name string, port corev1.ServicePort) *corev1.Service {
 	labels := map[string]string{
@@ -163,32 +253,107 @@ func newJenkinsService(cr *jenkinsv1alpha1.Jenkins, name string, port corev1.Ser
 	return svc
 }
 
-func newJenkinsRoute(cr *jenkinsv1alpha1.Jenkins, svc *corev1.Service) *routev1.Route {
-	return &routev1.Route{
+// newJenkinsRoute returns the Route exposing the web service of the instance, customized by spec.route. The
+// certificates are read from the Secret referenced by spec.route, if any.
+func newJenkinsRoute(cr *jenkinsv1alpha1.Jenkins, svc *corev1.Service, certificates *corev1.Secret) *routev1.Route {
+	spec := cr.Spec.Route
+	if spec == nil {
+		spec = &jenkinsv1alpha1.JenkinsRoute{}
+	}
+	termination := routev1.TLSTerminationEdge
+	if spec.Termination == jenkinsv1alpha1.JenkinsRouteTerminationReencrypt {
+		termination = routev1.TLSTerminationReencrypt
+	}
+	route := &routev1.Route{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      cr.Name,
-			Namespace: cr.Namespace,
+			Name:        cr.Name,
+			Namespace:   cr.Namespace,
+			Annotations: mergeStringMap(nil, spec.Annotations),
+			Labels:      mergeStringMap(nil, spec.Labels),
 		},
 		Spec: routev1.RouteSpec{
+			Host: spec.Host,
 			TLS: &routev1.TLSConfig{
 				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
-				Termination:                   routev1.TLSTerminationEdge,
+				Termination:                   termination,
 			},
 			To: routev1.RouteTargetReference{
-				Kind: svc.Kind,
+				Kind: "Service",
 				Name: svc.Name,
 			},
 		},
 	}
+	if certificates != nil {
+		route.Spec.TLS.Certificate = string(certificates.Data[corev1.TLSCertKey])
+		route.Spec.TLS.Key = string(certificates.Data[corev1.TLSPrivateKeyKey])
+		route.Spec.TLS.CACertificate = string(certificates.Data[RouteCACertificateKey])
+		if termination == routev1.TLSTerminationReencrypt {
+			route.Spec.TLS.DestinationCACertificate = string(certificates.Data[RouteDestinationCACertificateKey])
+		}
+	}
+	return route
 }
 
-func newVolume(isPersistent bool) *corev1.Volume {
+// newJenkinsIngress returns the Ingress routing the requests of the host of the instance to its web service
+func newJenkinsIngress(cr *jenkinsv1alpha1.Jenkins, svc *corev1.Service) *extensionsv1beta1.Ingress {
+	spec := cr.Spec.Ingress
+	if spec == nil {
+		spec = &jenkinsv1alpha1.JenkinsIngress{}
+	}
+	annotations := map[string]string{}
+	for key, value := range spec.Annotations {
+		annotations[key] = value
+	}
+	if len(spec.IngressClassName) > 0 {
+		annotations[IngressClassAnnotation] = spec.IngressClassName
+	}
+	ingress := &extensionsv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cr.Name,
+			Namespace:   cr.Namespace,
+			Annotations: annotations,
+			Labels: map[string]string{
+				JenkinsAppLabel: cr.Name,
+			},
+		},
+		Spec: extensionsv1beta1.IngressSpec{
+			Rules: []extensionsv1beta1.IngressRule{
+				{
+					Host: spec.Host,
+					IngressRuleValue: extensionsv1beta1.IngressRuleValue{
+						HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
+							Paths: []extensionsv1beta1.HTTPIngressPath{
+								{
+									Path: "/",
+									Backend: extensionsv1beta1.IngressBackend{
+										ServiceName: svc.Name,
+										ServicePort: intstr.FromString(JenkinsWebPortName),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if len(spec.TLSSecretName) > 0 {
+		tls := extensionsv1beta1.IngressTLS{SecretName: spec.TLSSecretName}
+		if len(spec.Host) > 0 {
+			tls.Hosts = []string{spec.Host}
+		}
+		ingress.Spec.TLS = []extensionsv1beta1.IngressTLS{tls}
+	}
+	return ingress
+}
+
+func newVolume(cr *jenkinsv1alpha1.Jenkins, isPersistent bool) *corev1.Volume {
 	volume := &corev1.Volume{}
 	volume.Name = JenkinsVolumeName
 
 	if isPersistent {
 		// Define PVC
-		volume.PersistentVolumeClaim = newJenkinsPvcVolumeSource()
+		volume.PersistentVolumeClaim = newJenkinsPvcVolumeSource(JenkinsClaimName(cr))
 	} else {
 		volume.EmptyDir = newJenkinsEmptyDirVolumeSource()
 	}
@@ -196,34 +361,108 @@ func newVolume(isPersistent bool) *corev1.Volume {
 	return volume
 }
 
-func newJenkinsPvcVolumeSource() *corev1.PersistentVolumeClaimVolumeSource {
+func newJenkinsPvcVolumeSource(claimName string) *corev1.PersistentVolumeClaimVolumeSource {
 	return &corev1.PersistentVolumeClaimVolumeSource{
-		ClaimName: JenkinsInstanceName,
+		ClaimName: claimName,
 	}
 }
 
 func newJenkinsPvc(cr *jenkinsv1alpha1.Jenkins, name string) *corev1.PersistentVolumeClaim {
-	JenkinsPvcSize := JenkinsPvcDefaultSize
+	persistence := cr.Spec.Persistence
 	accessModes := []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
-	if len(cr.Spec.Persistence.Size) > 0 {
-		JenkinsPvcSize = cr.Spec.Persistence.Size
+	if len(persistence.AccessModes) > 0 {
+		accessModes = append([]corev1.PersistentVolumeAccessMode{}, persistence.AccessModes...)
+	}
+	// The size is validated before the claim is built, fall back to the default instead of failing here
+	quantity, err := resource.ParseQuantity(claimSize(persistence))
+	if err != nil {
+		quantity = resource.MustParse(JenkinsPvcDefaultSize)
 	}
-	var quantity = resource.MustParse(JenkinsPvcSize)
 	resources := corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: quantity}}
+	// The storage class, access modes, volume mode and selector are immutable, they only apply to a new claim
 	pvc := &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: cr.Namespace,
+			Name:        name,
+			Namespace:   cr.Namespace,
+			Labels:      mergeStringMap(nil, persistence.Labels),
+			Annotations: mergeStringMap(nil, persistence.Annotations),
 		},
 		Spec: corev1.PersistentVolumeClaimSpec{
-			AccessModes: accessModes,
-			Resources:   resources,
+			AccessModes:      accessModes,
+			Resources:        resources,
+			StorageClassName: persistence.StorageClassName,
+			VolumeMode:       persistence.VolumeMode,
+			Selector:         persistence.Selector.DeepCopy(),
 		},
 	}
 
 	return pvc
 }
 
+// newJenkinsSnapshotPvc returns the claim receiving the final archive of JENKINS_HOME. It is not owned by the
+// Jenkins instance so that it survives its deletion.
+func newJenkinsSnapshotPvc(cr *jenkinsv1alpha1.Jenkins, size resource.Quantity) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + JenkinsSnapshotSuffix,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				JenkinsAppLabel: cr.Name,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources:   corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: size}},
+		},
+	}
+}
+
+// newJenkinsSnapshotJob returns a job archiving JENKINS_HOME from the claim of the instance into the snapshot claim,
+// running the Jenkins image of the instance
+func newJenkinsSnapshotJob(cr *jenkinsv1alpha1.Jenkins, image string) *batchv1.Job {
+	const snapshotMountPath = "/snapshot"
+	backoffLimit := int32(2)
+	command := "tar -czf " + snapshotMountPath + "/jenkins-home-$(date +%Y%m%d%H%M%S).tar.gz -C " + JenkinsVolumeMountPath + " ."
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + JenkinsSnapshotSuffix,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				JenkinsAppLabel: cr.Name,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    JenkinsSnapshotContainerName,
+							Image:   image,
+							Command: []string{"/bin/sh", "-c", command},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: JenkinsVolumeName, MountPath: JenkinsVolumeMountPath, ReadOnly: true},
+								{Name: JenkinsSnapshotVolumeName, MountPath: snapshotMountPath},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name:         JenkinsVolumeName,
+							VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: newJenkinsPvcVolumeSource(JenkinsClaimName(cr))},
+						},
+						{
+							Name:         JenkinsSnapshotVolumeName,
+							VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: newJenkinsPvcVolumeSource(cr.Name + JenkinsSnapshotSuffix)},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func newJenkinsEmptyDirVolumeSource() *corev1.EmptyDirVolumeSource {
 	return &corev1.EmptyDirVolumeSource{
 		Medium: corev1.StorageMediumDefault,
@@ -235,16 +474,24 @@ func newJenkinsServiceAccount(cr *jenkinsv1alpha1.Jenkins, name string) *corev1.
 		JenkinsAppLabel:  cr.Name,
 		JenkinsNameLabel: JenkinsServiceName,
 	}
-	annotationKey := "serviceaccounts.openshift.io/oauth-redirectreference." + cr.Name
-	annotationValue := "{\"kind\":\"OAuthRedirectReference\",\"apiVersion\":\"v1\",\"reference\":{\"kind\":\"Route\",\"name\":\"" + cr.Name + "\"}}"
+	// The OAuth redirect lets the users of the cluster log in Jenkins, it is not needed with the builtin authentication
+	annotations := map[string]string{}
+	if cr.Spec.ServiceAccount != nil {
+		for key, value := range cr.Spec.ServiceAccount.Annotations {
+			annotations[key] = value
+		}
+	}
+	if !isBuiltinAuthentication(cr) {
+		annotationKey := "serviceaccounts.openshift.io/oauth-redirectreference." + cr.Name
+		annotationValue := "{\"kind\":\"OAuthRedirectReference\",\"apiVersion\":\"v1\",\"reference\":{\"kind\":\"Route\",\"name\":\"" + cr.Name + "\"}}"
+		annotations[annotationKey] = annotationValue
+	}
 	return &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: cr.Namespace,
-			Labels:    labels,
-			Annotations: map[string]string{
-				annotationKey: annotationValue,
-			},
+			Name:        name,
+			Namespace:   cr.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
 		},
 	}
 }
@@ -260,15 +507,12 @@ func newJenkinsRoleBinding(cr *jenkinsv1alpha1.Jenkins, jenkinsServiceAccountNam
 			Namespace: cr.Namespace,
 			Labels:    labels,
 		},
-		RoleRef: rbacv1.RoleRef{
-			Kind:     "ClusterRole",
-			Name:     "edit",
-			APIGroup: "rbac.authorization.k8s.io",
-		},
+		RoleRef: JenkinsRoleRef(cr),
 		Subjects: []rbacv1.Subject{
 			{
-				Kind: "ServiceAccount",
-				Name: jenkinsServiceAccountName,
+				Kind:      "ServiceAccount",
+				Name:      jenkinsServiceAccountName,
+				Namespace: cr.Namespace,
 			},
 		},
 	}
@@ -304,34 +548,13 @@ func verifyAPI(group string, version string) (bool, error) {
 	return true, nil
 }
 
-func verifyRouteAPI() error {
-	found, err := verifyAPI(routev1.GroupName, routev1.SchemeGroupVersion.Version)
-	if err != nil {
-		return err
-	}
-	routeAPIFound = found
-	return nil
-}
-
-func verifyDeploymentConfigAPI() error {
-	found, err := verifyAPI(appsv1.GroupName, appsv1.SchemeGroupVersion.Version)
-	if err != nil {
-		return err
-	}
-	deploymentConfigAPIFound = found
-	return nil
-}
-
-func verifyBuildAPI() error {
-	found, err := verifyAPI(buildv1.GroupName, buildv1.SchemeGroupVersion.Version)
-	if err != nil {
-		return err
-	}
-	buildAPIFound = found
-	return nil
-}
-
-func verifyOpenshiftAPIs() {
-	verifyRouteAPI()
-	verifyDeploymentConfigAPI()
+// VerifyOpenshiftAPIs discovers which of the optional OpenShift APIs are available.
+// An API which cannot be verified is considered as missing.
+func VerifyOpenshiftAPIs() DiscoveredAPIs {
+	apis := DiscoveredAPIs{}
+	apis.Route, _ = verifyAPI(routev1.GroupName, routev1.SchemeGroupVersion.Version)
+	apis.DeploymentConfig, _ = verifyAPI(appsv1.GroupName, appsv1.SchemeGroupVersion.Version)
+	apis.Build, _ = verifyAPI(buildv1.GroupName, buildv1.SchemeGroupVersion.Version)
+	apis.ImageStream, _ = verifyAPI(imagev1.GroupName, imagev1.SchemeGroupVersion.Version)
+	return apis
 }
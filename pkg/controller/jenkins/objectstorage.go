@@ -0,0 +1,225 @@
+package jenkins
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/common"
+	j "github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/controllerutil"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/s3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// ObjectStorageBackupAnnotation requests a backup to the object storage every time its value changes
+	ObjectStorageBackupAnnotation = "jenkins.dev/object-storage-backup"
+	// ObjectStorageBackupSuffix is followed by the hash of the request in the name of the backup jobs
+	ObjectStorageBackupSuffix = "-object-storage-backup-"
+	// ObjectStorageArchiveLimit is the number of archives reported in the status
+	ObjectStorageArchiveLimit = 10
+
+	// OperatorImageEnv holds the image of the operator, which runs the backups to the object storage
+	OperatorImageEnv = "OPERATOR_IMAGE"
+	// OperatorCommand is the binary of the operator image
+	OperatorCommand = "openshift-jenkins-operator"
+)
+
+// ObjectStorageListTimeout bounds the requests listing the archives. The listing runs in the reconcile, an
+// unreachable object storage must not hold a worker of the controller for the timeout of the uploads.
+var ObjectStorageListTimeout = 20 * time.Second
+
+// objectStoragePrefix returns the prefix of the keys of the archives of the instance
+func objectStoragePrefix(cr *jenkinsv1alpha1.Jenkins) string {
+	if prefix := cr.Spec.ObjectStorage.Prefix; len(prefix) > 0 {
+		return prefix
+	}
+	return cr.Namespace + "/" + cr.Name + "/"
+}
+
+// objectStorageBackupJobName returns the name of the job running the backup requested by the annotation value
+func objectStorageBackupJobName(cr *jenkinsv1alpha1.Jenkins, request string) string {
+	sum := sha256.Sum256([]byte(request))
+	return cr.Name + ObjectStorageBackupSuffix + hex.EncodeToString(sum[:])[:8]
+}
+
+// newObjectStorageBackupJob returns the job archiving JENKINS_HOME to the object storage with the operator
+// image. The job runs on the node of Jenkins, which mounts the ReadWriteOnce volume of JENKINS_HOME.
+func newObjectStorageBackupJob(cr *jenkinsv1alpha1.Jenkins, request, image string) *batchv1.Job {
+	storage := cr.Spec.ObjectStorage
+	backoffLimit := int32(2)
+	credential := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: storage.CredentialsSecret},
+				Key:                  key,
+			},
+		}
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      objectStorageBackupJobName(cr, request),
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				JenkinsAppLabel: cr.Name,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    BackupContainerName,
+							Image:   image,
+							Command: []string{OperatorCommand, s3.BackupCommand},
+							Env: []corev1.EnvVar{
+								{Name: s3.EndpointEnv, Value: storage.Endpoint},
+								{Name: s3.RegionEnv, Value: storage.Region},
+								{Name: s3.BucketEnv, Value: storage.Bucket},
+								{Name: s3.PrefixEnv, Value: objectStoragePrefix(cr)},
+								{Name: s3.ExcludesEnv, Value: strings.Join(storage.Excludes, "\n")},
+								{Name: s3.AccessKeyIDEnv, ValueFrom: credential(s3.AccessKeyIDEnv)},
+								{Name: s3.SecretAccessKeyEnv, ValueFrom: credential(s3.SecretAccessKeyEnv)},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: JenkinsVolumeName, MountPath: JenkinsVolumeMountPath, ReadOnly: true},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						NewClaimVolume(JenkinsVolumeName, JenkinsClaimName(cr), true),
+					},
+					Affinity: &corev1.Affinity{
+						PodAffinity: &corev1.PodAffinity{
+							PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+								{Weight: ScheduledBackupAffinityWeight, PodAffinityTerm: NewJenkinsPodAffinityTerm(cr)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// reconcileObjectStorage starts the backup requested by the annotation of the instance, reports its result
+// and lists the archives of the object storage once it completed or when they were never listed.
+func (rc *ReconcileContext) reconcileObjectStorage() error {
+	instance := rc.ControlledResources.JenkinsInstance
+	if instance.Spec.ObjectStorage == nil {
+		rc.ObjectStorageStatus = nil
+		return nil
+	}
+	status := &jenkinsv1alpha1.JenkinsObjectStorageStatus{}
+	if instance.Status.ObjectStorage != nil {
+		status = instance.Status.ObjectStorage.DeepCopy()
+	}
+	rc.ObjectStorageStatus = status
+	listRequired := status.LastListTime == nil
+
+	if request := instance.Annotations[ObjectStorageBackupAnnotation]; len(request) > 0 && request != status.LastRequest {
+		if len(rc.OperatorImage) == 0 {
+			return fmt.Errorf("cannot start the backup to the object storage: %s is not set", OperatorImageEnv)
+		}
+		job := newObjectStorageBackupJob(instance, request, rc.OperatorImage)
+		if err := controllerutil.SetControllerReference(instance, job, rc.Scheme); err != nil {
+			return err
+		}
+		rc.Diagnostics.Info("Starting a backup to the object storage", "resource", common.ResourceName(job))
+		if err := rc.Client.Create(context.TODO(), job); err != nil && !kubeerrors.IsAlreadyExists(err) {
+			rc.recordResourceEvent(job, j.ActionCreate, err)
+			return err
+		} else if err == nil {
+			rc.recordResourceEvent(job, j.ActionCreate, nil)
+		}
+		status.LastRequest = request
+		status.LastBackup = &jenkinsv1alpha1.JenkinsBackupRun{Job: job.Name}
+	}
+
+	if run := status.LastBackup; run != nil && run.CompletionTime == nil {
+		job := &batchv1.Job{}
+		err := rc.Client.Get(context.TODO(), types.NamespacedName{Namespace: instance.Namespace, Name: run.Job}, job)
+		if kubeerrors.IsNotFound(err) {
+			now := metav1.Now()
+			run.CompletionTime = &now
+			run.Message = "Job " + run.Job + " was deleted before it completed"
+		} else if err != nil {
+			return err
+		} else if finished, failure := JobCompletion(job); finished {
+			run.StartTime = job.Status.StartTime
+			run.CompletionTime = jobFinishTime(job)
+			if len(failure) > 0 {
+				run.Message = JobFailureMessage(rc.Client, job, failure)
+			} else if result, err := ReadArchiveResult(rc.Client, job); err != nil {
+				rc.Diagnostics.Warning("Cannot read the result of a backup to the object storage", "error", err.Error())
+			} else {
+				run.Archive = result.Archive
+				run.Size = result.Size
+				run.Checksum = result.Checksum
+			}
+			listRequired = true
+		}
+	}
+
+	if listRequired {
+		archives, err := rc.listObjectStorageArchives(instance)
+		now := metav1.Now()
+		status.LastListTime = &now
+		if err != nil {
+			// The listing is not retried before the next backup, the error is only reported
+			rc.Diagnostics.Warning("Cannot list the archives of the object storage", "error", err.Error())
+			status.ListError = err.Error()
+		} else {
+			status.Archives = archives
+			status.ListError = ""
+		}
+	}
+	return nil
+}
+
+// listObjectStorageArchives returns the most recent archives of the instance found in the object storage
+func (rc *ReconcileContext) listObjectStorageArchives(cr *jenkinsv1alpha1.Jenkins) ([]jenkinsv1alpha1.JenkinsArchive, error) {
+	storage := cr.Spec.ObjectStorage
+	secret := &corev1.Secret{}
+	if err := rc.Client.Get(context.TODO(), types.NamespacedName{Namespace: cr.Namespace, Name: storage.CredentialsSecret}, secret); err != nil {
+		return nil, fmt.Errorf("cannot read the credentials of the object storage: %v", err)
+	}
+	credentials := s3.Credentials{
+		AccessKeyID:     string(secret.Data[s3.AccessKeyIDEnv]),
+		SecretAccessKey: string(secret.Data[s3.SecretAccessKeyEnv]),
+	}
+	client, err := s3.NewClient(storage.Endpoint, storage.Region, credentials)
+	if err != nil {
+		return nil, err
+	}
+	client.HTTPClient.Timeout = ObjectStorageListTimeout
+	objects, err := client.List(storage.Bucket, objectStoragePrefix(cr)+s3.ArchiveKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the archives of the object storage: %v", err)
+	}
+	sort.SliceStable(objects, func(i, j int) bool { return objects[i].LastModified.After(objects[j].LastModified) })
+	archives := []jenkinsv1alpha1.JenkinsArchive{}
+	for _, object := range objects {
+		if !strings.HasSuffix(object.Key, ArchiveExtension) {
+			continue
+		}
+		if len(archives) == ObjectStorageArchiveLimit {
+			break
+		}
+		lastModified := metav1.NewTime(object.LastModified)
+		archives = append(archives, jenkinsv1alpha1.JenkinsArchive{Key: object.Key, Size: object.Size, LastModified: &lastModified})
+	}
+	return archives, nil
+}
@@ -0,0 +1,151 @@
+package jenkins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/s3"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const testOperatorImage = "quay.io/redhat-developer/openshift-jenkins-operator:test"
+
+func newObjectStorageJenkins(endpoint string) *jenkinsv1alpha1.Jenkins {
+	cr := mocks.JenkinsCRMock(test_ns, test_name)
+	cr.Spec.Persistence.Enabled = true
+	cr.Spec.ObjectStorage = &jenkinsv1alpha1.JenkinsObjectStorage{
+		Endpoint:          endpoint,
+		Bucket:            "jenkins-backups",
+		CredentialsSecret: "s3-credentials",
+		Excludes:          []string{"workspace"},
+	}
+	return cr
+}
+
+func newObjectStorageSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: "s3-credentials"},
+		Data: map[string][]byte{
+			s3.AccessKeyIDEnv:     []byte("test-key"),
+			s3.SecretAccessKeyEnv: []byte("test-secret"),
+		},
+	}
+}
+
+func TestReconcileObjectStorage(t *testing.T) {
+	t.Run("TestAnnotationStartsBackupJob", func(t *testing.T) {
+		fake := mocks.NewFakeS3()
+		fake.AccessKeyID = "test-key"
+		server := httptest.NewServer(fake)
+		defer server.Close()
+		cr := newObjectStorageJenkins(server.URL)
+		cr.Annotations = map[string]string{ObjectStorageBackupAnnotation: "2020-01-01"}
+		r, c := newTestReconciler(cr, newObjectStorageSecret())
+		r.OperatorImage = testOperatorImage
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		job := &batchv1.Job{}
+		name := objectStorageBackupJobName(cr, "2020-01-01")
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: name}, job))
+		require.Equal(t, test_name, metav1.GetControllerOf(job).Name)
+		container := job.Spec.Template.Spec.Containers[0]
+		require.Equal(t, testOperatorImage, container.Image)
+		require.Equal(t, []string{OperatorCommand, s3.BackupCommand}, container.Command)
+		require.Contains(t, container.Env, corev1.EnvVar{Name: s3.PrefixEnv, Value: test_ns + "/" + test_name + "/"})
+		require.Contains(t, container.Env, corev1.EnvVar{Name: s3.ExcludesEnv, Value: "workspace"})
+		require.Equal(t, test_name, job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+
+		instance := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, instance))
+		require.Equal(t, "2020-01-01", instance.Status.ObjectStorage.LastRequest)
+		require.Equal(t, name, instance.Status.ObjectStorage.LastBackup.Job)
+		require.Nil(t, instance.Status.ObjectStorage.LastBackup.CompletionTime)
+	})
+	t.Run("TestMissingOperatorImageIsReported", func(t *testing.T) {
+		cr := newObjectStorageJenkins("http://minio:9000")
+		cr.Annotations = map[string]string{ObjectStorageBackupAnnotation: "now"}
+		r, _ := newTestReconciler(cr, newObjectStorageSecret())
+
+		_, err := r.Reconcile(testRequest())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), OperatorImageEnv)
+	})
+	t.Run("TestCompletedBackupIsReportedAndArchivesListed", func(t *testing.T) {
+		fake := mocks.NewFakeS3()
+		fake.AccessKeyID = "test-key"
+		prefix := test_ns + "/" + test_name + "/" + s3.ArchiveKeyPrefix
+		older := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		fake.PutObject("jenkins-backups", prefix+"20200101000000.tar.gz", []byte("old"), older)
+		fake.PutObject("jenkins-backups", prefix+"20200102000000.tar.gz", []byte("new"), older.Add(24*time.Hour))
+		fake.PutObject("jenkins-backups", prefix+"20200102000000.tar.gz.partial", []byte("partial"), older)
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		cr := newObjectStorageJenkins(server.URL)
+		cr.Annotations = map[string]string{ObjectStorageBackupAnnotation: "2020-01-02"}
+		name := objectStorageBackupJobName(cr, "2020-01-02")
+		cr.Status.ObjectStorage = &jenkinsv1alpha1.JenkinsObjectStorageStatus{
+			LastRequest:  "2020-01-02",
+			LastBackup:   &jenkinsv1alpha1.JenkinsBackupRun{Job: name},
+			LastListTime: &metav1.Time{Time: older},
+		}
+		job, pod := newScheduledBackupJob(name, older.Add(24*time.Hour), 0,
+			`{"archive":"`+prefix+`20200102000000.tar.gz","size":3,"checksum":"sha256:abc"}`)
+		r, c := newTestReconciler(cr, newObjectStorageSecret(), job, pod)
+		r.OperatorImage = testOperatorImage
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		instance := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, instance))
+		status := instance.Status.ObjectStorage
+		require.NotNil(t, status.LastBackup.CompletionTime)
+		require.Equal(t, prefix+"20200102000000.tar.gz", status.LastBackup.Archive)
+		require.Equal(t, "sha256:abc", status.LastBackup.Checksum)
+		require.Empty(t, status.ListError)
+		require.Len(t, status.Archives, 2)
+		require.Equal(t, prefix+"20200102000000.tar.gz", status.Archives[0].Key)
+		require.Equal(t, prefix+"20200101000000.tar.gz", status.Archives[1].Key)
+	})
+	t.Run("TestListErrorIsReported", func(t *testing.T) {
+		fake := mocks.NewFakeS3()
+		fake.AccessKeyID = "other-key"
+		server := httptest.NewServer(fake)
+		defer server.Close()
+		r, c := newTestReconciler(newObjectStorageJenkins(server.URL), newObjectStorageSecret())
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		instance := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, instance))
+		require.Contains(t, instance.Status.ObjectStorage.ListError, "AccessDenied")
+		require.NotNil(t, instance.Status.ObjectStorage.LastListTime)
+	})
+	t.Run("TestUnreachableObjectStorageTimesOut", func(t *testing.T) {
+		unblock := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			<-unblock
+		}))
+		defer server.Close()
+		defer close(unblock)
+		defer func(timeout time.Duration) { ObjectStorageListTimeout = timeout }(ObjectStorageListTimeout)
+		ObjectStorageListTimeout = 100 * time.Millisecond
+		r, c := newTestReconciler(newObjectStorageJenkins(server.URL), newObjectStorageSecret())
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		instance := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, instance))
+		require.Contains(t, instance.Status.ObjectStorage.ListError, "Timeout")
+	})
+}
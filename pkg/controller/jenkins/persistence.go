@@ -0,0 +1,117 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// JenkinsClaimName returns the name of the claim holding JENKINS_HOME of a persistent instance
+func JenkinsClaimName(cr *jenkinsv1alpha1.Jenkins) string {
+	if len(cr.Spec.Persistence.ExistingClaim) > 0 {
+		return cr.Spec.Persistence.ExistingClaim
+	}
+	return cr.Name
+}
+
+// usesExistingClaim returns true when JENKINS_HOME is stored in a claim which is not managed by the operator
+func usesExistingClaim(cr *jenkinsv1alpha1.Jenkins) bool {
+	return len(cr.Spec.Persistence.ExistingClaim) > 0
+}
+
+// getExistingClaim returns the claim referenced by persistence.existingClaim
+func (rc *ReconcileContext) getExistingClaim() (*corev1.PersistentVolumeClaim, error) {
+	instance := rc.ControlledResources.JenkinsInstance
+	pvc := &corev1.PersistentVolumeClaim{}
+	key := types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.Persistence.ExistingClaim}
+	if err := rc.Client.Get(context.TODO(), key, pvc); err != nil {
+		return nil, fmt.Errorf("cannot read the existing PersistentVolumeClaim %s: %v", key.Name, err)
+	}
+	return pvc, nil
+}
+
+// limitClaimExpansion keeps the requested size of the live claim when it is not allowed to grow, and returns
+// the reason why it cannot be expanded
+func (rc *ReconcileContext) limitClaimExpansion(desired *corev1.PersistentVolumeClaim) (string, error) {
+	live := &corev1.PersistentVolumeClaim{}
+	err := rc.Client.Get(context.TODO(), types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, live)
+	if kubeerrors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	desiredSize := desired.Spec.Resources.Requests[corev1.ResourceStorage]
+	liveSize := live.Spec.Resources.Requests[corev1.ResourceStorage]
+	if desiredSize.Cmp(liveSize) <= 0 {
+		return "", nil
+	}
+
+	expandable, err := rc.storageClassAllowsExpansion(live)
+	if err == nil && expandable {
+		return "", nil
+	}
+	desired.Spec.Resources.Requests[corev1.ResourceStorage] = liveSize
+	if err != nil {
+		return fmt.Sprintf("Cannot check whether PersistentVolumeClaim %s can be expanded to %s: %v", live.Name, desiredSize.String(), err), nil
+	}
+	return fmt.Sprintf("The StorageClass of PersistentVolumeClaim %s does not allow expanding it to %s", live.Name, desiredSize.String()), nil
+}
+
+// storageClassAllowsExpansion returns true when the StorageClass of the claim allows volume expansion
+func (rc *ReconcileContext) storageClassAllowsExpansion(pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if pvc.Spec.StorageClassName == nil || len(*pvc.Spec.StorageClassName) == 0 {
+		// Statically provisioned volumes cannot be expanded
+		return false, nil
+	}
+	// Read from the apiserver, caching the cluster scoped StorageClasses would require listing them
+	class := &storagev1.StorageClass{}
+	if err := rc.APIReader.Get(context.TODO(), types.NamespacedName{Name: *pvc.Spec.StorageClassName}, class); err != nil {
+		return false, err
+	}
+	return class.AllowVolumeExpansion != nil && *class.AllowVolumeExpansion, nil
+}
+
+// persistenceStatus reports the claim holding JENKINS_HOME and the progress of its expansion
+func (rc *ReconcileContext) persistenceStatus() *jenkinsv1alpha1.JenkinsPersistenceStatus {
+	instance := rc.ControlledResources.JenkinsInstance
+	if !rc.isPersistent() {
+		return nil
+	}
+	status := &jenkinsv1alpha1.JenkinsPersistenceStatus{ClaimName: JenkinsClaimName(instance)}
+	pvc := rc.ControlledResources.PersistentVolumeClaim
+	if pvc == nil {
+		return status
+	}
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	status.RequestedSize = requested.String()
+	capacity, bound := pvc.Status.Capacity[corev1.ResourceStorage]
+	if bound {
+		status.Capacity = capacity.String()
+	}
+
+	conditions := map[corev1.PersistentVolumeClaimConditionType]corev1.PersistentVolumeClaimCondition{}
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Status == corev1.ConditionTrue {
+			conditions[condition.Type] = condition
+		}
+	}
+	if condition, found := conditions[corev1.PersistentVolumeClaimFileSystemResizePending]; found {
+		status.Resize = jenkinsv1alpha1.JenkinsResizeFileSystemPending
+		status.Message = condition.Message
+	} else if condition, found := conditions[corev1.PersistentVolumeClaimResizing]; found {
+		status.Resize = jenkinsv1alpha1.JenkinsResizeInProgress
+		status.Message = condition.Message
+	} else if bound && pvc.Status.Phase == corev1.ClaimBound && requested.Cmp(capacity) > 0 {
+		status.Resize = jenkinsv1alpha1.JenkinsResizeInProgress
+		status.Message = fmt.Sprintf("Expanding the volume from %s to %s", capacity.String(), requested.String())
+	} else if len(rc.ClaimExpansionMessage) > 0 {
+		status.Resize = jenkinsv1alpha1.JenkinsResizeNotSupported
+		status.Message = rc.ClaimExpansionMessage
+	}
+	return status
+}
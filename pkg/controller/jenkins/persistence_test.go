@@ -0,0 +1,159 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	kappsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestStorageClass(name string, allowExpansion bool) *storagev1.StorageClass {
+	return &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: name},
+		Provisioner:          "kubernetes.io/test",
+		AllowVolumeExpansion: &allowExpansion,
+	}
+}
+
+// newBoundPvc returns the claim of the test instance bound to a volume of the given size
+func newBoundPvc(storageClass, size string) *corev1.PersistentVolumeClaim {
+	pvc := newJenkinsPvc(mocks.JenkinsCRMock(test_ns, test_name), test_name)
+	pvc.Spec.StorageClassName = &storageClass
+	pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(size)
+	pvc.Status.Phase = corev1.ClaimBound
+	pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)}
+	return pvc
+}
+
+func TestNewJenkinsPvcOptions(t *testing.T) {
+	cr := mocks.JenkinsCRMock(test_ns, test_name)
+	storageClass := "fast"
+	volumeMode := corev1.PersistentVolumeFilesystem
+	cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{
+		Enabled:          true,
+		Size:             "20Gi",
+		StorageClassName: &storageClass,
+		AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+		VolumeMode:       &volumeMode,
+		Selector:         &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "jenkins"}},
+		Labels:           map[string]string{"backup": "daily"},
+		Annotations:      map[string]string{"example.com/owner": "ci"},
+	}
+
+	pvc := newJenkinsPvc(cr, test_name)
+	require.Equal(t, "fast", *pvc.Spec.StorageClassName)
+	require.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, pvc.Spec.AccessModes)
+	require.Equal(t, corev1.PersistentVolumeFilesystem, *pvc.Spec.VolumeMode)
+	require.Equal(t, "jenkins", pvc.Spec.Selector.MatchLabels["tier"])
+	require.Equal(t, "daily", pvc.Labels["backup"])
+	require.Equal(t, "ci", pvc.Annotations["example.com/owner"])
+	size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	require.Equal(t, "20Gi", size.String())
+}
+
+func TestReconcileClaimExpansion(t *testing.T) {
+	claimKey := types.NamespacedName{Namespace: test_ns, Name: test_name}
+
+	t.Run("TestExpandableClaimGrows", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, Size: "5Gi"}
+		r, c := newTestReconciler(cr, newTestStorageClass("expandable", true), newBoundPvc("expandable", "1Gi"))
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		pvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, c.Get(context.TODO(), claimKey, pvc))
+		size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		require.Equal(t, "5Gi", size.String())
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, &jenkinsv1alpha1.JenkinsPersistenceStatus{
+			ClaimName:     test_name,
+			RequestedSize: "5Gi",
+			Capacity:      "1Gi",
+			Resize:        jenkinsv1alpha1.JenkinsResizeInProgress,
+			Message:       "Expanding the volume from 1Gi to 5Gi",
+		}, cr.Status.Persistence)
+	})
+	t.Run("TestClaimIsKeptWithoutExpansion", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, Size: "5Gi"}
+		r, c := newTestReconciler(cr, newTestStorageClass("fixed", false), newBoundPvc("fixed", "1Gi"))
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		pvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, c.Get(context.TODO(), claimKey, pvc))
+		size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		require.Equal(t, "1Gi", size.String())
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, jenkinsv1alpha1.JenkinsResizeNotSupported, cr.Status.Persistence.Resize)
+		require.Contains(t, cr.Status.Persistence.Message, "does not allow expanding it to 5Gi")
+	})
+	t.Run("TestForbiddenStorageClassKeepsTheClaim", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, Size: "5Gi"}
+		r, c := newTestReconciler(cr, newTestStorageClass("expandable", true), newBoundPvc("expandable", "1Gi"))
+		c.InjectFailure(mocks.VerbGet, "StorageClass", "expandable", kubeerrors.NewForbidden(
+			schema.GroupResource{Group: storagev1.GroupName, Resource: "storageclasses"}, "expandable", errors.New("cannot get")))
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		pvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, c.Get(context.TODO(), claimKey, pvc))
+		size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		require.Equal(t, "1Gi", size.String())
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, jenkinsv1alpha1.JenkinsResizeNotSupported, cr.Status.Persistence.Resize)
+		require.Contains(t, cr.Status.Persistence.Message, "Cannot check whether PersistentVolumeClaim test-jenkins can be expanded to 5Gi")
+		require.Contains(t, cr.Status.Persistence.Message, "forbidden")
+	})
+	t.Run("TestFileSystemResizePending", func(t *testing.T) {
+		pvc := newBoundPvc("expandable", "5Gi")
+		pvc.Status.Conditions = []corev1.PersistentVolumeClaimCondition{{
+			Type:    corev1.PersistentVolumeClaimFileSystemResizePending,
+			Status:  corev1.ConditionTrue,
+			Message: "Waiting for user to (re-)start a pod to finish file system resize of volume on node.",
+		}}
+		r, _ := newTestReconciler()
+		rc := r.newReconcileContext(testRequest())
+		rc.ControlledResources.JenkinsInstance = mocks.JenkinsCRMock(test_ns, test_name)
+		rc.ControlledResources.JenkinsInstance.Spec.Persistence.Enabled = true
+		rc.ControlledResources.PersistentVolumeClaim = pvc
+
+		status := rc.persistenceStatus()
+		require.Equal(t, jenkinsv1alpha1.JenkinsResizeFileSystemPending, status.Resize)
+		require.Equal(t, pvc.Status.Conditions[0].Message, status.Message)
+	})
+	t.Run("TestExistingClaimIsUsedAsIs", func(t *testing.T) {
+		existing := newBoundPvc("fixed", "50Gi")
+		existing.Name = "shared-home"
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, ExistingClaim: "shared-home"}
+		r, c := newTestReconciler(cr, existing)
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), claimKey, &corev1.PersistentVolumeClaim{})))
+		pvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: "shared-home"}, pvc))
+		require.Nil(t, metav1.GetControllerOf(pvc))
+		deployment := &kappsv1.Deployment{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, deployment))
+		require.Equal(t, "shared-home", deployment.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, "shared-home", cr.Status.Persistence.ClaimName)
+		require.Equal(t, "shared-home", cr.Status.Resources.PersistentVolumeClaim)
+		require.True(t, cr.Status.IsConditionTrue(jenkinsv1alpha1.JenkinsPersistenceReady))
+	})
+}
@@ -0,0 +1,158 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/common"
+	j "github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/controllerutil"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// DefaultJenkinsRole is the ClusterRole bound to the ServiceAccount of an instance by default
+	DefaultJenkinsRole = "edit"
+)
+
+// JenkinsRoleRef returns the role bound to the ServiceAccount of the instance
+func JenkinsRoleRef(cr *jenkinsv1alpha1.Jenkins) rbacv1.RoleRef {
+	roleRef := rbacv1.RoleRef{
+		Kind:     "ClusterRole",
+		Name:     DefaultJenkinsRole,
+		APIGroup: rbacv1.GroupName,
+	}
+	if cr.Spec.ServiceAccount != nil && cr.Spec.ServiceAccount.Role != nil {
+		roleRef.Kind = cr.Spec.ServiceAccount.Role.Kind
+		roleRef.Name = cr.Spec.ServiceAccount.Role.Name
+	}
+	return roleRef
+}
+
+// newJenkinsExternalRoleBinding returns the RoleBinding granting the role of the instance in another namespace.
+// It cannot be owned by the instance, it carries the owner labels instead so that it is removed by the finalizer.
+func newJenkinsExternalRoleBinding(cr *jenkinsv1alpha1.Jenkins, namespace string) *rbacv1.RoleBinding {
+	roleBinding := newJenkinsRoleBinding(cr, cr.Name)
+	roleBinding.Name = "jenkins_" + cr.Namespace + "_" + cr.Name
+	roleBinding.Namespace = namespace
+	roleBinding.Labels = mergeStringMap(roleBinding.Labels, externalOwnerLabels(cr))
+	return roleBinding
+}
+
+// boundNamespaces returns the sorted additional namespaces where the role of the instance is bound
+func (rc *ReconcileContext) boundNamespaces() ([]string, error) {
+	instance := rc.ControlledResources.JenkinsInstance
+	spec := instance.Spec.ServiceAccount
+	if spec == nil {
+		return []string{}, nil
+	}
+	found := map[string]bool{}
+	for _, namespace := range spec.Namespaces {
+		found[namespace] = true
+	}
+	if spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+		namespaces := &corev1.NamespaceList{}
+		if err := rc.Client.List(context.TODO(), &client.ListOptions{LabelSelector: selector}, namespaces); err != nil {
+			return nil, fmt.Errorf("cannot list the namespaces selected by serviceAccount.namespaceSelector: %v", err)
+		}
+		for _, namespace := range namespaces.Items {
+			if namespace.Status.Phase != corev1.NamespaceTerminating {
+				found[namespace.Name] = true
+			}
+		}
+	}
+	delete(found, instance.Namespace)
+	names := []string{}
+	for namespace := range found {
+		names = append(names, namespace)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// reconcileRoleBindings binds the role of the instance in the additional namespaces and removes the bindings
+// from the namespaces which are not selected anymore. It returns the namespaces where the role is bound.
+func (rc *ReconcileContext) reconcileRoleBindings() ([]string, error) {
+	instance := rc.ControlledResources.JenkinsInstance
+	namespaces, err := rc.boundNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	errs := []error{}
+	bound := []string{}
+	desired := map[string]bool{}
+	for _, namespace := range namespaces {
+		roleBinding := newJenkinsExternalRoleBinding(instance, namespace)
+		desired[namespace] = true
+		resource := j.RuntimeResource{Object: roleBinding, NamespacedName: types.NamespacedName{Namespace: namespace, Name: roleBinding.Name}}
+		if err := rc.createOrUpdateResource(resource); err != nil {
+			errs = append(errs, fmt.Errorf("cannot bind the role of the ServiceAccount in namespace %s: %v", namespace, err))
+			continue
+		}
+		bound = append(bound, namespace)
+	}
+
+	roleBindings := &rbacv1.RoleBindingList{}
+	if err := rc.Client.List(context.TODO(), client.MatchingLabels(externalOwnerLabels(instance)), roleBindings); err != nil {
+		return bound, utilerrors.NewAggregate(append(errs, err))
+	}
+	for i := range roleBindings.Items {
+		roleBinding := &roleBindings.Items[i]
+		if desired[roleBinding.Namespace] {
+			continue
+		}
+		rc.Diagnostics.Info("Deleting the unused RoleBinding", "resource", common.ResourceName(roleBinding))
+		if err := rc.Client.Delete(context.TODO(), roleBinding); err != nil && !kubeerrors.IsNotFound(err) {
+			rc.recordResourceEvent(roleBinding, j.ActionDelete, err)
+			errs = append(errs, err)
+		} else {
+			rc.recordResourceEvent(roleBinding, j.ActionDelete, nil)
+		}
+	}
+	return bound, utilerrors.NewAggregate(errs)
+}
+
+// externalOwnerMapper enqueues the instance owning a resource through the owner labels
+func externalOwnerMapper(obj handler.MapObject) []reconcile.Request {
+	labels := obj.Meta.GetLabels()
+	namespace, name := labels[JenkinsOwnerNamespaceLabel], labels[JenkinsOwnerNameLabel]
+	if len(namespace) == 0 || len(name) == 0 {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+}
+
+// namespaceSelectorMapper enqueues the Jenkins instances selecting additional namespaces by label
+type namespaceSelectorMapper struct {
+	client client.Client
+}
+
+var _ handler.Mapper = &namespaceSelectorMapper{}
+
+func (m *namespaceSelectorMapper) Map(obj handler.MapObject) []reconcile.Request {
+	instances := &jenkinsv1alpha1.JenkinsList{}
+	if err := m.client.List(context.TODO(), &client.ListOptions{}, instances); err != nil {
+		logController.Error(err, "Cannot list the Jenkins instances selecting namespaces")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, instance := range instances.Items {
+		if instance.Spec.ServiceAccount != nil && instance.Spec.ServiceAccount.NamespaceSelector != nil {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,113 @@
+package jenkins
+
+import (
+	"context"
+	"testing"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func newTestNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestReconcileRoleBindings(t *testing.T) {
+	externalName := "jenkins_" + test_ns + "_" + test_name
+
+	t.Run("TestRoleIsBoundInSelectedNamespaces", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.ServiceAccount = &jenkinsv1alpha1.JenkinsServiceAccount{
+			Role:              &jenkinsv1alpha1.JenkinsRoleRef{Kind: "ClusterRole", Name: "pipeline-deployer"},
+			Namespaces:        []string{"staging"},
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"jenkins.dev/target": "true"}},
+			Annotations:       map[string]string{"example.com/team": "ci"},
+		}
+		r, c := newTestReconciler(cr,
+			newTestNamespace("staging", nil),
+			newTestNamespace("production", map[string]string{"jenkins.dev/target": "true"}),
+			newTestNamespace("other", nil))
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		local := &rbacv1.RoleBinding{}
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: test_name + "_edit"}, local))
+		require.Equal(t, "pipeline-deployer", local.RoleRef.Name)
+		for _, namespace := range []string{"production", "staging"} {
+			roleBinding := &rbacv1.RoleBinding{}
+			require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: externalName}, roleBinding))
+			require.Nil(t, metav1.GetControllerOf(roleBinding))
+			require.Equal(t, externalOwnerLabels(cr), map[string]string{
+				JenkinsOwnerNamespaceLabel: roleBinding.Labels[JenkinsOwnerNamespaceLabel],
+				JenkinsOwnerNameLabel:      roleBinding.Labels[JenkinsOwnerNameLabel],
+			})
+			require.Equal(t, rbacv1.RoleRef{Kind: "ClusterRole", Name: "pipeline-deployer", APIGroup: rbacv1.GroupName}, roleBinding.RoleRef)
+			require.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Name: test_name, Namespace: test_ns}}, roleBinding.Subjects)
+		}
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Namespace: "other", Name: externalName}, &rbacv1.RoleBinding{})))
+
+		serviceAccount := &corev1.ServiceAccount{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, serviceAccount))
+		require.Equal(t, "ci", serviceAccount.Annotations["example.com/team"])
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		require.Equal(t, []string{"production", "staging"}, cr.Status.Resources.BoundNamespaces)
+	})
+
+	t.Run("TestUnselectedNamespacesAreCleanedUp", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.ServiceAccount = &jenkinsv1alpha1.JenkinsServiceAccount{Namespaces: []string{"staging", "production"}}
+		r, c := newTestReconciler(cr)
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		cr.Spec.ServiceAccount.Namespaces = []string{"staging"}
+		require.NoError(t, c.Update(context.TODO(), cr))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "staging", Name: externalName}, &rbacv1.RoleBinding{}))
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Namespace: "production", Name: externalName}, &rbacv1.RoleBinding{})))
+
+		// the finalizer removes the RoleBindings which are not garbage collected
+		require.NoError(t, c.Delete(context.TODO(), cr))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		requireJenkinsDeleted(t, c)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Namespace: "staging", Name: externalName}, &rbacv1.RoleBinding{})))
+	})
+
+	t.Run("TestRoleChangeRecreatesRoleBindings", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.ServiceAccount = &jenkinsv1alpha1.JenkinsServiceAccount{Namespaces: []string{"staging"}}
+		r, c := newTestReconciler(cr)
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, cr))
+		cr.Spec.ServiceAccount.Role = &jenkinsv1alpha1.JenkinsRoleRef{Kind: "Role", Name: "deployer"}
+		require.NoError(t, c.Update(context.TODO(), cr))
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		roleBinding := &rbacv1.RoleBinding{}
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "staging", Name: externalName}, roleBinding))
+		require.Equal(t, "Role", roleBinding.RoleRef.Kind)
+		require.Equal(t, "deployer", roleBinding.RoleRef.Name)
+	})
+}
+
+func TestExternalOwnerMapper(t *testing.T) {
+	roleBinding := newJenkinsExternalRoleBinding(mocks.JenkinsCRMock(test_ns, test_name), "staging")
+	requests := externalOwnerMapper(handler.MapObject{Meta: roleBinding, Object: roleBinding})
+	require.Len(t, requests, 1)
+	require.Equal(t, testRequest().NamespacedName, requests[0].NamespacedName)
+
+	other := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "staging", Name: "other"}}
+	require.Empty(t, externalOwnerMapper(handler.MapObject{Meta: other, Object: other}))
+}
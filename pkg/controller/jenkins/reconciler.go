@@ -2,10 +2,14 @@ package jenkins
 
 import (
 	"context"
+	"os"
+	"reflect"
+	"time"
 
 	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
 	common "github.com/redhat-developer/openshift-jenkins-operator/pkg/common"
 	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	j "github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/controllerutil"
 
@@ -15,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -23,8 +28,7 @@ import (
 )
 
 var (
-	JenkinsInstanceName = ""
-	logReconciler       = logf.Log.WithName("jenkins/reconciler.go")
+	logReconciler = logf.Log.WithName("jenkins/reconciler.go")
 )
 
 const (
@@ -55,37 +59,103 @@ const (
 	JenkinsPvcDefaultSize  = "1Gi"
 	JenkinsVolumeName      = "jenkins-data"
 	JenkinsVolumeMountPath = "/var/lib/jenkins"
+
+	JenkinsJavaOptsEnv            = "JAVA_OPTS"
+	JenkinsOptsEnv                = "JENKINS_OPTS"
+	JenkinsExtraEnvAnnotation     = "jenkins.dev/extra-env"
+	JenkinsExtraVolumesAnnotation = "jenkins.dev/extra-volumes"
+
+	JenkinsSnapshotSuffix        = "-snapshot"
+	JenkinsSnapshotVolumeName    = "jenkins-snapshot"
+	JenkinsSnapshotContainerName = "snapshot"
+
+	// IngressClassAnnotation selects the ingress controller serving an Ingress
+	IngressClassAnnotation = "kubernetes.io/ingress.class"
 )
 
 // ReconcileJenkins reconciles a Jenkins object
 type JenkinsReconciler struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	Client               client.Client
-	Scheme               *runtime.Scheme
-	Result               reconcile.Result
-	Request              reconcile.Request
-	ControlledRescources ControlledResources
-	Messages             common.Messages
+	Client client.Client
+	// APIReader reads from the apiserver the objects which are not cached, e.g. the ImageStreamTags which
+	// cannot be watched
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
+	// APIs holds the optional APIs discovered when the controller started
+	APIs DiscoveredAPIs
+	// Reloader reloads the Configuration as Code of the running instances
+	Reloader ConfigurationReloader
+	// OperatorImage is the image of the operator, which runs the backups to the object storage
+	OperatorImage string
+	// Recorder records the events of the instances
+	Recorder record.EventRecorder
+}
+
+// ReconcileContext holds the state of a single reconciliation of a Jenkins instance.
+// A new context is created for every request so that concurrent reconciles never share state.
+type ReconcileContext struct {
+	*JenkinsReconciler
+	Request             reconcile.Request
+	ControlledResources ControlledResources
+	// Diagnostics collects the errors and requeues of the reconcile and logs its steps
+	Diagnostics *common.Diagnostics
+	// ConfigurationAsCodeStatus is the Configuration as Code status computed during the reconcile
+	ConfigurationAsCodeStatus *jenkinsv1alpha1.JenkinsConfigurationAsCodeStatus
+	// BackupStatus is the status of the scheduled backups computed during the reconcile
+	BackupStatus *jenkinsv1alpha1.JenkinsBackupScheduleStatus
+	// ObjectStorageStatus is the status of the backups to the object storage computed during the reconcile
+	ObjectStorageStatus *jenkinsv1alpha1.JenkinsObjectStorageStatus
+	// BoundNamespaces are the additional namespaces where the role of the ServiceAccount was bound
+	BoundNamespaces []string
+	// ClaimExpansionMessage is the reason why the claim holding JENKINS_HOME cannot be expanded
+	ClaimExpansionMessage string
+	// MigratingCondition is the Migrating condition computed during the reconcile, nil when it is unchanged
+	MigratingCondition *jenkinsv1alpha1.JenkinsCondition
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager, messages common.Messages) reconcile.Reconciler {
-	return &JenkinsReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Messages: messages, ControlledRescources: ControlledResources{}}
+func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	apiReader, err := j.NewAPIReader(mgr)
+	if err != nil {
+		return nil, err
+	}
+	return &JenkinsReconciler{
+		Client:        mgr.GetClient(),
+		APIReader:     apiReader,
+		Scheme:        mgr.GetScheme(),
+		APIs:          VerifyOpenshiftAPIs(),
+		Reloader:      newHTTPConfigurationReloader(),
+		OperatorImage: os.Getenv(OperatorImageEnv),
+		Recorder:      mgr.GetRecorder(JenkinsControllerName),
+	}, nil
 }
 
+// newReconcileContext returns the context used to reconcile the given request
+func (r *JenkinsReconciler) newReconcileContext(request reconcile.Request) *ReconcileContext {
+	return &ReconcileContext{
+		JenkinsReconciler: r,
+		Request:           request,
+		Diagnostics:       common.NewDiagnostics(logReconciler, "Jenkins", request.NamespacedName),
+	}
+}
+
+// HealthCheckInterval is the delay after which a healthy instance is reconciled again, to report the state
+// of the resources which are not watched and to retry the steps which do not fail the reconcile
+var HealthCheckInterval = 10 * time.Minute
+
 /*
 	Reconciliation && Requeing Requests : Works on matching the current state of the resources to the expected state
 	The Controller will requeue the Request to be processed again if the returned error is non-nil
 */
 func (r *JenkinsReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	return r.newReconcileContext(request).reconcile()
+}
 
-	// Record Request and Jenkins Instance Name
-	r.Request = request
-	JenkinsInstanceName = request.NamespacedName.Name
+func (rc *ReconcileContext) reconcile() (reconcile.Result, error) {
 	// Get the Jenkins Instance
-	r.ControlledRescources.JenkinsInstance = &jenkinsv1alpha1.Jenkins{}
-	err := r.Client.Get(context.TODO(), r.Request.NamespacedName, r.ControlledRescources.JenkinsInstance)
+	rc.ControlledResources.JenkinsInstance = &jenkinsv1alpha1.Jenkins{}
+	err := rc.Client.Get(context.TODO(), rc.Request.NamespacedName, rc.ControlledResources.JenkinsInstance)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -93,92 +163,222 @@ func (r *JenkinsReconciler) Reconcile(request reconcile.Request) (reconcile.Resu
 			// Return and don't requeue
 			return reconcile.Result{}, nil
 		}
-		// Error reading the object - requeue the request.
+		// Error reading the object - requeue the request with the rate limiter of the controller
+		rc.Diagnostics.Error(err, "Cannot get the Jenkins instance")
+		return reconcile.Result{}, err
+	}
+	// Apply the deletion policy of an instance being deleted
+	if rc.ControlledResources.JenkinsInstance.GetDeletionTimestamp() != nil {
+		return rc.finalize()
+	}
+	if err := rc.ensureFinalizer(); err != nil {
+		rc.Diagnostics.Error(err, "Cannot add the finalizer")
+		return reconcile.Result{}, err
+	}
+	// Do not touch the managed resources while the spec cannot be applied
+	if errs := ValidateJenkins(rc.ControlledResources.JenkinsInstance); len(errs) > 0 {
+		rc.Diagnostics.Warning("Invalid spec", "errors", errs.ToAggregate().Error())
+		rc.Recorder.Event(rc.ControlledResources.JenkinsInstance, corev1.EventTypeWarning, ReasonInvalidSpec, errs.ToAggregate().Error())
+		if err := rc.updateInvalidSpecStatus(errs.ToAggregate()); err != nil {
+			rc.Diagnostics.Error(err, "Cannot update the status")
+			return reconcile.Result{}, err
+		}
 		return reconcile.Result{}, nil
 	}
+	image, imageErr := rc.resolveJenkinsImage()
+	if imageErr != nil {
+		rc.Diagnostics.Error(imageErr, "Cannot resolve the Jenkins image")
+	}
+	// Generate the admin credentials of the builtin authentication before they are referenced by the workload
+	credentialsErr := rc.reconcileAdminCredentials()
+	if credentialsErr != nil {
+		rc.Diagnostics.Error(credentialsErr, "Cannot reconcile the admin credentials")
+	}
 	// Create Resources
-	r.createAllResources()
+	rc.createAllResources(image)
 
 	// Resources on Watch
 	resourcesToWatch := []j.NamedResource{
-		j.NamedResource{r.ControlledRescources.ServiceAccount, r.ControlledRescources.ServiceAccount.GetName()},
-		j.NamedResource{r.ControlledRescources.RoleBinding, r.ControlledRescources.RoleBinding.GetName()},
-		j.NamedResource{r.ControlledRescources.JenkinsService, r.ControlledRescources.JenkinsService.GetName()},
-		j.NamedResource{r.ControlledRescources.JNLPService, r.ControlledRescources.JNLPService.GetName()},
-		j.NamedResource{r.ControlledRescources.Route, r.ControlledRescources.Route.GetName()},
+		{Object: rc.ControlledResources.ServiceAccount, Name: rc.ControlledResources.ServiceAccount.GetName()},
+		{Object: rc.ControlledResources.RoleBinding, Name: rc.ControlledResources.RoleBinding.GetName()},
+		{Object: rc.ControlledResources.JenkinsService, Name: rc.ControlledResources.JenkinsService.GetName()},
+		{Object: rc.ControlledResources.JNLPService, Name: rc.ControlledResources.JNLPService.GetName()},
+	}
+	// The Route is only defined when the Route API is available
+	if rc.ControlledResources.Route != nil {
+		resourcesToWatch = append(resourcesToWatch,
+			j.NamedResource{Object: rc.ControlledResources.Route, Name: rc.ControlledResources.Route.GetName()},
+		)
+	}
+	if rc.ControlledResources.Ingress != nil {
+		resourcesToWatch = append(resourcesToWatch,
+			j.NamedResource{Object: rc.ControlledResources.Ingress, Name: rc.ControlledResources.Ingress.GetName()},
+		)
+	}
+	if rc.ControlledResources.CascReloadSecret != nil {
+		resourcesToWatch = append(resourcesToWatch,
+			j.NamedResource{Object: rc.ControlledResources.CascReloadSecret, Name: rc.ControlledResources.CascReloadSecret.GetName()},
+		)
+	}
+
+	if rc.ControlledResources.BuiltinAuthConfigMap != nil {
+		resourcesToWatch = append(resourcesToWatch,
+			j.NamedResource{Object: rc.ControlledResources.BuiltinAuthConfigMap, Name: rc.ControlledResources.BuiltinAuthConfigMap.GetName()},
+		)
 	}
 
-	if r.ControlledRescources.JenkinsInstance.Spec.UseDeploymentConfig {
+	// The new workload is only created once the previous kind of workload is gone
+	migrating, migrationErr := rc.migrateWorkload()
+	if migrationErr != nil {
+		rc.Diagnostics.Error(migrationErr, "Cannot migrate the workload")
+	} else if migrating {
+		rc.Diagnostics.RequeueAfter(MigrationRequeueDelay)
+	}
+
+	if rc.ControlledResources.AdminSecret == nil && isBuiltinAuthentication(rc.ControlledResources.JenkinsInstance) {
+		rc.Diagnostics.Info("Admin credentials are not available, skipping workload update")
+	} else if migrating {
+		rc.Diagnostics.Info("Workload migration in progress, skipping workload creation")
+	} else if imageErr != nil {
+		rc.Diagnostics.Info("Jenkins image cannot be resolved, skipping workload update")
+	} else if rc.useDeploymentConfig() {
 		resourcesToWatch = append(resourcesToWatch,
-			j.NamedResource{r.ControlledRescources.DeploymentConfig, r.ControlledRescources.DeploymentConfig.GetName()},
+			j.NamedResource{Object: rc.ControlledResources.DeploymentConfig, Name: rc.ControlledResources.DeploymentConfig.GetName()},
 		)
 	} else {
 		resourcesToWatch = append(resourcesToWatch,
-			j.NamedResource{r.ControlledRescources.Deployment, r.ControlledRescources.Deployment.GetName()},
+			j.NamedResource{Object: rc.ControlledResources.Deployment, Name: rc.ControlledResources.Deployment.GetName()},
 		)
 	}
 
-	if r.isPersistent() {
-		r.ControlledRescources.PersistentVolumeClaim = newJenkinsPvc(r.ControlledRescources.JenkinsInstance, JenkinsInstanceName)
-		resourcesToWatch = append(resourcesToWatch, j.NamedResource{r.ControlledRescources.PersistentVolumeClaim, r.ControlledRescources.PersistentVolumeClaim.GetName()})
+	if rc.isPersistent() && usesExistingClaim(rc.ControlledResources.JenkinsInstance) {
+		// The existing claim is only read to report its state
+		pvc, err := rc.getExistingClaim()
+		if err != nil {
+			rc.Diagnostics.Error(err, "Cannot get the existing claim")
+		}
+		rc.ControlledResources.PersistentVolumeClaim = pvc
+	} else if rc.isPersistent() {
+		rc.ControlledResources.PersistentVolumeClaim = newJenkinsPvc(rc.ControlledResources.JenkinsInstance, rc.ControlledResources.JenkinsInstance.Name)
+		message, err := rc.limitClaimExpansion(rc.ControlledResources.PersistentVolumeClaim)
+		if err != nil {
+			rc.Diagnostics.Error(err, "Cannot check the expansion of the claim")
+		}
+		rc.ClaimExpansionMessage = message
+		resourcesToWatch = append(resourcesToWatch, j.NamedResource{Object: rc.ControlledResources.PersistentVolumeClaim, Name: rc.ControlledResources.PersistentVolumeClaim.GetName()})
+	}
+	// The backups run the Jenkins image, the CronJob is left as is while it cannot be resolved
+	if rc.isPersistent() && rc.ControlledResources.JenkinsInstance.Spec.Backup != nil && imageErr == nil {
+		rc.ControlledResources.BackupCronJob = newJenkinsBackupCronJob(rc.ControlledResources.JenkinsInstance, image)
+		resourcesToWatch = append(resourcesToWatch, j.NamedResource{Object: rc.ControlledResources.BackupCronJob, Name: rc.ControlledResources.BackupCronJob.GetName()})
 	}
 
 	// Set reference and watch resources
-	r.setControllerReferenceOnWatch(resourcesToWatch)
-	r.updateResourcesOnWatch(resourcesToWatch)
+	rc.setControllerReferenceOnWatch(resourcesToWatch)
+	rc.updateResourcesOnWatch(resourcesToWatch)
+
+	// Remove the Ingress which is not requested anymore
+	if err := rc.deleteIngressIfUnused(); err != nil {
+		rc.Diagnostics.Error(err, "Cannot delete the unused Ingress")
+	}
+
+	// Bind the role of the ServiceAccount in the additional namespaces
+	boundNamespaces, rbacErr := rc.reconcileRoleBindings()
+	rc.BoundNamespaces = boundNamespaces
+	if rbacErr != nil {
+		rc.Diagnostics.Error(rbacErr, "Cannot bind the role in the additional namespaces")
+	}
+
+	// Reload the Configuration as Code when it changed
+	rc.Diagnostics.RequeueAfter(rc.reconcileConfigurationAsCode())
 
-	return r.Result, err
+	// Report the runs of the scheduled backups, or remove them when they are not scheduled anymore
+	if err := rc.reconcileScheduledBackups(); err != nil {
+		rc.Diagnostics.Error(err, "Cannot reconcile the scheduled backups")
+	}
+
+	// Run the backups to the object storage requested through the annotation
+	if err := rc.reconcileObjectStorage(); err != nil {
+		rc.Diagnostics.Error(err, "Cannot reconcile the backups to the object storage")
+	}
+
+	// Report the observed state of the managed resources
+	if err := rc.updateStatus(); err != nil {
+		rc.Diagnostics.Error(err, "Cannot update the status")
+	}
+
+	// Check the health of the instance periodically, the errors are retried with the rate limiter instead
+	rc.Diagnostics.RequeueAfter(HealthCheckInterval)
+	return rc.Diagnostics.Result(), rc.Diagnostics.Err()
 }
 
-func (r *JenkinsReconciler) setControllerReferenceOnWatch(resourcesToWatch []j.NamedResource) {
+func (rc *ReconcileContext) setControllerReferenceOnWatch(resourcesToWatch []j.NamedResource) {
 	// Set Controller reference as Jenkins Instance
 	for _, namedRes := range resourcesToWatch {
-		resource := r.parseResourceToStatic(namedRes, r.ControlledRescources.JenkinsInstance.GetNamespace())
-		if err := controllerutil.SetControllerReference(r.ControlledRescources.JenkinsInstance, resource.Object, r.Scheme); err != nil {
-			r.Messages.LogError(err, "setControllerReferenceOnWatch", logReconciler)
-		} else {
-			r.Result = r.Messages.HandleComplete()
+		resource := rc.parseResourceToStatic(namedRes, rc.ControlledResources.JenkinsInstance.GetNamespace())
+		if err := controllerutil.SetControllerReference(rc.ControlledResources.JenkinsInstance, resource.Object, rc.Scheme); err != nil {
+			rc.Diagnostics.ResourceError(err, "Cannot set the controller reference", resource.Object.(runtime.Object))
 		}
 	}
 }
 
-func (r *JenkinsReconciler) updateResourcesOnWatch(resourcesToWatch []j.NamedResource) {
-	// Watch resources and create if they aren't present
+func (rc *ReconcileContext) updateResourcesOnWatch(resourcesToWatch []j.NamedResource) {
+	// Watch resources, create them if they aren't present and update them if they drifted
 	for _, namedRes := range resourcesToWatch {
-		resource := r.parseResourceToRuntime(namedRes, r.ControlledRescources.JenkinsInstance.GetNamespace())
-		if err := r.createResourceIfNotPresent(resource); err != nil {
-			r.Messages.LogError(err, "updateResourcesOnWatch", logReconciler)
-		} else {
-			r.Result = r.Messages.HandleComplete()
+		resource := rc.parseResourceToRuntime(namedRes, rc.ControlledResources.JenkinsInstance.GetNamespace())
+		if err := rc.createOrUpdateResource(resource); err != nil {
+			rc.Diagnostics.ResourceError(err, "Cannot reconcile the resource", resource.Object)
 		}
 	}
 }
 
-func (r *JenkinsReconciler) createAllResources() {
-	verifyOpenshiftAPIs()
-
+func (rc *ReconcileContext) createAllResources(image string) {
 	// Define Deployment Config
-	if deploymentConfigAPIFound && r.ControlledRescources.JenkinsInstance.Spec.UseDeploymentConfig {
-		r.ControlledRescources.DeploymentConfig = newJenkinsDeploymentConfig(r.ControlledRescources.JenkinsInstance, JenkinsInstanceName, JenkinsInstanceName+JenkinsJnlpServiceSuffix, r.ControlledRescources.JenkinsInstance.Spec.Persistence.Enabled)
+	if rc.useDeploymentConfig() {
+		rc.ControlledResources.DeploymentConfig = newJenkinsDeploymentConfig(rc.ControlledResources.JenkinsInstance, image, rc.ControlledResources.JenkinsInstance.Name, rc.ControlledResources.JenkinsInstance.Name+JenkinsJnlpServiceSuffix, rc.ControlledResources.JenkinsInstance.Spec.Persistence.Enabled)
 	} else {
 		//Define Deployment
-		r.ControlledRescources.Deployment = newJenkinsDeployment(r.ControlledRescources.JenkinsInstance, JenkinsInstanceName, JenkinsInstanceName+JenkinsJnlpServiceSuffix, r.ControlledRescources.JenkinsInstance.Spec.Persistence.Enabled)
+		rc.ControlledResources.Deployment = newJenkinsDeployment(rc.ControlledResources.JenkinsInstance, image, rc.ControlledResources.JenkinsInstance.Name, rc.ControlledResources.JenkinsInstance.Name+JenkinsJnlpServiceSuffix, rc.ControlledResources.JenkinsInstance.Spec.Persistence.Enabled)
 
 	}
 	// Define Jenkins Services
-	r.ControlledRescources.JenkinsService = r.getJenkinsService()
-	r.ControlledRescources.JNLPService = r.getJenkinsJNLPService()
-
-	// Define Route
-	if routeAPIFound {
-		r.ControlledRescources.Route = newJenkinsRoute(r.ControlledRescources.JenkinsInstance, r.ControlledRescources.JenkinsService)
+	rc.ControlledResources.JenkinsService = rc.getJenkinsService()
+	rc.ControlledResources.JNLPService = rc.getJenkinsJNLPService()
+
+	// Define Route, it is left as it is while its certificates cannot be read
+	if rc.APIs.Route {
+		certificates, err := rc.routeCertificates()
+		if err != nil {
+			rc.Diagnostics.Error(err, "Cannot read the certificates of the Route")
+		} else {
+			rc.ControlledResources.Route = newJenkinsRoute(rc.ControlledResources.JenkinsInstance, rc.ControlledResources.JenkinsService, certificates)
+		}
+	}
+	// Define Ingress, the only way to expose the instance without the Route API
+	if rc.useIngress() {
+		rc.ControlledResources.Ingress = newJenkinsIngress(rc.ControlledResources.JenkinsInstance, rc.ControlledResources.JenkinsService)
 	}
 	// Create RBAC and manage
-	r.ControlledRescources.ServiceAccount = newJenkinsServiceAccount(r.ControlledRescources.JenkinsInstance, JenkinsInstanceName)
-	r.ControlledRescources.RoleBinding = newJenkinsRoleBinding(r.ControlledRescources.JenkinsInstance, JenkinsInstanceName)
+	rc.ControlledResources.ServiceAccount = newJenkinsServiceAccount(rc.ControlledResources.JenkinsInstance, rc.ControlledResources.JenkinsInstance.Name)
+	rc.ControlledResources.RoleBinding = newJenkinsRoleBinding(rc.ControlledResources.JenkinsInstance, rc.ControlledResources.JenkinsInstance.Name)
+
+	// Define the Secret holding the Configuration as Code reload token
+	if rc.ControlledResources.JenkinsInstance.Spec.ConfigurationAsCode != nil {
+		rc.ControlledResources.CascReloadSecret = newJenkinsCascReloadSecret(rc.ControlledResources.JenkinsInstance)
+	}
+	// Define the script configuring the admin user, and roll Jenkins out again when its credentials are rotated
+	if isBuiltinAuthentication(rc.ControlledResources.JenkinsInstance) {
+		rc.ControlledResources.BuiltinAuthConfigMap = newBuiltinAuthConfigMap(rc.ControlledResources.JenkinsInstance)
+		if rc.ControlledResources.DeploymentConfig != nil {
+			rc.setAdminCredentialsHash(rc.ControlledResources.DeploymentConfig.Spec.Template)
+		}
+		if rc.ControlledResources.Deployment != nil {
+			rc.setAdminCredentialsHash(&rc.ControlledResources.Deployment.Spec.Template)
+		}
+	}
 }
 
-func (r *JenkinsReconciler) getJenkinsService() *corev1.Service {
+func (rc *ReconcileContext) getJenkinsService() *corev1.Service {
 	jenkinsPort := corev1.ServicePort{
 		Name:     JenkinsWebPortName,
 		Port:     JenkinsWebPort,
@@ -188,10 +388,10 @@ func (r *JenkinsReconciler) getJenkinsService() *corev1.Service {
 			StrVal: JenkinsWebPortAsStr,
 		},
 	}
-	return newJenkinsService(r.ControlledRescources.JenkinsInstance, JenkinsInstanceName, jenkinsPort)
+	return newJenkinsService(rc.ControlledResources.JenkinsInstance, rc.ControlledResources.JenkinsInstance.Name, jenkinsPort)
 }
 
-func (r *JenkinsReconciler) getJenkinsJNLPService() *corev1.Service {
+func (rc *ReconcileContext) getJenkinsJNLPService() *corev1.Service {
 	jenkinsJNLPPort := corev1.ServicePort{
 		Name:     JenkinsAgentPortName,
 		Port:     JenkinsAgentPort,
@@ -201,59 +401,109 @@ func (r *JenkinsReconciler) getJenkinsJNLPService() *corev1.Service {
 			StrVal: JenkinsAgentPortAsStr,
 		},
 	}
-	return newJenkinsService(r.ControlledRescources.JenkinsInstance, JenkinsInstanceName+JenkinsJnlpServiceSuffix, jenkinsJNLPPort)
+	return newJenkinsService(rc.ControlledResources.JenkinsInstance, rc.ControlledResources.JenkinsInstance.Name+JenkinsJnlpServiceSuffix, jenkinsJNLPPort)
 }
 
-func (r *JenkinsReconciler) createResourceIfNotPresent(resource j.RuntimeResource) error {
-	namespaceNameLog := "| Namespace " + resource.NamespacedName.Namespace + " | Name " + resource.NamespacedName.Name
-	message := "createResourceIfNotPresent: " + namespaceNameLog
-	noRequeueMessage := message + " REQUEUE DISABLED "
-
-	r.Messages.LogInfo(message, logReconciler)
-	err := r.checkResourceIfExists(resource)
+// createOrUpdateResource creates the resource when it is missing, otherwise it updates the fields owned by
+// the operator when they drifted from the desired state. On success resource.Object holds the live object.
+func (rc *ReconcileContext) createOrUpdateResource(resource j.RuntimeResource) error {
+	live := newEmptyObject(resource.Object)
+	err := rc.checkResourceIfExists(resource, live)
 	if err != nil && kubeerrors.IsNotFound(err) {
-		r.Messages.LogError(err, message, logReconciler)
-		r.createResource(resource)
-		// Resource is Successfully Created | DO NOT REQUEUE
-		r.Messages.LogInfo(noRequeueMessage, logReconciler)
-		r.Result = reconcile.Result{Requeue: false}
+		return rc.createResource(resource)
+	} else if err != nil {
 		return err
 	}
-	return err
+	return rc.updateResource(resource, live)
 }
 
-func (r *JenkinsReconciler) checkResourceIfExists(resource j.RuntimeResource) error {
-	namespaceNameLog := "| Namespace " + resource.NamespacedName.Namespace + " | Name " + resource.NamespacedName.Name
-	message := "checkResourceIfExists: " + namespaceNameLog
+func (rc *ReconcileContext) checkResourceIfExists(resource j.RuntimeResource, into runtime.Object) error {
+	return rc.Client.Get(context.TODO(), resource.NamespacedName, into)
+}
 
-	r.Messages.LogInfo(message, logReconciler)
-	err := r.Client.Get(context.TODO(), resource.NamespacedName, resource.Object)
-	if err != nil && kubeerrors.IsAlreadyExists(err) {
-		r.Messages.LogError(err, message, logReconciler)
+// updateResource merges the desired state into the live object and updates it if anything changed
+func (rc *ReconcileContext) updateResource(resource j.RuntimeResource, live runtime.Object) error {
+	merged := live.DeepCopyObject()
+	err := mergeManagedFields(resource.Object, merged)
+	if err == errRecreateRequired {
+		rc.Diagnostics.Info("Recreating the resource", "resource", common.ResourceName(live), "reason", err.Error())
+		if err := rc.Client.Delete(context.TODO(), live); err != nil && !kubeerrors.IsNotFound(err) {
+			rc.recordResourceEvent(live, j.ActionDelete, err)
+			return err
+		}
+		rc.recordResourceEvent(live, j.ActionDelete, nil)
+		return rc.createResource(resource)
+	} else if err != nil {
 		return err
 	}
-	return err
+
+	if !reflect.DeepEqual(merged, live) {
+		rc.Diagnostics.Info("Updating the resource which drifted", "resource", common.ResourceName(merged))
+		err := rc.Client.Update(context.TODO(), merged)
+		rc.recordResourceEvent(merged, j.ActionUpdate, err)
+		if err != nil {
+			return err
+		}
+	}
+	// Expose the live state to the rest of the reconcile loop
+	reflect.ValueOf(resource.Object).Elem().Set(reflect.ValueOf(merged).Elem())
+	return nil
 }
 
-func (r *JenkinsReconciler) isPersistent() bool {
-	return r.ControlledRescources.JenkinsInstance.Spec.Persistence.Enabled
+func (rc *ReconcileContext) isPersistent() bool {
+	return rc.ControlledResources.JenkinsInstance.Spec.Persistence.Enabled
 }
 
-func (r *JenkinsReconciler) createResource(resource j.RuntimeResource) {
-	namespaceNameLog := "| Namespace " + resource.NamespacedName.Namespace + " | Name " + resource.NamespacedName.Name
-	message := "createResource: " + namespaceNameLog
-	requeueMessage := message + " REQUEUE ENABLED "
+// useIngress returns true when the Route API is missing or an Ingress is requested
+func (rc *ReconcileContext) useIngress() bool {
+	ingress := rc.ControlledResources.JenkinsInstance.Spec.Ingress
+	return !rc.APIs.Route || (ingress != nil && ingress.Enabled)
+}
 
-	r.Messages.LogInfo(message, logReconciler)
-	err := r.Client.Create(context.TODO(), resource.Object)
-	if err != nil {
-		r.Messages.LogError(err, message, logReconciler)
-		r.Messages.LogInfo(requeueMessage, logReconciler)
-		r.Result = reconcile.Result{Requeue: true}
+// deleteIngressIfUnused deletes the Ingress of an instance which is not exposed through an Ingress anymore
+func (rc *ReconcileContext) deleteIngressIfUnused() error {
+	if rc.useIngress() {
+		return nil
+	}
+	instance := rc.ControlledResources.JenkinsInstance
+	ingress := &extensionsv1beta1.Ingress{}
+	err := rc.Client.Get(context.TODO(), types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}, ingress)
+	if kubeerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
 	}
+	if owner := metav1.GetControllerOf(ingress); owner == nil || owner.UID != instance.UID {
+		// The Ingress was not created by the operator
+		return nil
+	}
+	rc.Diagnostics.Info("Deleting the unused Ingress", "resource", common.ResourceName(ingress))
+	if err := rc.Client.Delete(context.TODO(), ingress); err != nil && !kubeerrors.IsNotFound(err) {
+		rc.recordResourceEvent(ingress, j.ActionDelete, err)
+		return err
+	}
+	rc.recordResourceEvent(ingress, j.ActionDelete, nil)
+	return nil
+}
+
+// useDeploymentConfig returns true when a DeploymentConfig is requested and the API is available
+func (rc *ReconcileContext) useDeploymentConfig() bool {
+	return rc.APIs.DeploymentConfig && rc.ControlledResources.JenkinsInstance.Spec.UseDeploymentConfig
+}
+
+func (rc *ReconcileContext) createResource(resource j.RuntimeResource) error {
+	rc.Diagnostics.Info("Creating the resource", "resource", common.ResourceName(resource.Object))
+	err := rc.Client.Create(context.TODO(), resource.Object)
+	rc.recordResourceEvent(resource.Object, j.ActionCreate, err)
+	return err
+}
+
+// recordResourceEvent records on the instance the outcome of an action on one of its resources
+func (rc *ReconcileContext) recordResourceEvent(obj runtime.Object, action j.ResourceAction, err error) {
+	j.RecordResourceEvent(rc.Recorder, rc.ControlledResources.JenkinsInstance, obj, action, err)
 }
 
-func (r *JenkinsReconciler) parseResourceToStatic(namedRes j.NamedResource, namespace string) j.StaticResource {
+func (rc *ReconcileContext) parseResourceToStatic(namedRes j.NamedResource, namespace string) j.StaticResource {
 	resource := namedRes.Object.(metav1.Object)
 	return j.StaticResource{
 		Object: resource,
@@ -264,7 +514,7 @@ func (r *JenkinsReconciler) parseResourceToStatic(namedRes j.NamedResource, name
 	}
 }
 
-func (r *JenkinsReconciler) parseResourceToRuntime(namedRes j.NamedResource, namespace string) j.RuntimeResource {
+func (rc *ReconcileContext) parseResourceToRuntime(namedRes j.NamedResource, namespace string) j.RuntimeResource {
 	resource := namedRes.Object.(runtime.Object)
 	return j.RuntimeResource{
 		Object: resource,
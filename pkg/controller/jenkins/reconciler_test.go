@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"testing"
 
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
 	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -60,7 +62,7 @@ func TestNewJenkinsPvc(t *testing.T) {
 
 func TestNewJenkinsDeploymentConfig(t *testing.T) {
 	t.Run("TestNewJenkinsDc", func(t *testing.T) {
-		dc := newJenkinsDeploymentConfig(mocks.JenkinsCRMock(test_ns, test_name), JenkinsServiceName, JenkinsJNLPServiceName, true)
+		dc := newJenkinsDeploymentConfig(mocks.JenkinsCRMock(test_ns, test_name), JenkinsImage, JenkinsServiceName, JenkinsJNLPServiceName, true)
 
 		mockDc := mocks.JenkinsDCMock(test_ns, test_name)
 		// Testing the things that are bound to match.
@@ -74,3 +76,44 @@ func TestNewJenkinsDeploymentConfig(t *testing.T) {
 	})
 }
 
+func TestNewPodTemplateSpec(t *testing.T) {
+	t.Run("TestDefaults", func(t *testing.T) {
+		podTemplate := newPodTemplateSpec(mocks.JenkinsCRMock(test_ns, test_name), JenkinsImage, test_name, test_name+JenkinsJnlpServiceSuffix, false)
+		container := podTemplate.Spec.Containers[0]
+		require.Equal(t, JenkinsImage, container.Image)
+		require.Equal(t, resource.MustParse(JenkinsContainerMemory), container.Resources.Limits[corev1.ResourceMemory])
+		require.Equal(t, int32(420), container.LivenessProbe.InitialDelaySeconds)
+		require.Len(t, podTemplate.Spec.Volumes, 1)
+	})
+
+	t.Run("TestOverrides", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Resources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+		}
+		cr.Spec.LivenessProbe = &jenkinsv1alpha1.JenkinsProbe{InitialDelaySeconds: int32Ptr(900)}
+		cr.Spec.ReadinessProbe = &jenkinsv1alpha1.JenkinsProbe{PeriodSeconds: int32Ptr(30), FailureThreshold: int32Ptr(5)}
+		cr.Spec.JavaOpts = "-Xmx3g"
+		cr.Spec.Env = []corev1.EnvVar{{Name: "OPENSHIFT_ENABLE_OAUTH", Value: "false"}, {Name: "EXTRA", Value: "1"}}
+		cr.Spec.Volumes = []corev1.Volume{{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+		cr.Spec.VolumeMounts = []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}}
+
+		podTemplate := newPodTemplateSpec(cr, "quay.io/example/jenkins:2", test_name, test_name+JenkinsJnlpServiceSuffix, false)
+		container := podTemplate.Spec.Containers[0]
+		require.Equal(t, "quay.io/example/jenkins:2", container.Image)
+		require.Equal(t, *cr.Spec.Resources, container.Resources)
+		require.Equal(t, int32(900), container.LivenessProbe.InitialDelaySeconds)
+		require.Equal(t, int32(240), container.LivenessProbe.TimeoutSeconds)
+		require.Equal(t, int32(30), container.ReadinessProbe.PeriodSeconds)
+		require.Equal(t, int32(5), container.ReadinessProbe.FailureThreshold)
+		require.Contains(t, container.Env, corev1.EnvVar{Name: JenkinsJavaOptsEnv, Value: "-Xmx3g"})
+		require.Contains(t, container.Env, corev1.EnvVar{Name: "OPENSHIFT_ENABLE_OAUTH", Value: "false"})
+		require.NotContains(t, container.Env, corev1.EnvVar{Name: "OPENSHIFT_ENABLE_OAUTH", Value: "true"})
+		require.Contains(t, container.Env, corev1.EnvVar{Name: "EXTRA", Value: "1"})
+		require.Len(t, podTemplate.Spec.Volumes, 2)
+		require.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "cache", MountPath: "/cache"})
+		require.Equal(t, "OPENSHIFT_ENABLE_OAUTH,EXTRA,JAVA_OPTS", podTemplate.Annotations[JenkinsExtraEnvAnnotation])
+		require.Equal(t, "cache", podTemplate.Annotations[JenkinsExtraVolumesAnnotation])
+	})
+}
+
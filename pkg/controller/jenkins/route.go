@@ -0,0 +1,62 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// RouteCACertificateKey holds the CA chain of the certificate of the Route in the certificate Secret
+	RouteCACertificateKey = "ca.crt"
+	// RouteDestinationCACertificateKey holds the CA of the certificate served by Jenkins, used with reencrypt
+	RouteDestinationCACertificateKey = "destination-ca.crt"
+)
+
+// routeCertificates returns the Secret holding the certificates of the Route, or nil when the certificate of
+// the router is used
+func (rc *ReconcileContext) routeCertificates() (*corev1.Secret, error) {
+	instance := rc.ControlledResources.JenkinsInstance
+	if instance.Spec.Route == nil || len(instance.Spec.Route.CertificateSecret) == 0 {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.Route.CertificateSecret}
+	if err := rc.Client.Get(context.TODO(), key, secret); err != nil {
+		return nil, fmt.Errorf("cannot read the certificates of the Route: %v", err)
+	}
+	for _, required := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		if len(secret.Data[required]) == 0 {
+			return nil, fmt.Errorf("Secret %s holding the certificates of the Route has no %s key", secret.Name, required)
+		}
+	}
+	return secret, nil
+}
+
+// routeCertificateMapper enqueues the Jenkins instances of the namespace whose Route uses a Secret
+type routeCertificateMapper struct {
+	client client.Client
+}
+
+var _ handler.Mapper = &routeCertificateMapper{}
+
+func (m *routeCertificateMapper) Map(obj handler.MapObject) []reconcile.Request {
+	instances := &jenkinsv1alpha1.JenkinsList{}
+	if err := m.client.List(context.TODO(), client.InNamespace(obj.Meta.GetNamespace()), instances); err != nil {
+		logController.Error(err, "Cannot list the Jenkins instances referencing Route certificates")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, instance := range instances.Items {
+		if instance.Spec.Route != nil && instance.Spec.Route.CertificateSecret == obj.Meta.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,295 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	kappsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+)
+
+const (
+	// Reasons used in the Jenkins status conditions
+	ReasonMinimumReplicasAvailable   = "MinimumReplicasAvailable"
+	ReasonMinimumReplicasUnavailable = "MinimumReplicasUnavailable"
+	ReasonRolloutInProgress          = "RolloutInProgress"
+	ReasonRolloutComplete            = "RolloutComplete"
+	ReasonReconcileFailed            = "ReconcileFailed"
+	ReasonReconcileSucceeded         = "ReconcileSucceeded"
+	ReasonClaimBound                 = "ClaimBound"
+	ReasonClaimPending               = "ClaimPending"
+	ReasonEphemeral                  = "Ephemeral"
+	ReasonRouteAdmitted              = "RouteAdmitted"
+	ReasonRouteNotAdmitted           = "RouteNotAdmitted"
+	ReasonIngressAdmitted            = "IngressAdmitted"
+	ReasonIngressNotAdmitted         = "IngressNotAdmitted"
+	ReasonInvalidSpec                = "InvalidSpec"
+	ReasonValidSpec                  = "ValidSpec"
+)
+
+// updateStatus computes the status of the Jenkins instance from the managed resources
+// and writes it through the status subresource when it changed.
+func (rc *ReconcileContext) updateStatus() error {
+	instance := rc.ControlledResources.JenkinsInstance
+	status := instance.Status.DeepCopy()
+
+	status.ObservedGeneration = instance.Generation
+	status.Resources = rc.managedResourcesStatus()
+	status.Image = rc.workloadImage()
+	status.ConfigurationAsCode = rc.ConfigurationAsCodeStatus
+	status.Backup = rc.BackupStatus
+	status.ObjectStorage = rc.ObjectStorageStatus
+	status.Persistence = rc.persistenceStatus()
+	status.SetCondition(jenkinsv1alpha1.JenkinsValid, corev1.ConditionTrue, ReasonValidSpec, "")
+	rc.setWorkloadConditions(status)
+	if condition := rc.MigratingCondition; condition != nil {
+		status.SetCondition(condition.Type, condition.Status, condition.Reason, condition.Message)
+	}
+	rc.setDegradedCondition(status)
+	rc.setPersistenceCondition(status)
+	status.URL = ""
+	rc.setRouteCondition(status)
+	rc.setIngressCondition(status)
+	status.Phase = phaseFromConditions(status)
+
+	if reflect.DeepEqual(instance.Status, *status) {
+		return nil
+	}
+	instance.Status = *status
+	return rc.Client.Status().Update(context.TODO(), instance)
+}
+
+// updateInvalidSpecStatus reports that the spec of the instance cannot be applied, leaving the rest of the status as is
+func (rc *ReconcileContext) updateInvalidSpecStatus(specErr error) error {
+	instance := rc.ControlledResources.JenkinsInstance
+	status := instance.Status.DeepCopy()
+	status.ObservedGeneration = instance.Generation
+	status.SetCondition(jenkinsv1alpha1.JenkinsValid, corev1.ConditionFalse, ReasonInvalidSpec, specErr.Error())
+	status.SetCondition(jenkinsv1alpha1.JenkinsDegraded, corev1.ConditionTrue, ReasonInvalidSpec, specErr.Error())
+	status.Phase = phaseFromConditions(status)
+
+	if reflect.DeepEqual(instance.Status, *status) {
+		return nil
+	}
+	instance.Status = *status
+	return rc.Client.Status().Update(context.TODO(), instance)
+}
+
+func (rc *ReconcileContext) managedResourcesStatus() jenkinsv1alpha1.JenkinsManagedResources {
+	resources := jenkinsv1alpha1.JenkinsManagedResources{}
+	controlled := rc.ControlledResources
+	if rc.useDeploymentConfig() && controlled.DeploymentConfig != nil {
+		resources.DeploymentConfig = controlled.DeploymentConfig.GetName()
+	} else if controlled.Deployment != nil {
+		resources.Deployment = controlled.Deployment.GetName()
+	}
+	for _, service := range []*corev1.Service{controlled.JenkinsService, controlled.JNLPService} {
+		if service != nil {
+			resources.Services = append(resources.Services, service.GetName())
+		}
+	}
+	if rc.isPersistent() && controlled.PersistentVolumeClaim != nil {
+		resources.PersistentVolumeClaim = controlled.PersistentVolumeClaim.GetName()
+	}
+	if controlled.ServiceAccount != nil {
+		resources.ServiceAccount = controlled.ServiceAccount.GetName()
+	}
+	if controlled.RoleBinding != nil {
+		resources.RoleBinding = controlled.RoleBinding.GetName()
+	}
+	if controlled.Route != nil {
+		resources.Route = controlled.Route.GetName()
+	}
+	if controlled.Ingress != nil {
+		resources.Ingress = controlled.Ingress.GetName()
+	}
+	if controlled.AdminSecret != nil {
+		resources.AdminSecret = controlled.AdminSecret.GetName()
+	}
+	if len(rc.BoundNamespaces) > 0 {
+		resources.BoundNamespaces = rc.BoundNamespaces
+	}
+	return resources
+}
+
+// workloadImage returns the image of the Jenkins container of the workload
+func (rc *ReconcileContext) workloadImage() string {
+	var podTemplate *corev1.PodTemplateSpec
+	if rc.useDeploymentConfig() && rc.ControlledResources.DeploymentConfig != nil {
+		podTemplate = rc.ControlledResources.DeploymentConfig.Spec.Template
+	} else if rc.ControlledResources.Deployment != nil {
+		podTemplate = &rc.ControlledResources.Deployment.Spec.Template
+	}
+	if podTemplate == nil {
+		return ""
+	}
+	for _, container := range podTemplate.Spec.Containers {
+		if container.Name == JenkinsContainerName {
+			return container.Image
+		}
+	}
+	return ""
+}
+
+func (rc *ReconcileContext) setWorkloadConditions(status *jenkinsv1alpha1.JenkinsStatus) {
+	var desired, updated, available int32
+	if rc.useDeploymentConfig() && rc.ControlledResources.DeploymentConfig != nil {
+		desired, updated, available = deploymentConfigReplicas(rc.ControlledResources.DeploymentConfig)
+	} else if rc.ControlledResources.Deployment != nil {
+		desired, updated, available = deploymentReplicas(rc.ControlledResources.Deployment)
+	}
+
+	if available > 0 {
+		status.SetCondition(jenkinsv1alpha1.JenkinsAvailable, corev1.ConditionTrue, ReasonMinimumReplicasAvailable,
+			fmt.Sprintf("%d replica(s) available", available))
+	} else {
+		status.SetCondition(jenkinsv1alpha1.JenkinsAvailable, corev1.ConditionFalse, ReasonMinimumReplicasUnavailable,
+			"Jenkins has no available replica")
+	}
+	if updated < desired || available < desired {
+		status.SetCondition(jenkinsv1alpha1.JenkinsProgressing, corev1.ConditionTrue, ReasonRolloutInProgress,
+			fmt.Sprintf("%d of %d replica(s) updated and %d available", updated, desired, available))
+	} else {
+		status.SetCondition(jenkinsv1alpha1.JenkinsProgressing, corev1.ConditionFalse, ReasonRolloutComplete,
+			"Rollout is complete")
+	}
+}
+
+func deploymentReplicas(deployment *kappsv1.Deployment) (desired, updated, available int32) {
+	desired = 1
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	return desired, deployment.Status.UpdatedReplicas, deployment.Status.AvailableReplicas
+}
+
+func deploymentConfigReplicas(dc *appsv1.DeploymentConfig) (desired, updated, available int32) {
+	return dc.Spec.Replicas, dc.Status.UpdatedReplicas, dc.Status.AvailableReplicas
+}
+
+func (rc *ReconcileContext) setDegradedCondition(status *jenkinsv1alpha1.JenkinsStatus) {
+	if rc.Diagnostics.Failed() {
+		status.SetCondition(jenkinsv1alpha1.JenkinsDegraded, corev1.ConditionTrue, ReasonReconcileFailed,
+			rc.Diagnostics.Message())
+		return
+	}
+	status.SetCondition(jenkinsv1alpha1.JenkinsDegraded, corev1.ConditionFalse, ReasonReconcileSucceeded,
+		"All managed resources are reconciled")
+}
+
+func (rc *ReconcileContext) setPersistenceCondition(status *jenkinsv1alpha1.JenkinsStatus) {
+	if !rc.isPersistent() {
+		status.SetCondition(jenkinsv1alpha1.JenkinsPersistenceReady, corev1.ConditionTrue, ReasonEphemeral,
+			"JENKINS_HOME is stored in an emptyDir volume")
+		return
+	}
+	pvc := rc.ControlledResources.PersistentVolumeClaim
+	if pvc != nil && pvc.Status.Phase == corev1.ClaimBound {
+		status.SetCondition(jenkinsv1alpha1.JenkinsPersistenceReady, corev1.ConditionTrue, ReasonClaimBound,
+			fmt.Sprintf("PersistentVolumeClaim %s is bound", pvc.GetName()))
+		return
+	}
+	status.SetCondition(jenkinsv1alpha1.JenkinsPersistenceReady, corev1.ConditionFalse, ReasonClaimPending,
+		"PersistentVolumeClaim is not bound yet")
+}
+
+func (rc *ReconcileContext) setRouteCondition(status *jenkinsv1alpha1.JenkinsStatus) {
+	route := rc.ControlledResources.Route
+	if route == nil {
+		status.RemoveCondition(jenkinsv1alpha1.JenkinsRouteAdmitted)
+		return
+	}
+	if host, admitted := routeAdmittedHost(route); admitted {
+		status.URL = routeURL(route, host)
+		status.SetCondition(jenkinsv1alpha1.JenkinsRouteAdmitted, corev1.ConditionTrue, ReasonRouteAdmitted,
+			fmt.Sprintf("Route %s is admitted", route.GetName()))
+		return
+	}
+	status.SetCondition(jenkinsv1alpha1.JenkinsRouteAdmitted, corev1.ConditionFalse, ReasonRouteNotAdmitted,
+		fmt.Sprintf("Route %s has not been admitted by any router", route.GetName()))
+}
+
+// routeAdmittedHost returns the host of the first router which admitted the route
+func routeAdmittedHost(route *routev1.Route) (string, bool) {
+	for _, ingress := range route.Status.Ingress {
+		for _, condition := range ingress.Conditions {
+			if condition.Type == routev1.RouteAdmitted && condition.Status == corev1.ConditionTrue {
+				host := ingress.Host
+				if len(host) == 0 {
+					host = route.Spec.Host
+				}
+				return host, true
+			}
+		}
+	}
+	return "", false
+}
+
+func routeURL(route *routev1.Route, host string) string {
+	scheme := "http"
+	if route.Spec.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + host
+}
+
+// setIngressCondition reports whether an ingress controller serves the Ingress. The URL of an admitted Route
+// is preferred over the URL of the Ingress.
+func (rc *ReconcileContext) setIngressCondition(status *jenkinsv1alpha1.JenkinsStatus) {
+	ingress := rc.ControlledResources.Ingress
+	if ingress == nil {
+		status.RemoveCondition(jenkinsv1alpha1.JenkinsIngressAdmitted)
+		return
+	}
+	if host, admitted := ingressAdmittedHost(ingress); admitted {
+		if len(status.URL) == 0 {
+			status.URL = ingressURL(ingress, host)
+		}
+		status.SetCondition(jenkinsv1alpha1.JenkinsIngressAdmitted, corev1.ConditionTrue, ReasonIngressAdmitted,
+			fmt.Sprintf("Ingress %s is served at %s", ingress.GetName(), host))
+		return
+	}
+	status.SetCondition(jenkinsv1alpha1.JenkinsIngressAdmitted, corev1.ConditionFalse, ReasonIngressNotAdmitted,
+		fmt.Sprintf("Ingress %s has not been given an address by any ingress controller", ingress.GetName()))
+}
+
+// ingressAdmittedHost returns the host of the Ingress, or the address given by the ingress controller when
+// the Ingress matches every host
+func ingressAdmittedHost(ingress *extensionsv1beta1.Ingress) (string, bool) {
+	if len(ingress.Status.LoadBalancer.Ingress) == 0 {
+		return "", false
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if len(rule.Host) > 0 {
+			return rule.Host, true
+		}
+	}
+	address := ingress.Status.LoadBalancer.Ingress[0]
+	if len(address.Hostname) > 0 {
+		return address.Hostname, true
+	}
+	return address.IP, len(address.IP) > 0
+}
+
+func ingressURL(ingress *extensionsv1beta1.Ingress, host string) string {
+	scheme := "http"
+	if len(ingress.Spec.TLS) > 0 {
+		scheme = "https"
+	}
+	return scheme + "://" + host
+}
+
+func phaseFromConditions(status *jenkinsv1alpha1.JenkinsStatus) jenkinsv1alpha1.JenkinsPhase {
+	if status.IsConditionTrue(jenkinsv1alpha1.JenkinsDegraded) {
+		return jenkinsv1alpha1.JenkinsPhaseFailed
+	}
+	if status.IsConditionTrue(jenkinsv1alpha1.JenkinsAvailable) {
+		return jenkinsv1alpha1.JenkinsPhaseReady
+	}
+	return jenkinsv1alpha1.JenkinsPhaseProvisioning
+}
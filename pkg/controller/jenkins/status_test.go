@@ -0,0 +1,63 @@
+package jenkins
+
+import (
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+)
+
+func TestRouteAdmittedHost(t *testing.T) {
+	t.Run("TestRouteNotAdmitted", func(t *testing.T) {
+		route := &routev1.Route{Spec: routev1.RouteSpec{Host: "jenkins.example.com"}}
+		_, admitted := routeAdmittedHost(route)
+		require.False(t, admitted)
+	})
+	t.Run("TestRouteAdmitted", func(t *testing.T) {
+		route := &routev1.Route{
+			Spec: routev1.RouteSpec{TLS: &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}},
+			Status: routev1.RouteStatus{Ingress: []routev1.RouteIngress{{
+				Host:       "jenkins.apps.example.com",
+				Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: corev1.ConditionTrue}},
+			}}},
+		}
+		host, admitted := routeAdmittedHost(route)
+		require.True(t, admitted)
+		require.Equal(t, "https://jenkins.apps.example.com", routeURL(route, host))
+	})
+}
+
+func TestPhaseFromConditions(t *testing.T) {
+	t.Run("TestPhaseTransitions", func(t *testing.T) {
+		status := &jenkinsv1alpha1.JenkinsStatus{}
+		require.Equal(t, jenkinsv1alpha1.JenkinsPhaseProvisioning, phaseFromConditions(status))
+
+		status.SetCondition(jenkinsv1alpha1.JenkinsAvailable, corev1.ConditionTrue, ReasonMinimumReplicasAvailable, "")
+		require.Equal(t, jenkinsv1alpha1.JenkinsPhaseReady, phaseFromConditions(status))
+		transitionTime := status.GetCondition(jenkinsv1alpha1.JenkinsAvailable).LastTransitionTime
+
+		// Updating a condition without changing its status keeps the transition time
+		status.SetCondition(jenkinsv1alpha1.JenkinsAvailable, corev1.ConditionTrue, ReasonMinimumReplicasAvailable, "1 replica(s) available")
+		require.Equal(t, transitionTime, status.GetCondition(jenkinsv1alpha1.JenkinsAvailable).LastTransitionTime)
+		require.Len(t, status.Conditions, 1)
+
+		status.SetCondition(jenkinsv1alpha1.JenkinsDegraded, corev1.ConditionTrue, ReasonReconcileFailed, "")
+		require.Equal(t, jenkinsv1alpha1.JenkinsPhaseFailed, phaseFromConditions(status))
+	})
+}
+
+func TestIngressAdmittedHost(t *testing.T) {
+	t.Run("TestIngressWithoutHost", func(t *testing.T) {
+		ingress := &extensionsv1beta1.Ingress{Spec: extensionsv1beta1.IngressSpec{Rules: []extensionsv1beta1.IngressRule{{}}}}
+		_, admitted := ingressAdmittedHost(ingress)
+		require.False(t, admitted)
+
+		ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}}
+		host, admitted := ingressAdmittedHost(ingress)
+		require.True(t, admitted)
+		require.Equal(t, "http://lb.example.com", ingressURL(ingress, host))
+	})
+}
@@ -0,0 +1,398 @@
+package jenkins
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	kappsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// errRecreateRequired is returned when a field owned by the operator changed but cannot be updated in place
+var errRecreateRequired = errors.New("immutable field changed, resource must be recreated")
+
+// newEmptyObject returns a new zero valued object of the same type as obj
+func newEmptyObject(obj runtime.Object) runtime.Object {
+	return reflect.New(reflect.TypeOf(obj).Elem()).Interface().(runtime.Object)
+}
+
+// mergeManagedFields copies the fields owned by the operator from desired into live.
+// Fields which are not set by the operator (replicas managed by an HPA, injected sidecars,
+// generated hosts, ...) are left untouched.
+func mergeManagedFields(desired, live runtime.Object) error {
+	desiredMeta, ok := desired.(metav1.Object)
+	if !ok {
+		return fmt.Errorf("%T is not a metav1.Object", desired)
+	}
+	liveMeta := live.(metav1.Object)
+	liveMeta.SetLabels(mergeStringMap(liveMeta.GetLabels(), desiredMeta.GetLabels()))
+	liveMeta.SetAnnotations(mergeStringMap(liveMeta.GetAnnotations(), desiredMeta.GetAnnotations()))
+	if metav1.GetControllerOf(liveMeta) == nil && metav1.GetControllerOf(desiredMeta) != nil {
+		liveMeta.SetOwnerReferences(append(liveMeta.GetOwnerReferences(), *metav1.GetControllerOf(desiredMeta)))
+	}
+
+	switch d := desired.(type) {
+	case *kappsv1.Deployment:
+		l := live.(*kappsv1.Deployment)
+		if _, found := d.Annotations[ImageTriggersAnnotation]; !found {
+			// The image of the Jenkins container does not come from an ImageStreamTag anymore
+			delete(l.Annotations, ImageTriggersAnnotation)
+		}
+		if l.Spec.Replicas == nil {
+			l.Spec.Replicas = d.Spec.Replicas
+		}
+		l.Spec.Strategy.Type = d.Spec.Strategy.Type
+		if d.Spec.Strategy.Type == kappsv1.RecreateDeploymentStrategyType {
+			l.Spec.Strategy.RollingUpdate = nil
+		}
+		mergePodTemplateSpec(&d.Spec.Template, &l.Spec.Template)
+	case *appsv1.DeploymentConfig:
+		l := live.(*appsv1.DeploymentConfig)
+		l.Spec.Selector = mergeStringMap(l.Spec.Selector, d.Spec.Selector)
+		l.Spec.Strategy.Type = d.Spec.Strategy.Type
+		l.Spec.Triggers = mergeDeploymentTriggers(d.Spec.Triggers, l.Spec.Triggers)
+		if l.Spec.Template == nil {
+			l.Spec.Template = d.Spec.Template
+		} else {
+			mergePodTemplateSpec(d.Spec.Template, l.Spec.Template)
+		}
+	case *corev1.Service:
+		l := live.(*corev1.Service)
+		l.Spec.Selector = d.Spec.Selector
+		l.Spec.Type = d.Spec.Type
+		l.Spec.Ports = mergeServicePorts(d.Spec.Ports, l.Spec.Ports)
+	case *routev1.Route:
+		l := live.(*routev1.Route)
+		if len(d.Spec.Host) > 0 {
+			l.Spec.Host = d.Spec.Host
+		}
+		l.Spec.To.Kind = d.Spec.To.Kind
+		l.Spec.To.Name = d.Spec.To.Name
+		l.Spec.Port = d.Spec.Port
+		l.Spec.TLS = d.Spec.TLS
+	case *extensionsv1beta1.Ingress:
+		l := live.(*extensionsv1beta1.Ingress)
+		if _, found := d.Annotations[IngressClassAnnotation]; !found {
+			// The ingress controller is not selected through spec.ingress anymore
+			delete(l.Annotations, IngressClassAnnotation)
+		}
+		l.Spec.Rules = d.Spec.Rules
+		l.Spec.TLS = d.Spec.TLS
+	case *rbacv1.RoleBinding:
+		l := live.(*rbacv1.RoleBinding)
+		if !reflect.DeepEqual(l.RoleRef, d.RoleRef) {
+			return errRecreateRequired
+		}
+		l.Subjects = d.Subjects
+	case *batchv1beta1.CronJob:
+		l := live.(*batchv1beta1.CronJob)
+		l.Spec.Schedule = d.Spec.Schedule
+		l.Spec.ConcurrencyPolicy = d.Spec.ConcurrencyPolicy
+		l.Spec.SuccessfulJobsHistoryLimit = d.Spec.SuccessfulJobsHistoryLimit
+		l.Spec.FailedJobsHistoryLimit = d.Spec.FailedJobsHistoryLimit
+		l.Spec.JobTemplate.Labels = d.Spec.JobTemplate.Labels
+		l.Spec.JobTemplate.Spec.BackoffLimit = d.Spec.JobTemplate.Spec.BackoffLimit
+		mergePodTemplateSpec(&d.Spec.JobTemplate.Spec.Template, &l.Spec.JobTemplate.Spec.Template)
+		// The command and environment of the backup container are entirely owned by the operator
+		liveSpec, desiredSpec := &l.Spec.JobTemplate.Spec.Template.Spec, &d.Spec.JobTemplate.Spec.Template.Spec
+		for i := range liveSpec.Containers {
+			for _, container := range desiredSpec.Containers {
+				if liveSpec.Containers[i].Name == container.Name {
+					liveSpec.Containers[i].Command = container.Command
+					liveSpec.Containers[i].Env = container.Env
+				}
+			}
+		}
+		liveSpec.Affinity = desiredSpec.Affinity
+	case *corev1.ServiceAccount:
+		// Only labels and annotations are owned, secrets are managed by the token controller
+	case *corev1.PersistentVolumeClaim:
+		l := live.(*corev1.PersistentVolumeClaim)
+		desiredSize := d.Spec.Resources.Requests[corev1.ResourceStorage]
+		liveSize := l.Spec.Resources.Requests[corev1.ResourceStorage]
+		// Claims can only grow, and only the requested size can be changed once bound
+		if desiredSize.Cmp(liveSize) > 0 {
+			if l.Spec.Resources.Requests == nil {
+				l.Spec.Resources.Requests = corev1.ResourceList{}
+			}
+			l.Spec.Resources.Requests[corev1.ResourceStorage] = desiredSize
+		}
+	}
+	return nil
+}
+
+// mergeDeploymentTriggers sets the ImageChange trigger of the Jenkins container and keeps the other triggers.
+// The last triggered image recorded by the server is kept when the trigger did not change.
+func mergeDeploymentTriggers(desired, live appsv1.DeploymentTriggerPolicies) appsv1.DeploymentTriggerPolicies {
+	var desiredImageChange *appsv1.DeploymentTriggerImageChangeParams
+	for _, trigger := range desired {
+		if trigger.Type == appsv1.DeploymentTriggerOnImageChange {
+			desiredImageChange = trigger.ImageChangeParams
+		}
+	}
+
+	merged := appsv1.DeploymentTriggerPolicies{}
+	hasConfigChange, hasImageChange := false, false
+	for _, trigger := range live {
+		if trigger.Type == appsv1.DeploymentTriggerOnImageChange && isJenkinsImageChangeTrigger(trigger.ImageChangeParams) {
+			if desiredImageChange == nil {
+				continue
+			}
+			params := desiredImageChange.DeepCopy()
+			if reflect.DeepEqual(trigger.ImageChangeParams.From, params.From) {
+				params.LastTriggeredImage = trigger.ImageChangeParams.LastTriggeredImage
+			}
+			trigger.ImageChangeParams = params
+			hasImageChange = true
+		}
+		hasConfigChange = hasConfigChange || trigger.Type == appsv1.DeploymentTriggerOnConfigChange
+		merged = append(merged, trigger)
+	}
+	for _, trigger := range desired {
+		if (trigger.Type == appsv1.DeploymentTriggerOnConfigChange && !hasConfigChange) ||
+			(trigger.Type == appsv1.DeploymentTriggerOnImageChange && !hasImageChange) {
+			merged = append(merged, trigger)
+		}
+	}
+	if len(merged) == 0 && live == nil {
+		return live
+	}
+	return merged
+}
+
+func isJenkinsImageChangeTrigger(params *appsv1.DeploymentTriggerImageChangeParams) bool {
+	if params == nil {
+		return false
+	}
+	for _, name := range params.ContainerNames {
+		if name == JenkinsContainerName {
+			return true
+		}
+	}
+	return false
+}
+
+// mergePodTemplateSpec updates the jenkins container and volumes of live, keeping the containers,
+// volumes, labels and annotations added by others
+func mergePodTemplateSpec(desired, live *corev1.PodTemplateSpec) {
+	pruneRemovedOverrides(desired, live)
+	live.Labels = mergeStringMap(live.Labels, desired.Labels)
+	live.Annotations = mergeStringMap(live.Annotations, desired.Annotations)
+	live.Spec.ServiceAccountName = desired.Spec.ServiceAccountName
+	for _, container := range desired.Spec.Containers {
+		found := false
+		for i := range live.Spec.Containers {
+			if live.Spec.Containers[i].Name == container.Name {
+				mergeContainer(&container, &live.Spec.Containers[i])
+				found = true
+				break
+			}
+		}
+		if !found {
+			live.Spec.Containers = append(live.Spec.Containers, container)
+		}
+	}
+	for _, volume := range desired.Spec.Volumes {
+		found := false
+		for i := range live.Spec.Volumes {
+			if live.Spec.Volumes[i].Name == volume.Name {
+				if !reflect.DeepEqual(live.Spec.Volumes[i].VolumeSource, volume.VolumeSource) {
+					live.Spec.Volumes[i].VolumeSource = volume.VolumeSource
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			live.Spec.Volumes = append(live.Spec.Volumes, volume)
+		}
+	}
+}
+
+// pruneRemovedOverrides removes from live the environment variables and volumes which were added from the cr
+// by a previous reconcile and are not desired anymore. Their names are recorded in the pod template annotations.
+func pruneRemovedOverrides(desired, live *corev1.PodTemplateSpec) {
+	desiredEnv := map[string]bool{}
+	for _, container := range desired.Spec.Containers {
+		for _, env := range container.Env {
+			desiredEnv[env.Name] = true
+		}
+	}
+	desiredVolumes := map[string]bool{}
+	for _, volume := range desired.Spec.Volumes {
+		desiredVolumes[volume.Name] = true
+	}
+	removedEnv := map[string]bool{}
+	for _, name := range splitNames(live.Annotations[JenkinsExtraEnvAnnotation]) {
+		removedEnv[name] = !desiredEnv[name]
+	}
+	removedVolumes := map[string]bool{}
+	for _, name := range splitNames(live.Annotations[JenkinsExtraVolumesAnnotation]) {
+		removedVolumes[name] = !desiredVolumes[name]
+	}
+
+	volumes := []corev1.Volume{}
+	for _, volume := range live.Spec.Volumes {
+		if !removedVolumes[volume.Name] {
+			volumes = append(volumes, volume)
+		}
+	}
+	live.Spec.Volumes = volumes
+	for i := range live.Spec.Containers {
+		container := &live.Spec.Containers[i]
+		if container.Name != JenkinsContainerName {
+			continue
+		}
+		env := []corev1.EnvVar{}
+		for _, envVar := range container.Env {
+			if !removedEnv[envVar.Name] {
+				env = append(env, envVar)
+			}
+		}
+		container.Env = env
+		mounts := []corev1.VolumeMount{}
+		for _, mount := range container.VolumeMounts {
+			if !removedVolumes[mount.Name] {
+				mounts = append(mounts, mount)
+			}
+		}
+		container.VolumeMounts = mounts
+	}
+}
+
+func splitNames(names string) []string {
+	if len(names) == 0 {
+		return []string{}
+	}
+	return strings.Split(names, ",")
+}
+
+func mergeContainer(desired, live *corev1.Container) {
+	live.Image = desired.Image
+	live.Env = mergeEnvVars(desired.Env, live.Env)
+	live.EnvFrom = desired.EnvFrom
+	live.VolumeMounts = mergeVolumeMounts(desired.VolumeMounts, live.VolumeMounts)
+	live.LivenessProbe = mergeProbe(desired.LivenessProbe, live.LivenessProbe)
+	live.ReadinessProbe = mergeProbe(desired.ReadinessProbe, live.ReadinessProbe)
+	if !resourceListEqual(desired.Resources.Limits, live.Resources.Limits) {
+		live.Resources.Limits = desired.Resources.Limits
+	}
+	if !resourceListEqual(desired.Resources.Requests, live.Resources.Requests) {
+		live.Resources.Requests = desired.Resources.Requests
+	}
+}
+
+// mergeEnvVars sets the variables defined by the operator and keeps the ones injected by others
+func mergeEnvVars(desired, live []corev1.EnvVar) []corev1.EnvVar {
+	merged := append([]corev1.EnvVar{}, live...)
+	for _, env := range desired {
+		found := false
+		for i := range merged {
+			if merged[i].Name == env.Name {
+				merged[i] = env
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, env)
+		}
+	}
+	return merged
+}
+
+func mergeVolumeMounts(desired, live []corev1.VolumeMount) []corev1.VolumeMount {
+	merged := append([]corev1.VolumeMount{}, live...)
+	for _, mount := range desired {
+		found := false
+		for i := range merged {
+			if merged[i].Name == mount.Name {
+				merged[i].MountPath = mount.MountPath
+				merged[i].SubPath = mount.SubPath
+				merged[i].ReadOnly = mount.ReadOnly
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, mount)
+		}
+	}
+	return merged
+}
+
+// mergeProbe sets the probe fields defined by the operator and keeps the defaults set by the API server
+func mergeProbe(desired, live *corev1.Probe) *corev1.Probe {
+	if desired == nil || live == nil {
+		return desired
+	}
+	merged := live.DeepCopy()
+	if desired.HTTPGet != nil && merged.HTTPGet != nil {
+		merged.HTTPGet.Path = desired.HTTPGet.Path
+		merged.HTTPGet.Port = desired.HTTPGet.Port
+	} else {
+		merged.Handler = desired.Handler
+	}
+	merged.InitialDelaySeconds = desired.InitialDelaySeconds
+	merged.TimeoutSeconds = desired.TimeoutSeconds
+	merged.FailureThreshold = desired.FailureThreshold
+	if desired.PeriodSeconds > 0 {
+		merged.PeriodSeconds = desired.PeriodSeconds
+	}
+	if desired.SuccessThreshold > 0 {
+		merged.SuccessThreshold = desired.SuccessThreshold
+	}
+	return merged
+}
+
+// mergeServicePorts sets the ports defined by the operator keeping the allocated node ports
+func mergeServicePorts(desired, live []corev1.ServicePort) []corev1.ServicePort {
+	merged := []corev1.ServicePort{}
+	for _, port := range desired {
+		for _, livePort := range live {
+			if livePort.Name == port.Name && livePort.Port == port.Port &&
+				livePort.Protocol == port.Protocol && livePort.TargetPort.String() == port.TargetPort.String() {
+				port = livePort
+				break
+			}
+		}
+		merged = append(merged, port)
+	}
+	return merged
+}
+
+func resourceListEqual(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, quantity := range a {
+		other, found := b[name]
+		if !found || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeStringMap(live, desired map[string]string) map[string]string {
+	if len(desired) == 0 {
+		return live
+	}
+	merged := map[string]string{}
+	for k, v := range live {
+		merged[k] = v
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,124 @@
+package jenkins
+
+import (
+	"testing"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestMergeManagedFieldsDeployment(t *testing.T) {
+	t.Run("TestKeepsFieldsOwnedByOthers", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		desired := newJenkinsDeployment(cr, JenkinsImage, test_name, test_name+JenkinsJnlpServiceSuffix, false)
+		live := desired.DeepCopy()
+		// replicas managed by an HPA and an injected sidecar
+		live.Spec.Replicas = int32Ptr(3)
+		live.Spec.Template.Spec.Containers = append(live.Spec.Template.Spec.Containers, corev1.Container{Name: "sidecar"})
+		// drift on an operator owned field
+		live.Spec.Template.Spec.Containers[0].Image = "quay.io/someone/jenkins"
+		live.Spec.Template.Spec.Containers[0].Env = live.Spec.Template.Spec.Containers[0].Env[1:]
+
+		require.NoError(t, mergeManagedFields(desired, live))
+		require.Equal(t, int32(3), *live.Spec.Replicas)
+		require.Len(t, live.Spec.Template.Spec.Containers, 2)
+		require.Equal(t, "sidecar", live.Spec.Template.Spec.Containers[1].Name)
+		require.Equal(t, JenkinsImage, live.Spec.Template.Spec.Containers[0].Image)
+		require.ElementsMatch(t, desired.Spec.Template.Spec.Containers[0].Env, live.Spec.Template.Spec.Containers[0].Env)
+	})
+}
+
+func TestMergeManagedFieldsOverrides(t *testing.T) {
+	t.Run("TestRemovedOverridesArePruned", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.JavaOpts = "-Xmx3g"
+		cr.Spec.Env = []corev1.EnvVar{{Name: "EXTRA", Value: "1"}}
+		cr.Spec.Volumes = []corev1.Volume{{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+		cr.Spec.VolumeMounts = []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}}
+		live := newJenkinsDeployment(cr, JenkinsImage, test_name, test_name+JenkinsJnlpServiceSuffix, false)
+		// injected by someone else
+		jenkins := &live.Spec.Template.Spec.Containers[0]
+		jenkins.Env = append(jenkins.Env, corev1.EnvVar{Name: "INJECTED", Value: "1"})
+		live.Spec.Template.Spec.Volumes = append(live.Spec.Template.Spec.Volumes, corev1.Volume{Name: "injected"})
+
+		cr.Spec.JavaOpts = ""
+		cr.Spec.Env = nil
+		cr.Spec.Volumes = nil
+		cr.Spec.VolumeMounts = nil
+		desired := newJenkinsDeployment(cr, JenkinsImage, test_name, test_name+JenkinsJnlpServiceSuffix, false)
+		require.NoError(t, mergeManagedFields(desired, live))
+
+		envNames := []string{}
+		for _, env := range live.Spec.Template.Spec.Containers[0].Env {
+			envNames = append(envNames, env.Name)
+		}
+		require.Contains(t, envNames, "INJECTED")
+		require.NotContains(t, envNames, "EXTRA")
+		require.NotContains(t, envNames, JenkinsJavaOptsEnv)
+		require.Len(t, live.Spec.Template.Spec.Containers[0].VolumeMounts, 1)
+		volumeNames := []string{}
+		for _, volume := range live.Spec.Template.Spec.Volumes {
+			volumeNames = append(volumeNames, volume.Name)
+		}
+		require.Equal(t, []string{JenkinsVolumeName, "injected"}, volumeNames)
+	})
+}
+
+func TestMergeManagedFieldsDeploymentConfig(t *testing.T) {
+	t.Run("TestImageChangeTrigger", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.JenkinsImageRef = "custom-jenkins"
+		desired := newJenkinsDeploymentConfig(cr, JenkinsImage, test_name, test_name+JenkinsJnlpServiceSuffix, false)
+		require.Len(t, desired.Spec.Triggers, 2)
+		require.Equal(t, "custom-jenkins:latest", desired.Spec.Triggers[1].ImageChangeParams.From.Name)
+
+		// the image last triggered by the server is kept
+		live := desired.DeepCopy()
+		live.Spec.Triggers[1].ImageChangeParams.LastTriggeredImage = "custom-jenkins@sha256:5678"
+		require.NoError(t, mergeManagedFields(desired, live))
+		require.Equal(t, "custom-jenkins@sha256:5678", live.Spec.Triggers[1].ImageChangeParams.LastTriggeredImage)
+
+		// and the trigger is removed with the reference
+		cr.Spec.JenkinsImageRef = ""
+		desired = newJenkinsDeploymentConfig(cr, JenkinsImage, test_name, test_name+JenkinsJnlpServiceSuffix, false)
+		require.NoError(t, mergeManagedFields(desired, live))
+		require.Len(t, live.Spec.Triggers, 1)
+		require.Equal(t, appsv1.DeploymentTriggerOnConfigChange, live.Spec.Triggers[0].Type)
+	})
+}
+
+func TestMergeManagedFieldsPvc(t *testing.T) {
+	t.Run("TestPvcOnlyGrows", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Persistence.Size = "5Gi"
+		desired := newJenkinsPvc(cr, test_name)
+
+		live := desired.DeepCopy()
+		live.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("1Gi")
+		require.NoError(t, mergeManagedFields(desired, live))
+		size := live.Spec.Resources.Requests[corev1.ResourceStorage]
+		require.Equal(t, "5Gi", size.String())
+
+		live.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("10Gi")
+		require.NoError(t, mergeManagedFields(desired, live))
+		size = live.Spec.Resources.Requests[corev1.ResourceStorage]
+		require.Equal(t, "10Gi", size.String())
+	})
+}
+
+func TestMergeManagedFieldsRoleBinding(t *testing.T) {
+	t.Run("TestRoleRefChangeRequiresRecreate", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		desired := newJenkinsRoleBinding(cr, test_name)
+		live := desired.DeepCopy()
+		live.Subjects = nil
+		require.NoError(t, mergeManagedFields(desired, live))
+		require.Equal(t, desired.Subjects, live.Subjects)
+
+		live.RoleRef.Name = "view"
+		require.Equal(t, errRecreateRequired, mergeManagedFields(desired, live))
+	})
+}
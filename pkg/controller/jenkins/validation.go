@@ -0,0 +1,533 @@
+package jenkins
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// reservedEnvVars are set by the operator and cannot be overridden through spec.env
+var reservedEnvVars = map[string]string{
+	"JENKINS_SERVICE_NAME":    "is set by the operator",
+	"JNLP_SERVICE_NAME":       "is set by the operator",
+	JenkinsJavaOptsEnv:        "is set by the operator, use spec.javaOpts instead",
+	JenkinsOptsEnv:            "is set by the operator, use spec.jenkinsOpts instead",
+	JenkinsCascConfigEnv:      "is set by the operator, use spec.configurationAsCode instead",
+	JenkinsCascReloadTokenEnv: "is set by the operator",
+	JenkinsAdminUsernameEnv:   "is set by the operator from the admin credentials",
+	JenkinsAdminPasswordEnv:   "is set by the operator from the admin credentials",
+	JenkinsAdminAPITokenEnv:   "is set by the operator from the admin credentials",
+}
+
+// ValidateJenkins checks the spec of the cr and the names derived from its name before any object is built from it
+func ValidateJenkins(cr *jenkinsv1alpha1.Jenkins) field.ErrorList {
+	spec := field.NewPath("spec")
+	errs := field.ErrorList{}
+	errs = append(errs, validateDerivedNames(cr, field.NewPath("metadata", "name"))...)
+	errs = append(errs, validateDeletionPolicy(cr, spec.Child("deletionPolicy"))...)
+	errs = append(errs, validatePersistence(cr.Spec.Persistence, spec.Child("persistence"))...)
+	errs = append(errs, validateImageSource(cr.Spec.Image, spec.Child("image"))...)
+	errs = append(errs, validateJenkinsImageRef(cr, spec.Child("jenkinsImageRef"))...)
+	errs = append(errs, validateResources(cr.Spec.Resources, spec.Child("resources"))...)
+	errs = append(errs, validateProbe(cr.Spec.LivenessProbe, spec.Child("livenessProbe"))...)
+	errs = append(errs, validateProbe(cr.Spec.ReadinessProbe, spec.Child("readinessProbe"))...)
+	errs = append(errs, validateEnv(cr.Spec.Env, spec.Child("env"))...)
+	errs = append(errs, validateVolumes(cr.Spec.Volumes, cr.Spec.VolumeMounts, spec)...)
+	errs = append(errs, validateConfigurationAsCode(cr.Spec.ConfigurationAsCode, spec.Child("configurationAsCode"))...)
+	errs = append(errs, validateBackupSchedule(cr, spec.Child("backup"))...)
+	errs = append(errs, validateObjectStorage(cr, spec.Child("objectStorage"))...)
+	errs = append(errs, validateIngress(cr.Spec.Ingress, spec.Child("ingress"))...)
+	errs = append(errs, validateRoute(cr.Spec.Route, spec.Child("route"))...)
+	errs = append(errs, validateAuthentication(cr.Spec.Authentication, spec.Child("authentication"))...)
+	errs = append(errs, validateServiceAccount(cr, spec.Child("serviceAccount"))...)
+	return errs
+}
+
+// ValidateJenkinsUpdate checks the changes of the spec which cannot be applied to the existing resources. The
+// persistence of an instance cannot be disabled: enabling it again would bypass the checks of the claim.
+func ValidateJenkinsUpdate(cr, old *jenkinsv1alpha1.Jenkins) field.ErrorList {
+	errs := field.ErrorList{}
+	path := field.NewPath("spec", "persistence")
+	persistence, oldPersistence := cr.Spec.Persistence, old.Spec.Persistence
+	if !oldPersistence.Enabled {
+		return errs
+	}
+	if !persistence.Enabled {
+		return append(errs, field.Forbidden(path.Child("enabled"), "cannot be disabled, JENKINS_HOME is stored in the claim of the instance"))
+	}
+	if persistence.ExistingClaim != oldPersistence.ExistingClaim {
+		errs = append(errs, field.Invalid(path.Child("existingClaim"), persistence.ExistingClaim, "field is immutable"))
+	}
+	if usesExistingClaim(cr) || usesExistingClaim(old) {
+		return errs
+	}
+	// The claim can only grow, the other fields defining it only apply to a new claim
+	size, sizeErr := resource.ParseQuantity(claimSize(persistence))
+	oldSize, oldSizeErr := resource.ParseQuantity(claimSize(oldPersistence))
+	if sizeErr == nil && oldSizeErr == nil && size.Cmp(oldSize) < 0 {
+		errs = append(errs, field.Forbidden(path.Child("size"), "cannot be decreased from "+oldSize.String()+" to "+size.String()))
+	}
+	immutable := []struct {
+		name     string
+		value    interface{}
+		oldValue interface{}
+	}{
+		{"storageClassName", persistence.StorageClassName, oldPersistence.StorageClassName},
+		{"accessModes", persistence.AccessModes, oldPersistence.AccessModes},
+		{"volumeMode", persistence.VolumeMode, oldPersistence.VolumeMode},
+		{"selector", persistence.Selector, oldPersistence.Selector},
+	}
+	for _, f := range immutable {
+		if !reflect.DeepEqual(f.value, f.oldValue) {
+			errs = append(errs, field.Invalid(path.Child(f.name), f.value, "field is immutable"))
+		}
+	}
+	return errs
+}
+
+// claimSize returns the requested size of the claim holding JENKINS_HOME
+func claimSize(persistence jenkinsv1alpha1.JenkinsPersistence) string {
+	if len(persistence.Size) == 0 {
+		return JenkinsPvcDefaultSize
+	}
+	return persistence.Size
+}
+
+// validateDerivedNames checks that the names of the resources derived from the name of the instance are valid.
+// The Services must be DNS-1035 labels, the Jobs and CronJobs have a shorter limit than other resources.
+func validateDerivedNames(cr *jenkinsv1alpha1.Jenkins, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for _, name := range []string{cr.Name, cr.Name + JenkinsJnlpServiceSuffix} {
+		for _, msg := range validation.IsDNS1035Label(name) {
+			errs = append(errs, field.Invalid(path, cr.Name, "the Service "+name+" derived from the name is invalid: "+msg))
+		}
+	}
+	limits := []struct {
+		kind    string
+		name    string
+		max     int
+		enabled bool
+	}{
+		{"CronJob", cr.Name + ScheduledBackupSuffix, MaxCronJobNameLength, cr.Spec.Backup != nil},
+		{"Job", objectStorageBackupJobName(cr, ""), validation.DNS1123LabelMaxLength, cr.Spec.ObjectStorage != nil},
+		{"Job", cr.Name + JenkinsSnapshotSuffix, validation.DNS1123LabelMaxLength,
+			deletionPolicy(cr) == jenkinsv1alpha1.JenkinsDeletionPolicySnapshot},
+	}
+	for _, limit := range limits {
+		if limit.enabled && len(limit.name) > limit.max {
+			errs = append(errs, field.Invalid(path, cr.Name, fmt.Sprintf("must be no more than %d characters to name the %s %s",
+				limit.max-len(limit.name)+len(cr.Name), limit.kind, limit.name)))
+		}
+	}
+	return errs
+}
+
+func validateDeletionPolicy(cr *jenkinsv1alpha1.Jenkins, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	switch cr.Spec.DeletionPolicy {
+	case "", jenkinsv1alpha1.JenkinsDeletionPolicyDelete, jenkinsv1alpha1.JenkinsDeletionPolicyRetain, jenkinsv1alpha1.JenkinsDeletionPolicySnapshot:
+	default:
+		errs = append(errs, field.NotSupported(path, cr.Spec.DeletionPolicy, []string{
+			string(jenkinsv1alpha1.JenkinsDeletionPolicyDelete),
+			string(jenkinsv1alpha1.JenkinsDeletionPolicyRetain),
+			string(jenkinsv1alpha1.JenkinsDeletionPolicySnapshot),
+		}))
+	}
+	return errs
+}
+
+func validateAuthentication(mode jenkinsv1alpha1.JenkinsAuthenticationMode, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	switch mode {
+	case "", jenkinsv1alpha1.JenkinsAuthenticationOpenShiftOAuth, jenkinsv1alpha1.JenkinsAuthenticationBuiltin:
+	default:
+		errs = append(errs, field.NotSupported(path, mode, []string{
+			string(jenkinsv1alpha1.JenkinsAuthenticationOpenShiftOAuth), string(jenkinsv1alpha1.JenkinsAuthenticationBuiltin),
+		}))
+	}
+	return errs
+}
+
+func validatePersistence(persistence jenkinsv1alpha1.JenkinsPersistence, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if len(persistence.Size) > 0 {
+		if size, err := resource.ParseQuantity(persistence.Size); err != nil {
+			errs = append(errs, field.Invalid(path.Child("size"), persistence.Size, err.Error()))
+		} else if size.Sign() <= 0 {
+			errs = append(errs, field.Invalid(path.Child("size"), persistence.Size, "must be greater than zero"))
+		}
+	}
+	supportedModes := []string{string(corev1.ReadWriteOnce), string(corev1.ReadOnlyMany), string(corev1.ReadWriteMany)}
+	for i, mode := range persistence.AccessModes {
+		if mode != corev1.ReadWriteOnce && mode != corev1.ReadOnlyMany && mode != corev1.ReadWriteMany {
+			errs = append(errs, field.NotSupported(path.Child("accessModes").Index(i), mode, supportedModes))
+		}
+	}
+	if persistence.VolumeMode != nil && *persistence.VolumeMode != corev1.PersistentVolumeFilesystem {
+		errs = append(errs, field.NotSupported(path.Child("volumeMode"), *persistence.VolumeMode,
+			[]string{string(corev1.PersistentVolumeFilesystem)}))
+	}
+	if persistence.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(persistence.Selector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("selector"), persistence.Selector, err.Error()))
+		}
+	}
+	for key, value := range persistence.Labels {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, field.Invalid(path.Child("labels"), key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(value) {
+			errs = append(errs, field.Invalid(path.Child("labels").Key(key), value, msg))
+		}
+	}
+	for key := range persistence.Annotations {
+		for _, msg := range validation.IsQualifiedName(strings.ToLower(key)) {
+			errs = append(errs, field.Invalid(path.Child("annotations"), key, msg))
+		}
+	}
+	if len(persistence.ExistingClaim) > 0 {
+		for _, msg := range validation.IsDNS1123Subdomain(persistence.ExistingClaim) {
+			errs = append(errs, field.Invalid(path.Child("existingClaim"), persistence.ExistingClaim, msg))
+		}
+		if len(persistence.Size) > 0 || persistence.StorageClassName != nil || len(persistence.AccessModes) > 0 ||
+			persistence.VolumeMode != nil || persistence.Selector != nil || len(persistence.Labels) > 0 || len(persistence.Annotations) > 0 {
+			errs = append(errs, field.Invalid(path.Child("existingClaim"), persistence.ExistingClaim,
+				"cannot be combined with the fields defining the claim created by the operator"))
+		}
+	}
+	return errs
+}
+
+func validateImageSource(source *jenkinsv1alpha1.JenkinsImageSource, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if source == nil {
+		return errs
+	}
+	if len(source.Reference) > 0 && len(source.ImageStreamTag) > 0 {
+		errs = append(errs, field.Invalid(path, source.ImageStreamTag, "only one of reference and imageStreamTag can be set"))
+	}
+	if len(source.ImageStreamTag) > 0 {
+		parts := strings.Split(source.ImageStreamTag, ":")
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			errs = append(errs, field.Invalid(path.Child("imageStreamTag"), source.ImageStreamTag, "must be of the form name:tag"))
+		}
+	} else if len(source.ImageStreamNamespace) > 0 {
+		errs = append(errs, field.Invalid(path.Child("imageStreamNamespace"), source.ImageStreamNamespace, "requires imageStreamTag"))
+	}
+	return errs
+}
+
+func validateJenkinsImageRef(cr *jenkinsv1alpha1.Jenkins, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if len(cr.Spec.JenkinsImageRef) == 0 {
+		return errs
+	}
+	for _, msg := range validation.IsDNS1123Subdomain(cr.Spec.JenkinsImageRef) {
+		errs = append(errs, field.Invalid(path, cr.Spec.JenkinsImageRef, msg))
+	}
+	if cr.Spec.Image != nil && (len(cr.Spec.Image.Reference) > 0 || len(cr.Spec.Image.ImageStreamTag) > 0) {
+		errs = append(errs, field.Invalid(path, cr.Spec.JenkinsImageRef, "cannot be used with image"))
+	}
+	return errs
+}
+
+func validateResources(resources *corev1.ResourceRequirements, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if resources == nil {
+		return errs
+	}
+	for name, request := range resources.Requests {
+		if limit, found := resources.Limits[name]; found && request.Cmp(limit) > 0 {
+			errs = append(errs, field.Invalid(path.Child("requests").Key(string(name)), request.String(),
+				"must be less than or equal to the "+string(name)+" limit "+limit.String()))
+		}
+	}
+	return errs
+}
+
+func validateProbe(probe *jenkinsv1alpha1.JenkinsProbe, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if probe == nil {
+		return errs
+	}
+	if probe.InitialDelaySeconds != nil && *probe.InitialDelaySeconds < 0 {
+		errs = append(errs, field.Invalid(path.Child("initialDelaySeconds"), *probe.InitialDelaySeconds, "must be greater than or equal to 0"))
+	}
+	positive := []struct {
+		name  string
+		value *int32
+	}{
+		{"timeoutSeconds", probe.TimeoutSeconds},
+		{"periodSeconds", probe.PeriodSeconds},
+		{"failureThreshold", probe.FailureThreshold},
+	}
+	for _, p := range positive {
+		if p.value != nil && *p.value < 1 {
+			errs = append(errs, field.Invalid(path.Child(p.name), *p.value, "must be greater than or equal to 1"))
+		}
+	}
+	return errs
+}
+
+func validateEnv(env []corev1.EnvVar, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	names := map[string]bool{}
+	for i, envVar := range env {
+		namePath := path.Index(i).Child("name")
+		for _, msg := range validation.IsEnvVarName(envVar.Name) {
+			errs = append(errs, field.Invalid(namePath, envVar.Name, msg))
+		}
+		if msg, reserved := reservedEnvVars[envVar.Name]; reserved {
+			errs = append(errs, field.Forbidden(namePath, msg))
+		}
+		if names[envVar.Name] {
+			errs = append(errs, field.Duplicate(namePath, envVar.Name))
+		}
+		names[envVar.Name] = true
+	}
+	return errs
+}
+
+func validateVolumes(volumes []corev1.Volume, mounts []corev1.VolumeMount, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	names := map[string]bool{}
+	for i, volume := range volumes {
+		namePath := path.Child("volumes").Index(i).Child("name")
+		for _, msg := range validation.IsDNS1123Label(volume.Name) {
+			errs = append(errs, field.Invalid(namePath, volume.Name, msg))
+		}
+		if volume.Name == JenkinsVolumeName || volume.Name == BuiltinAuthVolumeName {
+			errs = append(errs, field.Forbidden(namePath, "the volume "+volume.Name+" is managed by the operator"))
+		} else if strings.HasPrefix(volume.Name, JenkinsCascVolumePrefix) {
+			errs = append(errs, field.Forbidden(namePath, "the volumes prefixed by "+JenkinsCascVolumePrefix+" are managed by the operator"))
+		}
+		if names[volume.Name] {
+			errs = append(errs, field.Duplicate(namePath, volume.Name))
+		}
+		names[volume.Name] = true
+	}
+
+	mountPaths := map[string]bool{JenkinsVolumeMountPath: true}
+	for i, mount := range mounts {
+		mountPath := path.Child("volumeMounts").Index(i)
+		if !names[mount.Name] {
+			errs = append(errs, field.NotFound(mountPath.Child("name"), mount.Name))
+		}
+		if len(mount.MountPath) == 0 {
+			errs = append(errs, field.Required(mountPath.Child("mountPath"), ""))
+		} else if mountPaths[mount.MountPath] {
+			errs = append(errs, field.Duplicate(mountPath.Child("mountPath"), mount.MountPath))
+		}
+		mountPaths[mount.MountPath] = true
+	}
+	return errs
+}
+
+func validateConfigurationAsCode(casc *jenkinsv1alpha1.JenkinsConfigurationAsCode, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if casc == nil {
+		return errs
+	}
+	sources := []struct {
+		name string
+		refs []corev1.LocalObjectReference
+	}{
+		{"configMaps", casc.ConfigMaps},
+		{"secrets", casc.Secrets},
+	}
+	for _, source := range sources {
+		names := map[string]bool{}
+		for i, ref := range source.refs {
+			namePath := path.Child(source.name).Index(i).Child("name")
+			for _, msg := range validation.IsDNS1123Subdomain(ref.Name) {
+				errs = append(errs, field.Invalid(namePath, ref.Name, msg))
+			}
+			if names[ref.Name] {
+				errs = append(errs, field.Duplicate(namePath, ref.Name))
+			}
+			names[ref.Name] = true
+		}
+	}
+	if len(casc.ConfigMaps) == 0 && len(casc.Secrets) == 0 {
+		errs = append(errs, field.Required(path, "at least one ConfigMap or Secret must be referenced"))
+	}
+	return errs
+}
+
+func validateBackupSchedule(cr *jenkinsv1alpha1.Jenkins, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	backup := cr.Spec.Backup
+	if backup == nil {
+		return errs
+	}
+	if !cr.Spec.Persistence.Enabled {
+		errs = append(errs, field.Forbidden(path, "requires spec.persistence.enabled"))
+	}
+	if fields := strings.Fields(backup.Schedule); len(fields) == 0 {
+		errs = append(errs, field.Required(path.Child("schedule"), ""))
+	} else if !strings.HasPrefix(fields[0], "@") && len(fields) != 5 {
+		errs = append(errs, field.Invalid(path.Child("schedule"), backup.Schedule, "must be a cron expression of 5 fields"))
+	}
+	claimPath := path.Child("destination", "persistentVolumeClaim")
+	claim := backup.Destination.PersistentVolumeClaim
+	if len(claim) == 0 {
+		errs = append(errs, field.Required(claimPath, ""))
+	} else if claim == JenkinsClaimName(cr) {
+		errs = append(errs, field.Invalid(claimPath, claim, "must not be the claim of JENKINS_HOME"))
+	} else {
+		for _, msg := range validation.IsDNS1123Subdomain(claim) {
+			errs = append(errs, field.Invalid(claimPath, claim, msg))
+		}
+	}
+	if backup.Retention.MaxCount != nil && *backup.Retention.MaxCount < 1 {
+		errs = append(errs, field.Invalid(path.Child("retention", "maxCount"), *backup.Retention.MaxCount, "must be greater than or equal to 1"))
+	}
+	if backup.Retention.MaxAge != nil && backup.Retention.MaxAge.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("retention", "maxAge"), backup.Retention.MaxAge.Duration.String(), "must be greater than 0"))
+	}
+	return errs
+}
+
+func validateObjectStorage(cr *jenkinsv1alpha1.Jenkins, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	storage := cr.Spec.ObjectStorage
+	if storage == nil {
+		return errs
+	}
+	if !cr.Spec.Persistence.Enabled {
+		errs = append(errs, field.Forbidden(path, "requires spec.persistence.enabled"))
+	}
+	if len(storage.Endpoint) == 0 {
+		errs = append(errs, field.Required(path.Child("endpoint"), ""))
+	} else if u, err := url.Parse(storage.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		errs = append(errs, field.Invalid(path.Child("endpoint"), storage.Endpoint, "must be an http or https URL"))
+	}
+	if len(storage.Bucket) == 0 {
+		errs = append(errs, field.Required(path.Child("bucket"), ""))
+	} else if len(storage.Bucket) < 3 || len(storage.Bucket) > 63 {
+		errs = append(errs, field.Invalid(path.Child("bucket"), storage.Bucket, "must be between 3 and 63 characters"))
+	}
+	if strings.HasPrefix(storage.Prefix, "/") {
+		errs = append(errs, field.Invalid(path.Child("prefix"), storage.Prefix, "must not start with /"))
+	}
+	if len(storage.CredentialsSecret) == 0 {
+		errs = append(errs, field.Required(path.Child("credentialsSecret"), ""))
+	} else {
+		for _, msg := range validation.IsDNS1123Subdomain(storage.CredentialsSecret) {
+			errs = append(errs, field.Invalid(path.Child("credentialsSecret"), storage.CredentialsSecret, msg))
+		}
+	}
+	return errs
+}
+
+func validateIngress(ingress *jenkinsv1alpha1.JenkinsIngress, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if ingress == nil {
+		return errs
+	}
+	if len(ingress.Host) > 0 {
+		for _, msg := range validation.IsDNS1123Subdomain(ingress.Host) {
+			errs = append(errs, field.Invalid(path.Child("host"), ingress.Host, msg))
+		}
+	}
+	if len(ingress.TLSSecretName) > 0 {
+		for _, msg := range validation.IsDNS1123Subdomain(ingress.TLSSecretName) {
+			errs = append(errs, field.Invalid(path.Child("tlsSecretName"), ingress.TLSSecretName, msg))
+		}
+	}
+	for key := range ingress.Annotations {
+		for _, msg := range validation.IsQualifiedName(strings.ToLower(key)) {
+			errs = append(errs, field.Invalid(path.Child("annotations"), key, msg))
+		}
+		if key == IngressClassAnnotation && len(ingress.IngressClassName) > 0 {
+			errs = append(errs, field.Invalid(path.Child("annotations").Key(key), ingress.Annotations[key], "cannot be used with ingressClassName"))
+		}
+	}
+	return errs
+}
+
+func validateRoute(route *jenkinsv1alpha1.JenkinsRoute, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if route == nil {
+		return errs
+	}
+	if len(route.Host) > 0 {
+		for _, msg := range validation.IsDNS1123Subdomain(route.Host) {
+			errs = append(errs, field.Invalid(path.Child("host"), route.Host, msg))
+		}
+	}
+	switch route.Termination {
+	case "", jenkinsv1alpha1.JenkinsRouteTerminationEdge, jenkinsv1alpha1.JenkinsRouteTerminationReencrypt:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("termination"), route.Termination,
+			[]string{string(jenkinsv1alpha1.JenkinsRouteTerminationEdge), string(jenkinsv1alpha1.JenkinsRouteTerminationReencrypt)}))
+	}
+	if len(route.CertificateSecret) > 0 {
+		for _, msg := range validation.IsDNS1123Subdomain(route.CertificateSecret) {
+			errs = append(errs, field.Invalid(path.Child("certificateSecret"), route.CertificateSecret, msg))
+		}
+	}
+	for key := range route.Annotations {
+		for _, msg := range validation.IsQualifiedName(strings.ToLower(key)) {
+			errs = append(errs, field.Invalid(path.Child("annotations"), key, msg))
+		}
+	}
+	for key, value := range route.Labels {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, field.Invalid(path.Child("labels"), key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(value) {
+			errs = append(errs, field.Invalid(path.Child("labels").Key(key), value, msg))
+		}
+	}
+	return errs
+}
+
+func validateServiceAccount(cr *jenkinsv1alpha1.Jenkins, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	serviceAccount := cr.Spec.ServiceAccount
+	if serviceAccount == nil {
+		return errs
+	}
+	if role := serviceAccount.Role; role != nil {
+		if role.Kind != "ClusterRole" && role.Kind != "Role" {
+			errs = append(errs, field.NotSupported(path.Child("role", "kind"), role.Kind, []string{"ClusterRole", "Role"}))
+		}
+		if len(role.Name) == 0 {
+			errs = append(errs, field.Required(path.Child("role", "name"), ""))
+		}
+	}
+	namespaces := map[string]bool{}
+	for i, namespace := range serviceAccount.Namespaces {
+		namespacePath := path.Child("namespaces").Index(i)
+		for _, msg := range validation.IsDNS1123Label(namespace) {
+			errs = append(errs, field.Invalid(namespacePath, namespace, msg))
+		}
+		if namespace == cr.Namespace {
+			errs = append(errs, field.Invalid(namespacePath, namespace, "the role is always bound in the namespace of the instance"))
+		}
+		if namespaces[namespace] {
+			errs = append(errs, field.Duplicate(namespacePath, namespace))
+		}
+		namespaces[namespace] = true
+	}
+	if serviceAccount.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(serviceAccount.NamespaceSelector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("namespaceSelector"), serviceAccount.NamespaceSelector, err.Error()))
+		}
+	}
+	for key := range serviceAccount.Annotations {
+		for _, msg := range validation.IsQualifiedName(strings.ToLower(key)) {
+			errs = append(errs, field.Invalid(path.Child("annotations"), key, msg))
+		}
+	}
+	return errs
+}
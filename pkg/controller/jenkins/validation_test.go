@@ -0,0 +1,250 @@
+package jenkins
+
+import (
+	"strings"
+	"testing"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateJenkins(t *testing.T) {
+	t.Run("TestValidSpec", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Image = &jenkinsv1alpha1.JenkinsImageSource{ImageStreamTag: "jenkins:2", ImageStreamNamespace: "openshift"}
+		cr.Spec.Env = []corev1.EnvVar{{Name: "EXTRA", Value: "1"}}
+		cr.Spec.Volumes = []corev1.Volume{{Name: "cache"}}
+		cr.Spec.VolumeMounts = []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}}
+		require.Empty(t, ValidateJenkins(cr))
+	})
+
+	t.Run("TestInvalidSpec", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Image = &jenkinsv1alpha1.JenkinsImageSource{Reference: "jenkins", ImageStreamTag: "jenkins"}
+		cr.Spec.Resources = &corev1.ResourceRequirements{
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+		}
+		cr.Spec.ReadinessProbe = &jenkinsv1alpha1.JenkinsProbe{TimeoutSeconds: int32Ptr(0)}
+		cr.Spec.Env = []corev1.EnvVar{{Name: JenkinsJavaOptsEnv, Value: "-Xmx1g"}, {Name: "1NVALID"}}
+		cr.Spec.Volumes = []corev1.Volume{{Name: JenkinsVolumeName}}
+		cr.Spec.VolumeMounts = []corev1.VolumeMount{{Name: "missing", MountPath: JenkinsVolumeMountPath}}
+
+		fields := []string{}
+		for _, err := range ValidateJenkins(cr) {
+			fields = append(fields, err.Field)
+		}
+		require.Equal(t, []string{
+			"spec.image",
+			"spec.image.imageStreamTag",
+			"spec.resources.requests[memory]",
+			"spec.readinessProbe.timeoutSeconds",
+			"spec.env[0].name",
+			"spec.env[1].name",
+			"spec.volumes[0].name",
+			"spec.volumeMounts[0].name",
+			"spec.volumeMounts[0].mountPath",
+		}, fields)
+	})
+
+	t.Run("TestInvalidConfigurationAsCode", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Env = []corev1.EnvVar{{Name: JenkinsCascConfigEnv, Value: "/casc"}}
+		cr.Spec.Volumes = []corev1.Volume{{Name: JenkinsCascVolumePrefix + "configmap-0"}}
+		cr.Spec.ConfigurationAsCode = &jenkinsv1alpha1.JenkinsConfigurationAsCode{
+			ConfigMaps: []corev1.LocalObjectReference{{Name: "casc"}, {Name: "casc"}},
+			Secrets:    []corev1.LocalObjectReference{{Name: "Invalid_Name"}},
+		}
+
+		fields := []string{}
+		for _, err := range ValidateJenkins(cr) {
+			fields = append(fields, err.Field)
+		}
+		require.Equal(t, []string{
+			"spec.env[0].name",
+			"spec.volumes[0].name",
+			"spec.configurationAsCode.configMaps[1].name",
+			"spec.configurationAsCode.secrets[0].name",
+		}, fields)
+
+		cr.Spec.ConfigurationAsCode = &jenkinsv1alpha1.JenkinsConfigurationAsCode{}
+		require.Len(t, validateConfigurationAsCode(cr.Spec.ConfigurationAsCode, field.NewPath("spec")), 1)
+	})
+
+	t.Run("TestInvalidBackupSchedule", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Backup = &jenkinsv1alpha1.JenkinsBackupSchedule{
+			Schedule:    "0 2 * *",
+			Destination: jenkinsv1alpha1.JenkinsBackupDestination{PersistentVolumeClaim: test_name},
+			Retention:   jenkinsv1alpha1.JenkinsBackupRetention{MaxCount: int32Ptr(0)},
+		}
+
+		fields := []string{}
+		for _, err := range ValidateJenkins(cr) {
+			fields = append(fields, err.Field)
+		}
+		require.Equal(t, []string{
+			"spec.backup",
+			"spec.backup.schedule",
+			"spec.backup.destination.persistentVolumeClaim",
+			"spec.backup.retention.maxCount",
+		}, fields)
+
+		cr.Spec.Persistence.Enabled = true
+		cr.Spec.Backup.Schedule = "@daily"
+		cr.Spec.Backup.Destination.PersistentVolumeClaim = "jenkins-backups"
+		cr.Spec.Backup.Retention.MaxCount = int32Ptr(14)
+		require.Empty(t, ValidateJenkins(cr))
+	})
+	t.Run("TestInvalidObjectStorage", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.ObjectStorage = &jenkinsv1alpha1.JenkinsObjectStorage{
+			Endpoint:          "minio:9000",
+			Bucket:            "b",
+			Prefix:            "/backups/",
+			CredentialsSecret: "S3_Credentials",
+		}
+
+		fields := []string{}
+		for _, err := range ValidateJenkins(cr) {
+			fields = append(fields, err.Field)
+		}
+		require.Equal(t, []string{
+			"spec.objectStorage",
+			"spec.objectStorage.endpoint",
+			"spec.objectStorage.bucket",
+			"spec.objectStorage.prefix",
+			"spec.objectStorage.credentialsSecret",
+		}, fields)
+
+		cr.Spec.Persistence.Enabled = true
+		cr.Spec.ObjectStorage.Endpoint = "http://minio:9000"
+		cr.Spec.ObjectStorage.Bucket = "jenkins-backups"
+		cr.Spec.ObjectStorage.Prefix = "backups/"
+		cr.Spec.ObjectStorage.CredentialsSecret = "s3-credentials"
+		require.Empty(t, ValidateJenkins(cr))
+	})
+	t.Run("TestInvalidIngress", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Ingress = &jenkinsv1alpha1.JenkinsIngress{
+			Host:             "Jenkins_Example",
+			IngressClassName: "nginx",
+			Annotations:      map[string]string{IngressClassAnnotation: "traefik"},
+		}
+
+		fields := []string{}
+		for _, err := range ValidateJenkins(cr) {
+			fields = append(fields, err.Field)
+		}
+		require.Equal(t, []string{"spec.ingress.host", "spec.ingress.annotations[kubernetes.io/ingress.class]"}, fields)
+	})
+	t.Run("TestInvalidRoute", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Route = &jenkinsv1alpha1.JenkinsRoute{
+			Termination: "passthrough",
+			Labels:      map[string]string{"router": "not a label value"},
+		}
+
+		fields := []string{}
+		for _, err := range ValidateJenkins(cr) {
+			fields = append(fields, err.Field)
+		}
+		require.Equal(t, []string{"spec.route.termination", "spec.route.labels[router]"}, fields)
+
+		cr.Spec.Route.Termination = jenkinsv1alpha1.JenkinsRouteTerminationReencrypt
+		cr.Spec.Route.Labels["router"] = "internal"
+		require.Empty(t, ValidateJenkins(cr))
+	})
+	t.Run("TestInvalidAuthentication", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Authentication = "ldap"
+		cr.Spec.Env = []corev1.EnvVar{{Name: JenkinsAdminPasswordEnv, Value: "admin"}}
+		cr.Spec.Volumes = []corev1.Volume{{Name: BuiltinAuthVolumeName}}
+
+		fields := []string{}
+		for _, err := range ValidateJenkins(cr) {
+			fields = append(fields, err.Field)
+		}
+		require.Equal(t, []string{"spec.env[0].name", "spec.volumes[0].name", "spec.authentication"}, fields)
+	})
+	t.Run("TestInvalidPersistence", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		blockMode := corev1.PersistentVolumeBlock
+		cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{
+			Enabled:       true,
+			Size:          "ten",
+			AccessModes:   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce, "ReadWriteSometimes"},
+			VolumeMode:    &blockMode,
+			ExistingClaim: "shared-home",
+		}
+
+		fields := []string{}
+		for _, err := range ValidateJenkins(cr) {
+			fields = append(fields, err.Field)
+		}
+		require.Equal(t, []string{
+			"spec.persistence.size",
+			"spec.persistence.accessModes[1]",
+			"spec.persistence.volumeMode",
+			"spec.persistence.existingClaim",
+		}, fields)
+	})
+	t.Run("TestNonPositivePersistenceSize", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		for _, size := range []string{"0", "0Gi", "-1Gi"} {
+			cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, Size: size}
+			errs := ValidateJenkins(cr)
+			require.Len(t, errs, 1, size)
+			require.Equal(t, field.ErrorTypeInvalid, errs[0].Type)
+			require.Equal(t, "spec.persistence.size", errs[0].Field)
+			require.Equal(t, "must be greater than zero", errs[0].Detail)
+		}
+		cr.Spec.Persistence.Size = "1Mi"
+		require.Empty(t, ValidateJenkins(cr))
+	})
+	t.Run("TestInvalidServiceAccount", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.ServiceAccount = &jenkinsv1alpha1.JenkinsServiceAccount{
+			Role:       &jenkinsv1alpha1.JenkinsRoleRef{Kind: "Group"},
+			Namespaces: []string{"staging", test_ns, "staging"},
+			NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "team", Operator: "Matches"},
+			}},
+		}
+
+		fields := []string{}
+		for _, err := range ValidateJenkins(cr) {
+			fields = append(fields, err.Field)
+		}
+		require.Equal(t, []string{
+			"spec.serviceAccount.role.kind",
+			"spec.serviceAccount.role.name",
+			"spec.serviceAccount.namespaces[1]",
+			"spec.serviceAccount.namespaces[2]",
+			"spec.serviceAccount.namespaceSelector",
+		}, fields)
+	})
+	t.Run("TestInvalidDerivedNames", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, strings.Repeat("j", 60))
+		cr.Spec.DeletionPolicy = "Archive"
+		cr.Spec.Persistence.Enabled = true
+		cr.Spec.Backup = &jenkinsv1alpha1.JenkinsBackupSchedule{
+			Schedule:    "@daily",
+			Destination: jenkinsv1alpha1.JenkinsBackupDestination{PersistentVolumeClaim: "backups"},
+		}
+
+		errs := ValidateJenkins(cr)
+		fields := []string{}
+		for _, err := range errs {
+			fields = append(fields, err.Field)
+		}
+		require.Equal(t, []string{"metadata.name", "metadata.name", "spec.deletionPolicy"}, fields)
+		require.Contains(t, errs[0].Detail, "the Service "+cr.Name+JenkinsJnlpServiceSuffix)
+		require.Contains(t, errs[1].Detail, "must be no more than 35 characters to name the CronJob")
+	})
+}
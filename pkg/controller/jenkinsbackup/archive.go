@@ -0,0 +1,11 @@
+package jenkinsbackup
+
+import (
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+)
+
+// ArchivePath returns the path of the archive of the backup, relative to its destination
+func ArchivePath(backup *jenkinsv1alpha1.JenkinsBackup) string {
+	return backup.Spec.JenkinsRef + "/" + backup.Name + jenkins.ArchiveExtension
+}
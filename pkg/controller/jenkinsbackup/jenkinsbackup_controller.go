@@ -0,0 +1,173 @@
+package jenkinsbackup
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("jenkinsbackup_controller")
+
+// Add creates a new JenkinsBackup Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileJenkinsBackup{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("jenkinsbackup-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &jenkinsv1alpha1.JenkinsBackup{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	// Watch the jobs creating the archives
+	return c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &jenkinsv1alpha1.JenkinsBackup{},
+	})
+}
+
+// blank assignment to verify that ReconcileJenkinsBackup implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileJenkinsBackup{}
+
+// ReconcileJenkinsBackup reconciles a JenkinsBackup object
+type ReconcileJenkinsBackup struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// Reconcile runs the job archiving JENKINS_HOME of the instance referenced by the JenkinsBackup and reports
+// the archive in its status. A backup is never run again once it completed or failed.
+func (r *ReconcileJenkinsBackup) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	logger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	logger.Info("Reconciling JenkinsBackup")
+
+	instance := &jenkinsv1alpha1.JenkinsBackup{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if instance.Status.Phase.IsFinished() {
+		return reconcile.Result{}, nil
+	}
+
+	status := instance.Status.DeepCopy()
+	err = r.runBackup(instance, status)
+	if err != nil {
+		logger.Error(err, "Error while running the backup")
+	}
+	if !reflect.DeepEqual(instance.Status, *status) {
+		instance.Status = *status
+		if updateErr := r.client.Status().Update(context.TODO(), instance); updateErr != nil && err == nil {
+			err = updateErr
+		}
+	}
+	return reconcile.Result{}, err
+}
+
+// runBackup creates the backup job and fills status with its progress
+func (r *ReconcileJenkinsBackup) runBackup(backup *jenkinsv1alpha1.JenkinsBackup, status *jenkinsv1alpha1.JenkinsBackupStatus) error {
+	if len(status.Phase) == 0 {
+		status.Phase = jenkinsv1alpha1.JenkinsBackupPhasePending
+	}
+	cr := &jenkinsv1alpha1.Jenkins{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: backup.Spec.JenkinsRef}, cr)
+	if errors.IsNotFound(err) {
+		setFailed(status, fmt.Sprintf("Jenkins instance %s not found", backup.Spec.JenkinsRef))
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !cr.Spec.Persistence.Enabled {
+		setFailed(status, fmt.Sprintf("Jenkins instance %s is not persistent, JENKINS_HOME cannot be archived", cr.Name))
+		return nil
+	}
+	claimName := backup.Spec.Destination.PersistentVolumeClaim
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: claimName}, &corev1.PersistentVolumeClaim{})
+	if errors.IsNotFound(err) {
+		setFailed(status, fmt.Sprintf("PersistentVolumeClaim %s not found", claimName))
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	job := &batchv1.Job{}
+	desired := newBackupJob(backup, cr, cr.Status.Phase == jenkinsv1alpha1.JenkinsPhaseReady)
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, job)
+	if errors.IsNotFound(err) {
+		if err := controllerutil.SetControllerReference(backup, desired, r.scheme); err != nil {
+			return err
+		}
+		log.Info("Creating a new Job", "Job.Namespace", desired.Namespace, "Job.Name", desired.Name)
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			return err
+		}
+		job = desired
+	} else if err != nil {
+		return err
+	}
+	if status.StartTime == nil {
+		now := metav1.Now()
+		status.StartTime = &now
+	}
+	status.Phase = jenkinsv1alpha1.JenkinsBackupPhaseRunning
+	status.Message = fmt.Sprintf("Archiving JENKINS_HOME of %s into PersistentVolumeClaim %s", cr.Name, claimName)
+
+	finished, failure := jenkins.JobCompletion(job)
+	if !finished {
+		return nil
+	}
+	if len(failure) > 0 {
+		setFailed(status, jenkins.JobFailureMessage(r.client, job, failure))
+		return nil
+	}
+	result, err := jenkins.ReadArchiveResult(r.client, job)
+	if err != nil {
+		return err
+	}
+	now := metav1.Now()
+	status.Phase = jenkinsv1alpha1.JenkinsBackupPhaseCompleted
+	status.Message = fmt.Sprintf("JENKINS_HOME of %s archived into PersistentVolumeClaim %s", cr.Name, claimName)
+	status.Archive = result.Archive
+	status.Size = result.Size
+	status.Checksum = result.Checksum
+	status.CompletionTime = &now
+	return nil
+}
+
+func setFailed(status *jenkinsv1alpha1.JenkinsBackupStatus, message string) {
+	now := metav1.Now()
+	status.Phase = jenkinsv1alpha1.JenkinsBackupPhaseFailed
+	status.Message = message
+	status.CompletionTime = &now
+}
@@ -0,0 +1,137 @@
+package jenkinsbackup
+
+import (
+	"context"
+	"testing"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	test_ns      = "test"
+	test_name    = "test-jenkinsbackup"
+	test_jenkins = "test-jenkins"
+	test_claim   = "jenkins-backups"
+)
+
+func newTestBackup() *jenkinsv1alpha1.JenkinsBackup {
+	return &jenkinsv1alpha1.JenkinsBackup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name},
+		Spec: jenkinsv1alpha1.JenkinsBackupSpec{
+			JenkinsRef:  test_jenkins,
+			Destination: jenkinsv1alpha1.JenkinsBackupDestination{PersistentVolumeClaim: test_claim},
+			Excludes:    []string{"workspace", "jobs/*/builds/*/archive"},
+		},
+	}
+}
+
+func newTestReconciler(objs ...runtime.Object) (*ReconcileJenkinsBackup, *mocks.FakeClient) {
+	s := mocks.NewScheme()
+	c := mocks.NewFakeClient(s, objs...)
+	return &ReconcileJenkinsBackup{client: c, scheme: s}, c
+}
+
+func testRequest() reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: test_ns, Name: test_name}}
+}
+
+// finishJob sets the status of the job and creates its pod terminated with the given exit code and message
+func finishJob(t *testing.T, c *mocks.FakeClient, name string, exitCode int32, message string) {
+	job := &batchv1.Job{}
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: name}, job))
+	if exitCode == 0 {
+		job.Status.Succeeded = 1
+	} else {
+		job.Status.Failed = 1
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"},
+		}
+	}
+	require.NoError(t, c.Status().Update(context.TODO(), job))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: name + "-pod", Labels: map[string]string{jenkins.JobNameLabel: name}},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message}}},
+			},
+		},
+	}
+	require.NoError(t, c.Create(context.TODO(), pod))
+}
+
+func TestReconcileBackup(t *testing.T) {
+	t.Run("TestBackupCompletes", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_jenkins)
+		cr.Spec.Persistence.Enabled = true
+		cr.Status.Phase = jenkinsv1alpha1.JenkinsPhaseReady
+		claim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_claim}}
+		r, c := newTestReconciler(cr, claim, newTestBackup())
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		job := &batchv1.Job{}
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: test_name + BackupJobSuffix}, job))
+		require.Equal(t, test_name, metav1.GetControllerOf(job).Name)
+		podSpec := job.Spec.Template.Spec
+		require.Equal(t, test_jenkins, podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		require.True(t, podSpec.Volumes[0].PersistentVolumeClaim.ReadOnly)
+		require.Equal(t, test_claim, podSpec.Volumes[1].PersistentVolumeClaim.ClaimName)
+		require.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: jenkins.BackupArchiveEnv, Value: test_jenkins + "/" + test_name + jenkins.ArchiveExtension})
+		require.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: jenkins.BackupExcludesEnv, Value: "workspace\njobs/*/builds/*/archive"})
+		require.NotNil(t, podSpec.Affinity.PodAffinity)
+
+		backup := &jenkinsv1alpha1.JenkinsBackup{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, backup))
+		require.Equal(t, jenkinsv1alpha1.JenkinsBackupPhaseRunning, backup.Status.Phase)
+		require.NotNil(t, backup.Status.StartTime)
+
+		finishJob(t, c, job.Name, 0, `{"archive":"test-jenkins/test-jenkinsbackup.tar.gz","size":2048,"checksum":"sha256:abcd"}`)
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, backup))
+		require.Equal(t, jenkinsv1alpha1.JenkinsBackupPhaseCompleted, backup.Status.Phase)
+		require.Equal(t, "test-jenkins/test-jenkinsbackup.tar.gz", backup.Status.Archive)
+		require.Equal(t, int64(2048), backup.Status.Size)
+		require.Equal(t, "sha256:abcd", backup.Status.Checksum)
+		require.NotNil(t, backup.Status.CompletionTime)
+	})
+
+	t.Run("TestBackupFails", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_jenkins)
+		cr.Spec.Persistence.Enabled = true
+		claim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_claim}}
+		r, c := newTestReconciler(cr, claim, newTestBackup())
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		finishJob(t, c, test_name+BackupJobSuffix, 1, "tar: write error: No space left on device")
+		_, err = r.Reconcile(testRequest())
+		require.NoError(t, err)
+		backup := &jenkinsv1alpha1.JenkinsBackup{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, backup))
+		require.Equal(t, jenkinsv1alpha1.JenkinsBackupPhaseFailed, backup.Status.Phase)
+		require.Contains(t, backup.Status.Message, "No space left on device")
+	})
+
+	t.Run("TestEphemeralJenkinsIsRejected", func(t *testing.T) {
+		r, c := newTestReconciler(mocks.JenkinsCRMock(test_ns, test_jenkins), newTestBackup())
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		backup := &jenkinsv1alpha1.JenkinsBackup{}
+		require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, backup))
+		require.Equal(t, jenkinsv1alpha1.JenkinsBackupPhaseFailed, backup.Status.Phase)
+		err = c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: test_name + BackupJobSuffix}, &batchv1.Job{})
+		require.True(t, kubeerrors.IsNotFound(err))
+	})
+}
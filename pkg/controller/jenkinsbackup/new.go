@@ -0,0 +1,68 @@
+package jenkinsbackup
+
+import (
+	"strings"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupJobSuffix is appended to the name of a backup to name its job
+const BackupJobSuffix = "-backup"
+
+// newBackupJob returns the job archiving JENKINS_HOME of the Jenkins instance into the destination of the
+// backup. When Jenkins is running the job is scheduled on the same node, so that the ReadWriteOnce volume of
+// JENKINS_HOME can be mounted by both pods.
+func newBackupJob(backup *jenkinsv1alpha1.JenkinsBackup, cr *jenkinsv1alpha1.Jenkins, jenkinsRunning bool) *batchv1.Job {
+	backoffLimit := int32(2)
+	includes := jenkins.DefaultBackupIncludes
+	if len(backup.Spec.Includes) > 0 {
+		includes = strings.Join(backup.Spec.Includes, "\n")
+	}
+	podSpec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers: []corev1.Container{
+			{
+				Name:    jenkins.BackupContainerName,
+				Image:   jenkins.JenkinsImage,
+				Command: []string{"/bin/bash", "-c", jenkins.BackupScript},
+				Env: []corev1.EnvVar{
+					{Name: jenkins.BackupArchiveEnv, Value: ArchivePath(backup)},
+					{Name: jenkins.BackupIncludesEnv, Value: includes},
+					{Name: jenkins.BackupExcludesEnv, Value: strings.Join(backup.Spec.Excludes, "\n")},
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: jenkins.JenkinsVolumeName, MountPath: jenkins.JenkinsVolumeMountPath, ReadOnly: true},
+					{Name: jenkins.BackupVolumeName, MountPath: jenkins.BackupMountPath},
+				},
+			},
+		},
+		Volumes: []corev1.Volume{
+			jenkins.NewClaimVolume(jenkins.JenkinsVolumeName, jenkins.JenkinsClaimName(cr), true),
+			jenkins.NewClaimVolume(jenkins.BackupVolumeName, backup.Spec.Destination.PersistentVolumeClaim, false),
+		},
+	}
+	if jenkinsRunning {
+		podSpec.Affinity = &corev1.Affinity{
+			PodAffinity: &corev1.PodAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{jenkins.NewJenkinsPodAffinityTerm(cr)},
+			},
+		}
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backup.Name + BackupJobSuffix,
+			Namespace: backup.Namespace,
+			Labels: map[string]string{
+				jenkins.JenkinsAppLabel: cr.Name,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template:     corev1.PodTemplateSpec{Spec: podSpec},
+		},
+	}
+}
@@ -0,0 +1,80 @@
+package jenkinsimage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"time"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/rest"
+)
+
+// BinaryBuildInstantiator starts builds of binary BuildConfigs
+type BinaryBuildInstantiator interface {
+	// InstantiateBinary starts a build of the BuildConfig using the archive as binary input and returns the build
+	InstantiateBinary(namespace, name string, archive io.Reader) (*buildv1.Build, error)
+}
+
+// binaryBuildClient starts builds through the buildconfigs/instantiatebinary subresource of the Build API
+type binaryBuildClient struct {
+	client rest.Interface
+}
+
+// NewBinaryBuildInstantiator returns a BinaryBuildInstantiator talking to the API server defined in config
+func NewBinaryBuildInstantiator(config *rest.Config, scheme *runtime.Scheme) (BinaryBuildInstantiator, error) {
+	buildConfig := rest.CopyConfig(config)
+	buildConfig.GroupVersion = &buildv1.SchemeGroupVersion
+	buildConfig.APIPath = "/apis"
+	buildConfig.ContentType = runtime.ContentTypeJSON
+	buildConfig.NegotiatedSerializer = serializer.DirectCodecFactory{CodecFactory: serializer.NewCodecFactory(scheme)}
+	if len(buildConfig.UserAgent) == 0 {
+		buildConfig.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+	client, err := rest.RESTClientFor(buildConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &binaryBuildClient{client: client}, nil
+}
+
+func (c *binaryBuildClient) InstantiateBinary(namespace, name string, archive io.Reader) (*buildv1.Build, error) {
+	build := &buildv1.Build{}
+	err := c.client.Post().
+		Namespace(namespace).
+		Resource("buildconfigs").
+		Name(name).
+		SubResource("instantiatebinary").
+		SetHeader("Content-Type", "application/octet-stream").
+		Body(archive).
+		Do().
+		Into(build)
+	return build, err
+}
+
+// newBinaryBuildArchive returns a gzipped tar archive holding the given files, as uploaded by oc start-build --from-dir
+func newBinaryBuildArchive(files map[string]string) (*bytes.Buffer, error) {
+	archive := &bytes.Buffer{}
+	gz := gzip.NewWriter(archive)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for name, content := range files {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), ModTime: now, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
@@ -0,0 +1,206 @@
+package jenkinsimage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	cu "github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/controllerutil"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// newFakeBuildServer returns a server implementing the instantiatebinary subresource. The builds it starts
+// are created in c, and the files of the uploaded archives are sent to uploads.
+func newFakeBuildServer(t *testing.T, c *mocks.FakeClient, uploads chan map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/apis/build.openshift.io/v1/namespaces/"+test_ns+"/buildconfigs/"+test_name+"/instantiatebinary", req.URL.Path)
+
+		gz, err := gzip.NewReader(req.Body)
+		require.NoError(t, err)
+		files := map[string]string{}
+		tr := tar.NewReader(gz)
+		for header, err := tr.Next(); err == nil; header, err = tr.Next() {
+			content, err := ioutil.ReadAll(tr)
+			require.NoError(t, err)
+			files[header.Name] = string(content)
+		}
+		uploads <- files
+
+		build := &buildv1.Build{
+			TypeMeta: metav1.TypeMeta{APIVersion: buildv1.SchemeGroupVersion.String(), Kind: "Build"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: test_ns,
+				Name:      test_name + "-1",
+				Labels:    map[string]string{BuildConfigLabel: test_name},
+			},
+		}
+		require.NoError(t, c.Create(context.TODO(), build))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		require.NoError(t, json.NewEncoder(w).Encode(build))
+	}))
+}
+
+func TestInstantiateBinary(t *testing.T) {
+	t.Run("TestUploadsArchiveAndReturnsBuild", func(t *testing.T) {
+		s := mocks.NewScheme()
+		c := mocks.NewFakeClient(s)
+		uploads := make(chan map[string]string, 1)
+		server := newFakeBuildServer(t, c, uploads)
+		defer server.Close()
+
+		builds, err := NewBinaryBuildInstantiator(&rest.Config{Host: server.URL}, s)
+		require.NoError(t, err)
+		archive, err := newBinaryBuildArchive(map[string]string{PluginsListFilename: "git\n"})
+		require.NoError(t, err)
+		build, err := builds.InstantiateBinary(test_ns, test_name, archive)
+		require.NoError(t, err)
+		require.Equal(t, test_name+"-1", build.Name)
+		require.Equal(t, map[string]string{PluginsListFilename: "git\n"}, <-uploads)
+	})
+
+	t.Run("TestReturnsServerErrors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403}`))
+		}))
+		defer server.Close()
+
+		builds, err := NewBinaryBuildInstantiator(&rest.Config{Host: server.URL}, mocks.NewScheme())
+		require.NoError(t, err)
+		archive, err := newBinaryBuildArchive(map[string]string{})
+		require.NoError(t, err)
+		_, err = builds.InstantiateBinary(test_ns, test_name, archive)
+		require.Error(t, err)
+	})
+}
+
+func TestReconcileStartsBuild(t *testing.T) {
+	t.Run("TestBuildIsStartedOncePerPluginsHash", func(t *testing.T) {
+		instance := &jenkinsv1alpha1.JenkinsImage{
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name},
+			Spec:       jenkinsv1alpha1.JenkinsImageSpec{Plugins: []jenkinsv1alpha1.JenkinsPlugin{{Name: "blueocean", Version: "1.3.0"}}},
+		}
+		s := mocks.NewScheme()
+		c := mocks.NewFakeClient(s, instance)
+		uploads := make(chan map[string]string, 2)
+		server := newFakeBuildServer(t, c, uploads)
+		defer server.Close()
+		builds, err := NewBinaryBuildInstantiator(&rest.Config{Host: server.URL}, s)
+		require.NoError(t, err)
+		recorder := mocks.NewFakeRecorder()
+		r := &ReconcileJenkinsImage{client: c, apiReader: c, scheme: s, builds: builds, recorder: recorder}
+
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: test_ns, Name: test_name}}
+		// the first pass creates the ImageStream, the second one the BuildConfig and the build
+		for i := 0; i < 3; i++ {
+			_, err = r.Reconcile(request)
+			require.NoError(t, err)
+		}
+		require.Len(t, uploads, 1)
+		require.Equal(t, "blueocean:1.3.0\n", (<-uploads)[PluginsListFilename])
+
+		require.NoError(t, c.Get(context.TODO(), request.NamespacedName, instance))
+		require.Equal(t, pluginsHash(instance.Spec.Plugins), instance.Status.PluginsHash)
+		require.Equal(t, test_name+"-1", instance.Status.LatestBuild)
+		build := &buildv1.Build{}
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: test_name + "-1"}, build))
+		require.Equal(t, instance.Status.PluginsHash, build.Annotations[PluginsHashAnnotation])
+		require.Equal(t, instance.UID, build.OwnerReferences[0].UID)
+		require.Equal(t, jenkinsv1alpha1.JenkinsImagePhasePending, instance.Status.Phase)
+		require.Equal(t, []string{
+			"Normal " + cu.ReasonSuccessfulCreate,
+			"Normal " + cu.ReasonSuccessfulCreate,
+			"Normal " + ReasonBuildStarted,
+		}, recorder.Reasons())
+
+		// the status follows the build
+		build.Status.Phase = buildv1.BuildPhaseComplete
+		build.Status.OutputDockerImageReference = DefaultRegistryHostname + "/" + test_ns + "/" + test_name + ":latest"
+		build.Status.Output.To = &buildv1.BuildStatusOutputTo{ImageDigest: "sha256:1234"}
+		require.NoError(t, c.Status().Update(context.TODO(), build))
+		_, err = r.Reconcile(request)
+		require.NoError(t, err)
+		require.NoError(t, c.Get(context.TODO(), request.NamespacedName, instance))
+		require.Equal(t, jenkinsv1alpha1.JenkinsImagePhaseComplete, instance.Status.Phase)
+		require.Equal(t, DefaultRegistryHostname+"/"+test_ns+"/"+test_name+"@sha256:1234", instance.Status.Image)
+		require.Len(t, instance.Status.Builds, 1)
+		require.Equal(t, "Normal "+ReasonBuildSucceeded+" Build "+build.Name+" succeeded", recorder.Events()[3])
+
+		// the event is recorded once
+		_, err = r.Reconcile(request)
+		require.NoError(t, err)
+		require.Len(t, recorder.Events(), 4)
+	})
+
+	t.Run("TestBuildIsAnnotatedAfterConflict", func(t *testing.T) {
+		instance := &jenkinsv1alpha1.JenkinsImage{
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name},
+			Spec:       jenkinsv1alpha1.JenkinsImageSpec{Plugins: []jenkinsv1alpha1.JenkinsPlugin{{Name: "git"}}},
+		}
+		s := mocks.NewScheme()
+		c := mocks.NewFakeClient(s, instance)
+		uploads := make(chan map[string]string, 2)
+		server := newFakeBuildServer(t, c, uploads)
+		defer server.Close()
+		builds, err := NewBinaryBuildInstantiator(&rest.Config{Host: server.URL}, s)
+		require.NoError(t, err)
+		r := &ReconcileJenkinsImage{client: c, apiReader: c, scheme: s, builds: builds, recorder: mocks.NewFakeRecorder()}
+		// the build controller updates the build first
+		c.InjectFailureOnce(mocks.VerbUpdate, "Build", "", kubeerrors.NewConflict(
+			schema.GroupResource{Group: buildv1.GroupName, Resource: "builds"}, test_name+"-1", errors.New("the object has been modified")))
+
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: test_ns, Name: test_name}}
+		for i := 0; i < 4; i++ {
+			_, err = r.Reconcile(request)
+			require.NoError(t, err)
+		}
+		require.Len(t, uploads, 1)
+		build := &buildv1.Build{}
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: test_name + "-1"}, build))
+		require.Equal(t, pluginsHash(instance.Spec.Plugins), build.Annotations[PluginsHashAnnotation])
+		require.Len(t, build.OwnerReferences, 1)
+	})
+
+	t.Run("TestBuildStartFailureIsRecorded", func(t *testing.T) {
+		instance := &jenkinsv1alpha1.JenkinsImage{
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name},
+			Spec:       jenkinsv1alpha1.JenkinsImageSpec{Plugins: []jenkinsv1alpha1.JenkinsPlugin{{Name: "git"}}},
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403}`))
+		}))
+		defer server.Close()
+		s := mocks.NewScheme()
+		builds, err := NewBinaryBuildInstantiator(&rest.Config{Host: server.URL}, s)
+		require.NoError(t, err)
+		recorder := mocks.NewFakeRecorder()
+		r := &ReconcileJenkinsImage{client: mocks.NewFakeClient(s, instance), scheme: s, builds: builds, recorder: recorder}
+
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: test_ns, Name: test_name}}
+		_, err = r.Reconcile(request)
+		require.NoError(t, err)
+		_, err = r.Reconcile(request)
+		require.Error(t, err)
+		require.Equal(t, "Warning "+ReasonBuildStartFailed, recorder.Reasons()[2])
+	})
+}
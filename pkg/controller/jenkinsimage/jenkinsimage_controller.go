@@ -1,27 +1,28 @@
 package jenkinsimage
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	buildv1 "github.com/openshift/api/build/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
 	cu "github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/controllerutil"
-	"io/ioutil"
-	"os"
-	"os/exec"
-	"path/filepath"
 	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
@@ -36,37 +37,60 @@ const (
 	DefaultImageStreamTag   = "latest"
 	PluginsListFilename     = "plugins.txt"
 
-	OcCommand = "oc"
-	StartBuildArg = "start-build"
-	FromDirArg = "--from-dir"
+	// BuildConfigLabel is set by OpenShift on the builds of a BuildConfig
+	BuildConfigLabel = "openshift.io/build-config.name"
+
+	// PluginsHashAnnotation holds the hash of the plugins list a build was started for
+	PluginsHashAnnotation = "jenkins.dev/plugins-hash"
 )
 
+// JenkinsImageControllerName is the name of the controller, also used as the source of its events
+const JenkinsImageControllerName = "jenkinsimage-controller"
+
 var log = logf.Log.WithName("jenkinsimage_controller")
 
 // Add creates a new JenkinsImage Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	r, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return add(mgr, r)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileJenkinsImage{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	builds, err := NewBinaryBuildInstantiator(mgr.GetConfig(), mgr.GetScheme())
+	if err != nil {
+		return nil, err
+	}
+	apiReader, err := cu.NewAPIReader(mgr)
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileJenkinsImage{
+		client:    mgr.GetClient(),
+		apiReader: apiReader,
+		scheme:    mgr.GetScheme(),
+		builds:    builds,
+		recorder:  mgr.GetRecorder(JenkinsImageControllerName),
+	}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New("jenkinsimage-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(JenkinsImageControllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
 	// Create owner reference stating the owner of all the resources under the controller
 	ownerRef := &jenkinsv1alpha1.JenkinsImage{}
 	resourcesToWatch := []cu.NamedResource{
-		cu.NamedResource{ownerRef, ""},
-		cu.NamedResource{&imagev1.ImageStream{}, ""},
-		cu.NamedResource{&buildv1.BuildConfig{}, ""},
+		{Object: ownerRef},
+		{Object: &imagev1.ImageStream{}},
+		{Object: &buildv1.BuildConfig{}},
 	}
 	for _, resource := range resourcesToWatch {
 		ownerReference := ownerRef
@@ -75,7 +99,8 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 		cu.WatchResourceOrStackError(c, resource, ownerReference)
 	}
-	return nil
+	// Builds are owned by their BuildConfig, the JenkinsImage is added as a secondary owner when they are started
+	return c.Watch(&source.Kind{Type: &buildv1.Build{}}, &handler.EnqueueRequestForOwner{OwnerType: ownerRef})
 
 }
 
@@ -87,7 +112,12 @@ type ReconcileJenkinsImage struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
-	scheme *runtime.Scheme
+	// apiReader reads the builds from the apiserver, the cache may not hold the latest version of a build
+	// which was just started
+	apiReader client.Reader
+	scheme    *runtime.Scheme
+	builds    BinaryBuildInstantiator
+	recorder  record.EventRecorder
 }
 
 // The Controller will requeue the request to be processed again if the returned error is non-nil or
@@ -110,6 +140,13 @@ func (r *ReconcileJenkinsImage) Reconcile(request reconcile.Request) (reconcile.
 		return reconcile.Result{}, err
 	}
 
+	// Do not build anything while the plugins list cannot be rendered
+	if errs := ValidateJenkinsImage(instance); len(errs) > 0 {
+		logger.Error(errs.ToAggregate(), "Invalid JenkinsImage spec")
+		r.recorder.Event(instance, corev1.EventTypeWarning, ReasonInvalidSpec, errs.ToAggregate().Error())
+		return reconcile.Result{}, r.updateInvalidSpecStatus(instance, errs.ToAggregate())
+	}
+
 	// Define an image stream object
 	imagestream := newImageStream(instance)
 	// Set JenkinsImage instance as the owner and controller
@@ -122,6 +159,7 @@ func (r *ReconcileJenkinsImage) Reconcile(request reconcile.Request) (reconcile.
 	if err != nil && errors.IsNotFound(err) {
 		logger.Info("Creating a new ImageStream", "ImageStream.Namespace", imagestream.Namespace, "ImageStream.Name", imagestream.Name)
 		err = r.client.Create(context.TODO(), imagestream)
+		cu.RecordResourceEvent(r.recorder, instance, imagestream, cu.ActionCreate, err)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
@@ -144,58 +182,92 @@ func (r *ReconcileJenkinsImage) Reconcile(request reconcile.Request) (reconcile.
 	if err != nil && errors.IsNotFound(err) {
 		logger.Info("Creating a new BuildConfig", "BuildConfig.Namespace", buildConfig.Namespace, "BuildConfig.Name", buildConfig.Name)
 		err = r.client.Create(context.TODO(), buildConfig)
+		cu.RecordResourceEvent(r.recorder, instance, buildConfig, cu.ActionCreate, err)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
-		// BuildConfig created successfully - don't requeue and start the binary build from temp dir
-		startBinaryBuild(instance, buildConfig)
-		return reconcile.Result{}, nil
+		found = buildConfig
 	} else if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	// BuildConfig already exists - don't requeue
-	logger.Info("Skip reconcile: BuildConfig already exists", "BuildConfig.Namespace", found.Namespace, "BuildConfig.Name", found.Name)
-	return reconcile.Result{}, nil
+	// Start a new build whenever the rendered plugins list changed
+	hash := pluginsHash(instance.Spec.Plugins)
+	if instance.Status.PluginsHash != hash {
+		build, err := r.findBuildForHash(found, hash)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if build == nil {
+			logger.Info("Starting a new build", "BuildConfig.Name", found.Name, "PluginsHash", hash)
+			if build, err = r.startBinaryBuild(instance, found, hash); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		instance.Status.PluginsHash = hash
+		instance.Status.LatestBuild = build.Name
+	}
+	return reconcile.Result{}, r.updateStatus(instance, found)
 }
 
-func startBinaryBuild(instance *jenkinsv1alpha1.JenkinsImage, bc *buildv1.BuildConfig) {
-	name := bc.Name
-	logger := log.WithName("jenkinsimage_startbinarybuild")
-	tmpDir, err := ioutil.TempDir("","prefix")
-	if err != nil {
-		logger.Error(err, "Error")
-		fmt.Println(err)
+// findBuildForHash returns the build of the BuildConfig started for the given plugins hash, if any. Builds run
+// one after the other with the Serial run policy, this prevents queuing the same build twice.
+func (r *ReconcileJenkinsImage) findBuildForHash(bc *buildv1.BuildConfig, hash string) (*buildv1.Build, error) {
+	builds := &buildv1.BuildList{}
+	opts := client.InNamespace(bc.Namespace).MatchingLabels(map[string]string{BuildConfigLabel: bc.Name})
+	if err := r.client.List(context.TODO(), opts, builds); err != nil {
+		return nil, err
 	}
-	defer os.RemoveAll(tmpDir)
-
-	pluginsFile, err := os.Create(filepath.Join(tmpDir, filepath.Base(PluginsListFilename)))
-	if err != nil {
-		logger.Error(err, fmt.Sprint("Error while creating tmp file for binary build: ", pluginsFile , err))
-		fmt.Println(err)
-		pluginsFile.Close()
-		return
-	}
-	defer pluginsFile.Close()
-	for _, v := range instance.Spec.Plugins {
-		fmt.Fprintf(pluginsFile, "%s:%s", v.Name, v.Version)
-		logger.Info(fmt.Sprintf("Writing value %s into file %s ", pluginsFile.Name() , v.Name))
-		if err != nil {
-			logger.Error(err, fmt.Sprint("Error while writing plugins list into tmpFile: ", pluginsFile , err))
-			return
+	for i := range builds.Items {
+		if builds.Items[i].Annotations[PluginsHashAnnotation] == hash {
+			return &builds.Items[i], nil
 		}
 	}
-	err = pluginsFile.Close()
+	return nil, nil
+}
+
+// startBinaryBuild starts a build of the BuildConfig with the plugins list as binary input, then annotates
+// the build with the hash of the plugins list. The JenkinsImage is added to the owners of the build so that
+// the changes of the build are watched. The build controller updates the build as soon as it is started, the
+// annotation is retried on conflict: a build without it would be started again by the next reconcile.
+func (r *ReconcileJenkinsImage) startBinaryBuild(instance *jenkinsv1alpha1.JenkinsImage, bc *buildv1.BuildConfig, hash string) (*buildv1.Build, error) {
+	logger := log.WithName("jenkinsimage_startbinarybuild")
+	archive, err := newBinaryBuildArchive(map[string]string{
+		PluginsListFilename: renderPluginsList(instance.Spec.Plugins),
+	})
 	if err != nil {
-		logger.Error(err, fmt.Sprint("Error while closing plugins file: ", pluginsFile , err))
-		return
+		logger.Error(err, "Error while creating the binary build archive")
+		return nil, err
 	}
 
-	cmd := exec.Command(OcCommand, StartBuildArg, name, FromDirArg, tmpDir)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err = cmd.Run()
+	build, err := r.builds.InstantiateBinary(bc.Namespace, bc.Name, archive)
+	if err != nil {
+		logger.Error(err, fmt.Sprint("Error while instantiating binary build of BuildConfig ", bc.Name))
+		r.recorder.Eventf(instance, corev1.EventTypeWarning, ReasonBuildStartFailed, "Failed to start a build of BuildConfig %s: %v", bc.Name, err)
+		return nil, err
+	}
+	r.recorder.Eventf(instance, corev1.EventTypeNormal, ReasonBuildStarted, "Started build %s of BuildConfig %s", build.Name, bc.Name)
+	key := types.NamespacedName{Namespace: build.Namespace, Name: build.Name}
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.apiReader.Get(context.TODO(), key, build); err != nil {
+			return err
+		}
+		if build.Annotations == nil {
+			build.Annotations = map[string]string{}
+		}
+		build.Annotations[PluginsHashAnnotation] = hash
+		build.OwnerReferences = append(build.OwnerReferences, metav1.OwnerReference{
+			APIVersion: jenkinsv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "JenkinsImage",
+			Name:       instance.Name,
+			UID:        instance.UID,
+		})
+		return r.client.Update(context.TODO(), build)
+	})
 	if err != nil {
-		logger.Error(err, fmt.Sprint("oc start-build command failed with error: ", cmd))
+		logger.Error(err, "Error while annotating the build", "Build.Name", key.Name)
+		cu.RecordResourceEvent(r.recorder, instance, build, cu.ActionUpdate, err)
+		return nil, err
 	}
+	return build, nil
 }
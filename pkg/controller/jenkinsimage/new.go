@@ -59,7 +59,7 @@ func newBuildConfig(cr *jenkinsv1alpha1.JenkinsImage) *buildv1.BuildConfig {
 				Output: buildv1.BuildOutput{
 					To: &corev1.ObjectReference{
 						Kind: ImageStreamTagKind,
-						Name: cr.Name + ImageToTagSeparator + DefaultImageStreamTag,
+						Name: OutputImageStreamTag(cr.Name),
 					},
 				},
 			},
@@ -67,3 +67,8 @@ func newBuildConfig(cr *jenkinsv1alpha1.JenkinsImage) *buildv1.BuildConfig {
 	}
 	return bc
 }
+
+// OutputImageStreamTag returns the name of the ImageStreamTag receiving the images built for the JenkinsImage
+func OutputImageStreamTag(name string) string {
+	return name + ImageToTagSeparator + DefaultImageStreamTag
+}
@@ -0,0 +1,29 @@
+package jenkinsimage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+)
+
+// renderPluginsList returns the content of the plugins.txt file installed by the s2i build, one plugin per line
+func renderPluginsList(plugins []jenkinsv1alpha1.JenkinsPlugin) string {
+	var b strings.Builder
+	for _, plugin := range plugins {
+		if len(plugin.Version) > 0 {
+			fmt.Fprintf(&b, "%s:%s\n", plugin.Name, plugin.Version)
+		} else {
+			fmt.Fprintf(&b, "%s\n", plugin.Name)
+		}
+	}
+	return b.String()
+}
+
+// pluginsHash returns the hash of the rendered plugins.txt, used to detect when the image must be rebuilt
+func pluginsHash(plugins []jenkinsv1alpha1.JenkinsPlugin) string {
+	sum := sha256.Sum256([]byte(renderPluginsList(plugins)))
+	return hex.EncodeToString(sum[:])
+}
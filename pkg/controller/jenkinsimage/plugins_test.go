@@ -0,0 +1,58 @@
+package jenkinsimage
+
+import (
+	"testing"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	test_ns   = "test"
+	test_name = "test-jenkinsimage"
+)
+
+func TestRenderPluginsList(t *testing.T) {
+	t.Run("TestOnePluginPerLine", func(t *testing.T) {
+		plugins := []jenkinsv1alpha1.JenkinsPlugin{{Name: "blueocean", Version: "1.3.0"}, {Name: "git"}}
+		require.Equal(t, "blueocean:1.3.0\ngit\n", renderPluginsList(plugins))
+	})
+}
+
+func TestPluginsHash(t *testing.T) {
+	t.Run("TestHashChangesWithPlugins", func(t *testing.T) {
+		plugins := []jenkinsv1alpha1.JenkinsPlugin{{Name: "blueocean", Version: "1.3.0"}}
+		hash := pluginsHash(plugins)
+		require.Equal(t, hash, pluginsHash([]jenkinsv1alpha1.JenkinsPlugin{{Name: "blueocean", Version: "1.3.0"}}))
+		require.NotEqual(t, hash, pluginsHash([]jenkinsv1alpha1.JenkinsPlugin{{Name: "blueocean", Version: "1.4.0"}}))
+	})
+}
+
+func TestFindBuildForHash(t *testing.T) {
+	t.Run("TestFindsBuildOfTheBuildConfig", func(t *testing.T) {
+		bc := newBuildConfig(&jenkinsv1alpha1.JenkinsImage{ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name}})
+		newBuild := func(name, bcName, hash string) *buildv1.Build {
+			return &buildv1.Build{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   test_ns,
+				Name:        name,
+				Labels:      map[string]string{BuildConfigLabel: bcName},
+				Annotations: map[string]string{PluginsHashAnnotation: hash},
+			}}
+		}
+		s := mocks.NewScheme()
+		r := &ReconcileJenkinsImage{
+			client: mocks.NewFakeClient(s, newBuild(test_name+"-1", test_name, "a"), newBuild("other-1", "other", "b")),
+			scheme: s,
+		}
+
+		build, err := r.findBuildForHash(bc, "a")
+		require.NoError(t, err)
+		require.Equal(t, test_name+"-1", build.Name)
+		build, err = r.findBuildForHash(bc, "b")
+		require.NoError(t, err)
+		require.Nil(t, build)
+	})
+}
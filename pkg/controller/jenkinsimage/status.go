@@ -0,0 +1,147 @@
+package jenkinsimage
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// BuildHistoryLimit is the number of builds reported in the status of a JenkinsImage
+	BuildHistoryLimit = 5
+
+	// Reasons used in the JenkinsImage status conditions
+	ReasonInvalidSpec = "InvalidSpec"
+	ReasonValidSpec   = "ValidSpec"
+
+	// Reasons of the events recorded for the builds of a JenkinsImage
+	ReasonBuildStarted     = "BuildStarted"
+	ReasonBuildStartFailed = "BuildStartFailed"
+	ReasonBuildSucceeded   = "BuildSucceeded"
+	ReasonBuildFailed      = "BuildFailed"
+)
+
+// updateStatus computes the phase, build history and image of the JenkinsImage from the builds of its
+// BuildConfig and writes them through the status subresource when they changed.
+func (r *ReconcileJenkinsImage) updateStatus(instance *jenkinsv1alpha1.JenkinsImage, bc *buildv1.BuildConfig) error {
+	builds := &buildv1.BuildList{}
+	opts := client.InNamespace(bc.Namespace).MatchingLabels(map[string]string{BuildConfigLabel: bc.Name})
+	if err := r.client.List(context.TODO(), opts, builds); err != nil {
+		return err
+	}
+	status := instance.Status.DeepCopy()
+	status.SetCondition(jenkinsv1alpha1.JenkinsValid, corev1.ConditionTrue, ReasonValidSpec, "")
+	setBuildsStatus(status, builds.Items)
+	r.recordBuildEvents(instance, status)
+
+	if reflect.DeepEqual(instance.Status, *status) {
+		return nil
+	}
+	instance.Status = *status
+	return r.client.Status().Update(context.TODO(), instance)
+}
+
+// recordBuildEvents records the builds of the history which completed or failed since the previous status
+func (r *ReconcileJenkinsImage) recordBuildEvents(instance *jenkinsv1alpha1.JenkinsImage, status *jenkinsv1alpha1.JenkinsImageStatus) {
+	previous := map[string]jenkinsv1alpha1.JenkinsImagePhase{}
+	for _, build := range instance.Status.Builds {
+		previous[build.Name] = build.Phase
+	}
+	for _, build := range status.Builds {
+		if previous[build.Name] == build.Phase {
+			continue
+		}
+		switch build.Phase {
+		case jenkinsv1alpha1.JenkinsImagePhaseComplete:
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, ReasonBuildSucceeded, "Build %s succeeded", build.Name)
+		case jenkinsv1alpha1.JenkinsImagePhaseFailed:
+			r.recorder.Eventf(instance, corev1.EventTypeWarning, ReasonBuildFailed, "Build %s failed: %s", build.Name,
+				strings.TrimSpace(build.Reason+" "+build.Message))
+		}
+	}
+}
+
+// updateInvalidSpecStatus reports that the spec of the JenkinsImage cannot be built, leaving the builds as they are
+func (r *ReconcileJenkinsImage) updateInvalidSpecStatus(instance *jenkinsv1alpha1.JenkinsImage, specErr error) error {
+	status := instance.Status.DeepCopy()
+	status.SetCondition(jenkinsv1alpha1.JenkinsValid, corev1.ConditionFalse, ReasonInvalidSpec, specErr.Error())
+
+	if reflect.DeepEqual(instance.Status, *status) {
+		return nil
+	}
+	instance.Status = *status
+	return r.client.Status().Update(context.TODO(), instance)
+}
+
+// setBuildsStatus fills the build history of status with the most recent builds. The phase follows the latest
+// build started by the operator, while the image is the output of the most recent successful build.
+func setBuildsStatus(status *jenkinsv1alpha1.JenkinsImageStatus, builds []buildv1.Build) {
+	sort.SliceStable(builds, func(i, j int) bool {
+		return builds[j].CreationTimestamp.Before(&builds[i].CreationTimestamp)
+	})
+
+	status.Builds = nil
+	status.Phase = jenkinsv1alpha1.JenkinsImagePhasePending
+	imageFound := false
+	for i := range builds {
+		build := &builds[i]
+		phase := imagePhase(build.Status.Phase)
+		if len(status.Builds) < BuildHistoryLimit {
+			entry := jenkinsv1alpha1.JenkinsImageBuild{
+				Name:           build.Name,
+				Phase:          phase,
+				StartTime:      build.Status.StartTimestamp,
+				CompletionTime: build.Status.CompletionTimestamp,
+			}
+			if phase == jenkinsv1alpha1.JenkinsImagePhaseFailed {
+				entry.Reason = string(build.Status.Reason)
+				entry.Message = build.Status.Message
+			}
+			status.Builds = append(status.Builds, entry)
+		}
+		if build.Name == status.LatestBuild {
+			status.Phase = phase
+		}
+		if !imageFound && phase == jenkinsv1alpha1.JenkinsImagePhaseComplete {
+			status.Image, status.ImageDigest = buildOutputImage(build)
+			imageFound = true
+		}
+	}
+}
+
+// imagePhase maps the phase of a build to the phase of the JenkinsImage
+func imagePhase(phase buildv1.BuildPhase) jenkinsv1alpha1.JenkinsImagePhase {
+	switch phase {
+	case buildv1.BuildPhaseRunning:
+		return jenkinsv1alpha1.JenkinsImagePhaseBuilding
+	case buildv1.BuildPhaseComplete:
+		return jenkinsv1alpha1.JenkinsImagePhaseComplete
+	case buildv1.BuildPhaseFailed, buildv1.BuildPhaseError, buildv1.BuildPhaseCancelled:
+		return jenkinsv1alpha1.JenkinsImagePhaseFailed
+	default:
+		return jenkinsv1alpha1.JenkinsImagePhasePending
+	}
+}
+
+// buildOutputImage returns the pullspec by digest and the digest of the image pushed by a complete build. The
+// pullspec falls back to the output reference of the build when the digest is not reported.
+func buildOutputImage(build *buildv1.Build) (string, string) {
+	reference := build.Status.OutputDockerImageReference
+	if build.Status.Output.To == nil || len(build.Status.Output.To.ImageDigest) == 0 {
+		return reference, ""
+	}
+	digest := build.Status.Output.To.ImageDigest
+	repository := reference
+	if i := strings.LastIndex(repository, "@"); i >= 0 {
+		repository = repository[:i]
+	} else if i := strings.LastIndex(repository, ImageToTagSeparator); i > strings.LastIndex(repository, ImageNameSeparator) {
+		repository = repository[:i]
+	}
+	return repository + "@" + digest, digest
+}
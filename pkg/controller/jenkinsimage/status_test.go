@@ -0,0 +1,82 @@
+package jenkinsimage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestBuild(number int, phase buildv1.BuildPhase) buildv1.Build {
+	created := metav1.NewTime(time.Date(2019, 1, 1, 0, number, 0, 0, time.UTC))
+	return buildv1.Build{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         test_ns,
+			Name:              fmt.Sprintf("%s-%d", test_name, number),
+			Labels:            map[string]string{BuildConfigLabel: test_name},
+			CreationTimestamp: created,
+		},
+		Status: buildv1.BuildStatus{Phase: phase, StartTimestamp: &created},
+	}
+}
+
+func TestSetBuildsStatus(t *testing.T) {
+	t.Run("TestNoBuildIsPending", func(t *testing.T) {
+		status := &jenkinsv1alpha1.JenkinsImageStatus{}
+		setBuildsStatus(status, nil)
+		require.Equal(t, jenkinsv1alpha1.JenkinsImagePhasePending, status.Phase)
+		require.Empty(t, status.Builds)
+	})
+
+	t.Run("TestHistoryIsLimitedAndSorted", func(t *testing.T) {
+		builds := []buildv1.Build{}
+		for i := 1; i <= BuildHistoryLimit+2; i++ {
+			builds = append(builds, newTestBuild(i, buildv1.BuildPhaseComplete))
+		}
+		builds[len(builds)-1].Status.Phase = buildv1.BuildPhaseRunning
+		status := &jenkinsv1alpha1.JenkinsImageStatus{LatestBuild: builds[len(builds)-1].Name}
+		setBuildsStatus(status, builds)
+
+		require.Equal(t, jenkinsv1alpha1.JenkinsImagePhaseBuilding, status.Phase)
+		require.Len(t, status.Builds, BuildHistoryLimit)
+		require.Equal(t, fmt.Sprintf("%s-%d", test_name, BuildHistoryLimit+2), status.Builds[0].Name)
+		require.Equal(t, fmt.Sprintf("%s-%d", test_name, 3), status.Builds[BuildHistoryLimit-1].Name)
+	})
+
+	t.Run("TestFailureKeepsLastImage", func(t *testing.T) {
+		complete := newTestBuild(1, buildv1.BuildPhaseComplete)
+		complete.Status.OutputDockerImageReference = DefaultRegistryHostname + "/test/" + test_name + ":latest"
+		complete.Status.Output.To = &buildv1.BuildStatusOutputTo{ImageDigest: "sha256:1234"}
+		failed := newTestBuild(2, buildv1.BuildPhaseFailed)
+		failed.Status.Reason = buildv1.StatusReasonPushImageToRegistryFailed
+		failed.Status.Message = "Failed to push the image"
+		status := &jenkinsv1alpha1.JenkinsImageStatus{LatestBuild: failed.Name}
+		setBuildsStatus(status, []buildv1.Build{complete, failed})
+
+		require.Equal(t, jenkinsv1alpha1.JenkinsImagePhaseFailed, status.Phase)
+		require.Equal(t, string(buildv1.StatusReasonPushImageToRegistryFailed), status.Builds[0].Reason)
+		require.Equal(t, failed.Status.Message, status.Builds[0].Message)
+		require.Empty(t, status.Builds[1].Reason)
+		require.Equal(t, DefaultRegistryHostname+"/test/"+test_name+"@sha256:1234", status.Image)
+		require.Equal(t, "sha256:1234", status.ImageDigest)
+	})
+}
+
+func TestBuildOutputImage(t *testing.T) {
+	t.Run("TestDigestReplacesTag", func(t *testing.T) {
+		build := newTestBuild(1, buildv1.BuildPhaseComplete)
+		build.Status.OutputDockerImageReference = "registry:5000/test/jenkins:latest"
+		image, digest := buildOutputImage(&build)
+		require.Equal(t, "registry:5000/test/jenkins:latest", image)
+		require.Empty(t, digest)
+
+		build.Status.Output.To = &buildv1.BuildStatusOutputTo{ImageDigest: "sha256:5678"}
+		image, digest = buildOutputImage(&build)
+		require.Equal(t, "registry:5000/test/jenkins@sha256:5678", image)
+		require.Equal(t, "sha256:5678", digest)
+	})
+}
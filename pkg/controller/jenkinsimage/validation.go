@@ -0,0 +1,56 @@
+package jenkinsimage
+
+import (
+	"regexp"
+	"strings"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var (
+	// pluginNameRegexp matches the short names of the Jenkins plugins, which are their Maven artifactIds
+	pluginNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+	// pluginVersionRegexp matches the versions accepted in plugins.txt, e.g. 1.3.0 or latest
+	pluginVersionRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.+~-]*$`)
+)
+
+// ValidateJenkinsImage checks the spec of the JenkinsImage before the plugins list is rendered and built
+func ValidateJenkinsImage(image *jenkinsv1alpha1.JenkinsImage) field.ErrorList {
+	errs := field.ErrorList{}
+	// The builds are labelled with the name of the BuildConfig, named after the JenkinsImage
+	for _, msg := range validation.IsValidLabelValue(image.Name) {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), image.Name, "the builds cannot be labelled with the name: "+msg))
+	}
+	path := field.NewPath("spec", "plugins")
+	names := map[string]bool{}
+	for i, plugin := range image.Spec.Plugins {
+		pluginPath := path.Index(i)
+		if len(plugin.Name) == 0 {
+			errs = append(errs, field.Required(pluginPath.Child("name"), ""))
+		} else if !pluginNameRegexp.MatchString(plugin.Name) {
+			errs = append(errs, field.Invalid(pluginPath.Child("name"), plugin.Name, "must be the short name of a plugin, e.g. blueocean"))
+		} else if names[plugin.Name] {
+			errs = append(errs, field.Duplicate(pluginPath.Child("name"), plugin.Name))
+		}
+		names[plugin.Name] = true
+		if len(plugin.Version) > 0 && !pluginVersionRegexp.MatchString(plugin.Version) {
+			errs = append(errs, field.Invalid(pluginPath.Child("version"), plugin.Version, "must be a plugin version, e.g. 1.3.0 or latest"))
+		}
+	}
+	return errs
+}
+
+// SetJenkinsImageDefaults trims the plugins names and versions, which are written as is to plugins.txt.
+// It is called by the defaulting admission webhook.
+func SetJenkinsImageDefaults(image *jenkinsv1alpha1.JenkinsImage) {
+	if image.Spec.Plugins == nil {
+		image.Spec.Plugins = []jenkinsv1alpha1.JenkinsPlugin{}
+	}
+	for i := range image.Spec.Plugins {
+		plugin := &image.Spec.Plugins[i]
+		plugin.Name = strings.TrimSpace(plugin.Name)
+		plugin.Version = strings.TrimSpace(plugin.Version)
+	}
+}
@@ -0,0 +1,76 @@
+package jenkinsimage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestValidateJenkinsImage(t *testing.T) {
+	t.Run("TestValidPlugins", func(t *testing.T) {
+		image := &jenkinsv1alpha1.JenkinsImage{
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name},
+			Spec: jenkinsv1alpha1.JenkinsImageSpec{Plugins: []jenkinsv1alpha1.JenkinsPlugin{
+				{Name: "blueocean", Version: "1.3.0"}, {Name: "workflow-aggregator", Version: "latest"}, {Name: "git"},
+			}},
+		}
+		require.Empty(t, ValidateJenkinsImage(image))
+	})
+
+	t.Run("TestInvalidPlugins", func(t *testing.T) {
+		image := &jenkinsv1alpha1.JenkinsImage{
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: strings.Repeat("i", 64)},
+			Spec: jenkinsv1alpha1.JenkinsImageSpec{Plugins: []jenkinsv1alpha1.JenkinsPlugin{
+				{Name: "blueocean", Version: "1.3.0"},
+				{Name: ""},
+				{Name: "git plugin"},
+				{Name: "blueocean", Version: "1.4.0"},
+				{Name: "git", Version: "4.0:https://example.com/git.hpi"},
+			}},
+		}
+
+		fields := []string{}
+		for _, err := range ValidateJenkinsImage(image) {
+			fields = append(fields, err.Field)
+		}
+		require.Equal(t, []string{
+			"metadata.name",
+			"spec.plugins[1].name",
+			"spec.plugins[2].name",
+			"spec.plugins[3].name",
+			"spec.plugins[4].version",
+		}, fields)
+	})
+
+	t.Run("TestInvalidSpecIsNotBuilt", func(t *testing.T) {
+		instance := &jenkinsv1alpha1.JenkinsImage{
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name},
+			Spec:       jenkinsv1alpha1.JenkinsImageSpec{Plugins: []jenkinsv1alpha1.JenkinsPlugin{{Name: "blue ocean"}}},
+		}
+		s := mocks.NewScheme()
+		c := mocks.NewFakeClient(s, instance)
+		recorder := record.NewFakeRecorder(10)
+		r := &ReconcileJenkinsImage{client: c, scheme: s, recorder: recorder}
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: test_ns, Name: test_name}}
+
+		_, err := r.Reconcile(request)
+		require.NoError(t, err)
+		require.True(t, kubeerrors.IsNotFound(c.Get(context.TODO(), request.NamespacedName, &imagev1.ImageStream{})))
+		require.NoError(t, c.Get(context.TODO(), request.NamespacedName, instance))
+		condition := instance.Status.GetCondition(jenkinsv1alpha1.JenkinsValid)
+		require.Equal(t, corev1.ConditionFalse, condition.Status)
+		require.Equal(t, ReasonInvalidSpec, condition.Reason)
+		require.Contains(t, <-recorder.Events, "Warning "+ReasonInvalidSpec)
+	})
+}
@@ -0,0 +1,272 @@
+package jenkinsrestore
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	kappsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// RestoreRequeueDelay is the delay between two checks while waiting for the backup or for Jenkins to stop
+const RestoreRequeueDelay = 5 * time.Second
+
+var log = logf.Log.WithName("jenkinsrestore_controller")
+
+// Add creates a new JenkinsRestore Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileJenkinsRestore{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("jenkinsrestore-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &jenkinsv1alpha1.JenkinsRestore{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	// Watch the jobs restoring the archives
+	return c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &jenkinsv1alpha1.JenkinsRestore{},
+	})
+}
+
+// blank assignment to verify that ReconcileJenkinsRestore implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileJenkinsRestore{}
+
+// ReconcileJenkinsRestore reconciles a JenkinsRestore object
+type ReconcileJenkinsRestore struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// Reconcile stops the Jenkins instance referenced by the JenkinsRestore, replaces its JENKINS_HOME with the
+// archive of the backup and starts it again. A restore is never run again once it completed or failed.
+func (r *ReconcileJenkinsRestore) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	logger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	logger.Info("Reconciling JenkinsRestore")
+
+	instance := &jenkinsv1alpha1.JenkinsRestore{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if instance.Status.Phase.IsFinished() {
+		return reconcile.Result{}, nil
+	}
+
+	status := instance.Status.DeepCopy()
+	result, err := r.runRestore(instance, status)
+	if err != nil {
+		logger.Error(err, "Error while running the restore")
+	}
+	if !reflect.DeepEqual(instance.Status, *status) {
+		instance.Status = *status
+		if updateErr := r.client.Status().Update(context.TODO(), instance); updateErr != nil && err == nil {
+			err = updateErr
+		}
+	}
+	return result, err
+}
+
+// runRestore moves the restore one step forward and fills status with its progress
+func (r *ReconcileJenkinsRestore) runRestore(restore *jenkinsv1alpha1.JenkinsRestore, status *jenkinsv1alpha1.JenkinsRestoreStatus) (reconcile.Result, error) {
+	if len(status.Phase) == 0 {
+		status.Phase = jenkinsv1alpha1.JenkinsBackupPhasePending
+	}
+	backup := &jenkinsv1alpha1.JenkinsBackup{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.BackupRef}, backup)
+	if errors.IsNotFound(err) {
+		setFailed(status, fmt.Sprintf("JenkinsBackup %s not found", restore.Spec.BackupRef))
+		return reconcile.Result{}, nil
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+	switch backup.Status.Phase {
+	case jenkinsv1alpha1.JenkinsBackupPhaseFailed:
+		setFailed(status, fmt.Sprintf("JenkinsBackup %s failed", backup.Name))
+		return reconcile.Result{}, nil
+	case jenkinsv1alpha1.JenkinsBackupPhaseCompleted:
+	default:
+		status.Message = fmt.Sprintf("Waiting for JenkinsBackup %s to complete", backup.Name)
+		return reconcile.Result{RequeueAfter: RestoreRequeueDelay}, nil
+	}
+
+	cr := &jenkinsv1alpha1.Jenkins{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.JenkinsRef}, cr)
+	if errors.IsNotFound(err) {
+		setFailed(status, fmt.Sprintf("Jenkins instance %s not found", restore.Spec.JenkinsRef))
+		return reconcile.Result{}, nil
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !cr.Spec.Persistence.Enabled {
+		setFailed(status, fmt.Sprintf("Jenkins instance %s is not persistent, JENKINS_HOME cannot be restored", cr.Name))
+		return reconcile.Result{}, nil
+	}
+	workload, err := r.getWorkload(cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Record the replicas before stopping Jenkins so that they can be restored even if the operator restarts
+	if status.Replicas == nil {
+		now := metav1.Now()
+		replicas := workloadReplicas(workload)
+		status.Phase = jenkinsv1alpha1.JenkinsBackupPhaseRunning
+		status.StartTime = &now
+		status.Replicas = &replicas
+		status.Message = fmt.Sprintf("Stopping Jenkins instance %s", cr.Name)
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	job := &batchv1.Job{}
+	desired := newRestoreJob(restore, backup, cr)
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, job)
+	if errors.IsNotFound(err) {
+		stopped, err := r.stopJenkins(cr, workload)
+		if err != nil || !stopped {
+			return reconcile.Result{RequeueAfter: RestoreRequeueDelay}, err
+		}
+		if err := controllerutil.SetControllerReference(restore, desired, r.scheme); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Info("Creating a new Job", "Job.Namespace", desired.Namespace, "Job.Name", desired.Name)
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			return reconcile.Result{}, err
+		}
+		job = desired
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+	status.Message = fmt.Sprintf("Restoring %s into JENKINS_HOME of %s", backup.Status.Archive, cr.Name)
+
+	finished, failure := jenkins.JobCompletion(job)
+	if !finished {
+		return reconcile.Result{}, nil
+	}
+	// Jenkins is started again whatever the outcome: the archive is verified before JENKINS_HOME is replaced
+	if err := r.scaleWorkload(workload, *status.Replicas); err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(failure) > 0 {
+		setFailed(status, jenkins.JobFailureMessage(r.client, job, failure))
+		return reconcile.Result{}, nil
+	}
+	result, err := jenkins.ReadArchiveResult(r.client, job)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	now := metav1.Now()
+	status.Phase = jenkinsv1alpha1.JenkinsBackupPhaseCompleted
+	status.Message = fmt.Sprintf("%s restored into JENKINS_HOME of %s", result.Archive, cr.Name)
+	status.Size = result.Size
+	status.Checksum = result.Checksum
+	status.CompletionTime = &now
+	return reconcile.Result{}, nil
+}
+
+// getWorkload returns the Deployment or DeploymentConfig running the Jenkins instance, nil if there is none
+func (r *ReconcileJenkinsRestore) getWorkload(cr *jenkinsv1alpha1.Jenkins) (runtime.Object, error) {
+	var workload runtime.Object = &kappsv1.Deployment{}
+	if len(cr.Status.Resources.DeploymentConfig) > 0 {
+		workload = &appsv1.DeploymentConfig{}
+	}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}, workload)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	return workload, err
+}
+
+func workloadReplicas(workload runtime.Object) int32 {
+	switch w := workload.(type) {
+	case *kappsv1.Deployment:
+		if w.Spec.Replicas == nil {
+			return 1
+		}
+		return *w.Spec.Replicas
+	case *appsv1.DeploymentConfig:
+		return w.Spec.Replicas
+	}
+	return 0
+}
+
+// scaleWorkload sets the replicas of the workload if they differ
+func (r *ReconcileJenkinsRestore) scaleWorkload(workload runtime.Object, replicas int32) error {
+	switch w := workload.(type) {
+	case *kappsv1.Deployment:
+		if w.Spec.Replicas != nil && *w.Spec.Replicas == replicas {
+			return nil
+		}
+		w.Spec.Replicas = &replicas
+	case *appsv1.DeploymentConfig:
+		if w.Spec.Replicas == replicas {
+			return nil
+		}
+		w.Spec.Replicas = replicas
+	default:
+		return nil
+	}
+	return r.client.Update(context.TODO(), workload)
+}
+
+// stopJenkins scales the workload down and returns true once no pod of the Jenkins instance is left
+func (r *ReconcileJenkinsRestore) stopJenkins(cr *jenkinsv1alpha1.Jenkins, workload runtime.Object) (bool, error) {
+	if err := r.scaleWorkload(workload, 0); err != nil {
+		return false, err
+	}
+	pods := &corev1.PodList{}
+	labels := map[string]string{
+		jenkins.JenkinsAppLabelName: cr.Name,
+		jenkins.JenkinsNameLabel:    cr.Name,
+	}
+	if err := r.client.List(context.TODO(), client.InNamespace(cr.Namespace).MatchingLabels(labels), pods); err != nil {
+		return false, err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func setFailed(status *jenkinsv1alpha1.JenkinsRestoreStatus, message string) {
+	now := metav1.Now()
+	status.Phase = jenkinsv1alpha1.JenkinsBackupPhaseFailed
+	status.Message = message
+	status.CompletionTime = &now
+}
@@ -0,0 +1,180 @@
+package jenkinsrestore
+
+import (
+	"context"
+	"testing"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	kappsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	test_ns      = "test"
+	test_name    = "test-jenkinsrestore"
+	test_jenkins = "test-jenkins"
+	test_backup  = "test-jenkinsbackup"
+)
+
+func newTestObjects(backupPhase jenkinsv1alpha1.JenkinsBackupPhase) []runtime.Object {
+	cr := mocks.JenkinsCRMock(test_ns, test_jenkins)
+	cr.Spec.Persistence.Enabled = true
+	replicas := int32(1)
+	deployment := &kappsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_jenkins},
+		Spec:       kappsv1.DeploymentSpec{Replicas: &replicas},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: test_ns,
+			Name:      test_jenkins + "-1",
+			Labels:    map[string]string{jenkins.JenkinsAppLabelName: test_jenkins, jenkins.JenkinsNameLabel: test_jenkins},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	backup := &jenkinsv1alpha1.JenkinsBackup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_backup},
+		Spec: jenkinsv1alpha1.JenkinsBackupSpec{
+			JenkinsRef:  test_jenkins,
+			Destination: jenkinsv1alpha1.JenkinsBackupDestination{PersistentVolumeClaim: "jenkins-backups"},
+		},
+		Status: jenkinsv1alpha1.JenkinsBackupStatus{
+			Phase:    backupPhase,
+			Archive:  test_jenkins + "/" + test_backup + jenkins.ArchiveExtension,
+			Size:     2048,
+			Checksum: "sha256:abcd",
+		},
+	}
+	restore := &jenkinsv1alpha1.JenkinsRestore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name},
+		Spec:       jenkinsv1alpha1.JenkinsRestoreSpec{JenkinsRef: test_jenkins, BackupRef: test_backup},
+	}
+	return []runtime.Object{cr, deployment, pod, backup, restore}
+}
+
+func newTestReconciler(objs ...runtime.Object) (*ReconcileJenkinsRestore, *mocks.FakeClient) {
+	s := mocks.NewScheme()
+	c := mocks.NewFakeClient(s, objs...)
+	return &ReconcileJenkinsRestore{client: c, scheme: s}, c
+}
+
+func testRequest() reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: test_ns, Name: test_name}}
+}
+
+func getRestore(t *testing.T, c *mocks.FakeClient) *jenkinsv1alpha1.JenkinsRestore {
+	restore := &jenkinsv1alpha1.JenkinsRestore{}
+	require.NoError(t, c.Get(context.TODO(), testRequest().NamespacedName, restore))
+	return restore
+}
+
+func getReplicas(t *testing.T, c *mocks.FakeClient) int32 {
+	deployment := &kappsv1.Deployment{}
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: test_jenkins}, deployment))
+	return *deployment.Spec.Replicas
+}
+
+// finishJob sets the status of the restore job and creates its pod terminated with the given exit code and message
+func finishJob(t *testing.T, c *mocks.FakeClient, exitCode int32, message string) {
+	job := &batchv1.Job{}
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: test_name + RestoreJobSuffix}, job))
+	if exitCode == 0 {
+		job.Status.Succeeded = 1
+	} else {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+	}
+	require.NoError(t, c.Status().Update(context.TODO(), job))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: job.Name + "-pod", Labels: map[string]string{jenkins.JobNameLabel: job.Name}},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message}}},
+			},
+		},
+	}
+	require.NoError(t, c.Create(context.TODO(), pod))
+}
+
+// stopJenkins reconciles until the restore job is created, removing the Jenkins pod once scaled down
+func stopJenkins(t *testing.T, r *ReconcileJenkinsRestore, c *mocks.FakeClient) {
+	_, err := r.Reconcile(testRequest())
+	require.NoError(t, err)
+	restore := getRestore(t, c)
+	require.Equal(t, jenkinsv1alpha1.JenkinsBackupPhaseRunning, restore.Status.Phase)
+	require.Equal(t, int32(1), *restore.Status.Replicas)
+
+	// the job waits for the pods of Jenkins to be gone
+	result, err := r.Reconcile(testRequest())
+	require.NoError(t, err)
+	require.Equal(t, RestoreRequeueDelay, result.RequeueAfter)
+	require.Equal(t, int32(0), getReplicas(t, c))
+	job := &batchv1.Job{}
+	require.Error(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: test_name + RestoreJobSuffix}, job))
+
+	require.NoError(t, c.Delete(context.TODO(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_jenkins + "-1"}}))
+	_, err = r.Reconcile(testRequest())
+	require.NoError(t, err)
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: test_ns, Name: test_name + RestoreJobSuffix}, job))
+	require.Equal(t, test_name, metav1.GetControllerOf(job).Name)
+	container := job.Spec.Template.Spec.Containers[0]
+	require.Contains(t, container.Env, corev1.EnvVar{Name: RestoreChecksumEnv, Value: "sha256:abcd"})
+	require.False(t, container.VolumeMounts[0].ReadOnly)
+	require.True(t, container.VolumeMounts[1].ReadOnly)
+}
+
+func TestReconcileRestore(t *testing.T) {
+	t.Run("TestRestoreCompletes", func(t *testing.T) {
+		r, c := newTestReconciler(newTestObjects(jenkinsv1alpha1.JenkinsBackupPhaseCompleted)...)
+		stopJenkins(t, r, c)
+
+		finishJob(t, c, 0, `{"archive":"test-jenkins/test-jenkinsbackup.tar.gz","size":2048,"checksum":"sha256:abcd"}`)
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, int32(1), getReplicas(t, c))
+		restore := getRestore(t, c)
+		require.Equal(t, jenkinsv1alpha1.JenkinsBackupPhaseCompleted, restore.Status.Phase)
+		require.Equal(t, int64(2048), restore.Status.Size)
+		require.Equal(t, "sha256:abcd", restore.Status.Checksum)
+		require.NotNil(t, restore.Status.CompletionTime)
+	})
+
+	t.Run("TestJenkinsIsRestartedOnFailure", func(t *testing.T) {
+		r, c := newTestReconciler(newTestObjects(jenkinsv1alpha1.JenkinsBackupPhaseCompleted)...)
+		stopJenkins(t, r, c)
+
+		finishJob(t, c, 1, "checksum of test-jenkins/test-jenkinsbackup.tar.gz does not match sha256:abcd")
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, int32(1), getReplicas(t, c))
+		restore := getRestore(t, c)
+		require.Equal(t, jenkinsv1alpha1.JenkinsBackupPhaseFailed, restore.Status.Phase)
+		require.Contains(t, restore.Status.Message, "does not match")
+	})
+
+	t.Run("TestWaitsForBackup", func(t *testing.T) {
+		r, c := newTestReconciler(newTestObjects(jenkinsv1alpha1.JenkinsBackupPhaseRunning)...)
+
+		result, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, RestoreRequeueDelay, result.RequeueAfter)
+		require.Equal(t, jenkinsv1alpha1.JenkinsBackupPhasePending, getRestore(t, c).Status.Phase)
+		require.Equal(t, int32(1), getReplicas(t, c))
+	})
+
+	t.Run("TestFailedBackupIsRejected", func(t *testing.T) {
+		r, c := newTestReconciler(newTestObjects(jenkinsv1alpha1.JenkinsBackupPhaseFailed)...)
+
+		_, err := r.Reconcile(testRequest())
+		require.NoError(t, err)
+		require.Equal(t, jenkinsv1alpha1.JenkinsBackupPhaseFailed, getRestore(t, c).Status.Phase)
+		require.Equal(t, int32(1), getReplicas(t, c))
+	})
+}
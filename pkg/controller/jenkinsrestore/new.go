@@ -0,0 +1,70 @@
+package jenkinsrestore
+
+import (
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	RestoreJobSuffix     = "-restore"
+	RestoreContainerName = "restore"
+	RestoreChecksumEnv   = "BACKUP_CHECKSUM"
+)
+
+// restoreScript verifies the checksum of the archive, then replaces the content of JENKINS_HOME with it and
+// reports the archive in the termination message
+const restoreScript = `set -euo pipefail
+archive="` + jenkins.BackupMountPath + `/${BACKUP_ARCHIVE}"
+if ! echo "${BACKUP_CHECKSUM#sha256:}  $archive" | sha256sum -c - > /dev/null; then
+  echo "checksum of $BACKUP_ARCHIVE does not match $BACKUP_CHECKSUM" > /dev/termination-log
+  exit 1
+fi
+find ` + jenkins.JenkinsVolumeMountPath + ` -mindepth 1 -maxdepth 1 ! -name lost+found -exec rm -rf {} +
+tar -xzf "$archive" -C ` + jenkins.JenkinsVolumeMountPath + `
+size=$(stat -c %s "$archive")
+printf '{"archive":"%s","size":%s,"checksum":"%s"}' "$BACKUP_ARCHIVE" "$size" "$BACKUP_CHECKSUM" > /dev/termination-log
+`
+
+// newRestoreJob returns the job extracting the archive of the backup into JENKINS_HOME of the Jenkins instance
+func newRestoreJob(restore *jenkinsv1alpha1.JenkinsRestore, backup *jenkinsv1alpha1.JenkinsBackup, cr *jenkinsv1alpha1.Jenkins) *batchv1.Job {
+	backoffLimit := int32(2)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      restore.Name + RestoreJobSuffix,
+			Namespace: restore.Namespace,
+			Labels: map[string]string{
+				jenkins.JenkinsAppLabel: cr.Name,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    RestoreContainerName,
+							Image:   jenkins.JenkinsImage,
+							Command: []string{"/bin/bash", "-c", restoreScript},
+							Env: []corev1.EnvVar{
+								{Name: jenkins.BackupArchiveEnv, Value: backup.Status.Archive},
+								{Name: RestoreChecksumEnv, Value: backup.Status.Checksum},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: jenkins.JenkinsVolumeName, MountPath: jenkins.JenkinsVolumeMountPath},
+								{Name: jenkins.BackupVolumeName, MountPath: jenkins.BackupMountPath, ReadOnly: true},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						jenkins.NewClaimVolume(jenkins.JenkinsVolumeName, jenkins.JenkinsClaimName(cr), false),
+						jenkins.NewClaimVolume(jenkins.BackupVolumeName, backup.Spec.Destination.PersistentVolumeClaim, true),
+					},
+				},
+			},
+		},
+	}
+}
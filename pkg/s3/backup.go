@@ -0,0 +1,151 @@
+package s3
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// BackupCommand is the argument of the operator binary running a backup to the object storage
+	BackupCommand = "s3-backup"
+
+	EndpointEnv        = "S3_ENDPOINT"
+	RegionEnv          = "S3_REGION"
+	BucketEnv          = "S3_BUCKET"
+	PrefixEnv          = "S3_PREFIX"
+	AccessKeyIDEnv     = "AWS_ACCESS_KEY_ID"
+	SecretAccessKeyEnv = "AWS_SECRET_ACCESS_KEY"
+	ExcludesEnv        = "BACKUP_EXCLUDES"
+
+	// ArchiveKeyPrefix starts the name of the archives, followed by the time of the backup
+	ArchiveKeyPrefix  = "jenkins-home-"
+	archiveTimeFormat = "20060102150405"
+)
+
+// archiveResult is written to the termination message, like the results of the other backup jobs
+type archiveResult struct {
+	Archive  string `json:"archive"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// RunBackup archives the directory home and uploads it to the object storage defined by the environment.
+// The result, or the error, is written to terminationLog.
+func RunBackup(home, terminationLog string, getenv func(string) string) error {
+	result, err := runBackup(home, getenv)
+	message := ""
+	if err != nil {
+		message = err.Error()
+	} else {
+		content, _ := json.Marshal(result)
+		message = string(content)
+	}
+	if writeErr := ioutil.WriteFile(terminationLog, []byte(message), 0644); writeErr != nil && err == nil {
+		err = writeErr
+	}
+	return err
+}
+
+func runBackup(home string, getenv func(string) string) (*archiveResult, error) {
+	credentials := Credentials{AccessKeyID: getenv(AccessKeyIDEnv), SecretAccessKey: getenv(SecretAccessKeyEnv)}
+	client, err := NewClient(getenv(EndpointEnv), getenv(RegionEnv), credentials)
+	if err != nil {
+		return nil, err
+	}
+	excludes := []string{}
+	for _, exclude := range strings.Split(getenv(ExcludesEnv), "\n") {
+		if len(exclude) > 0 {
+			excludes = append(excludes, exclude)
+		}
+	}
+	key := getenv(PrefixEnv) + ArchiveKeyPrefix + time.Now().UTC().Format(archiveTimeFormat) + ".tar.gz"
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(WriteArchive(writer, home, excludes))
+	}()
+	uploaded, err := client.Upload(getenv(BucketEnv), key, reader)
+	// Unblock the archive writer if the upload stopped reading
+	reader.CloseWithError(io.ErrClosedPipe)
+	if err != nil {
+		return nil, fmt.Errorf("upload of %s failed: %v", key, err)
+	}
+	return &archiveResult{Archive: uploaded.Key, Size: uploaded.Size, Checksum: uploaded.Checksum}, nil
+}
+
+// WriteArchive writes a gzipped tar archive of the content of dir to w. The paths relative to dir matching one
+// of the excludes patterns are skipped with their content, as well as lost+found.
+func WriteArchive(w io.Writer, dir string, excludes []string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "lost+found" || isExcluded(rel, excludes) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		} else if !info.Mode().IsRegular() && !info.IsDir() {
+			// Sockets, pipes and devices cannot be restored
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func isExcluded(path string, excludes []string) bool {
+	for _, pattern := range excludes {
+		if matched, _ := filepath.Match(strings.Trim(pattern, "/"), path); matched {
+			return true
+		}
+	}
+	return false
+}
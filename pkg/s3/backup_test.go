@@ -0,0 +1,117 @@
+package s3
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestHome returns a temporary JENKINS_HOME holding the given files
+func newTestHome(t *testing.T, files map[string]string) string {
+	home, err := ioutil.TempDir("", "jenkins-home")
+	require.NoError(t, err)
+	for name, content := range files {
+		path := filepath.Join(home, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	return home
+}
+
+// archiveMembers returns the content of the regular files of a gzipped tar archive by name
+func archiveMembers(t *testing.T, r io.Reader) map[string]string {
+	gz, err := gzip.NewReader(r)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	members := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return members
+		}
+		require.NoError(t, err)
+		if header.Typeflag == tar.TypeReg {
+			content, err := ioutil.ReadAll(tr)
+			require.NoError(t, err)
+			members[header.Name] = string(content)
+		}
+	}
+}
+
+func TestWriteArchive(t *testing.T) {
+	t.Run("TestExcludesSkipContent", func(t *testing.T) {
+		home := newTestHome(t, map[string]string{
+			"config.xml":                        "<hudson/>",
+			"jobs/app/config.xml":               "<project/>",
+			"jobs/app/builds/1/log":             "build log",
+			"workspace/app/pom.xml":             "<project/>",
+			"lost+found/orphan":                 "orphan",
+			"jobs/app/builds/1/archive/app.jar": "jar",
+		})
+		defer os.RemoveAll(home)
+
+		archive := &bytes.Buffer{}
+		require.NoError(t, WriteArchive(archive, home, []string{"workspace", "jobs/*/builds/*/archive"}))
+		require.Equal(t, map[string]string{
+			"config.xml":            "<hudson/>",
+			"jobs/app/config.xml":   "<project/>",
+			"jobs/app/builds/1/log": "build log",
+		}, archiveMembers(t, archive))
+	})
+}
+
+func TestRunBackup(t *testing.T) {
+	t.Run("TestArchiveIsUploaded", func(t *testing.T) {
+		home := newTestHome(t, map[string]string{"config.xml": "<hudson/>", "workspace/file": "skipped"})
+		defer os.RemoveAll(home)
+		terminationLog := filepath.Join(home, "..", filepath.Base(home)+"-termination-log")
+		defer os.Remove(terminationLog)
+		fake := mocks.NewFakeS3()
+		fake.AccessKeyID = "test-key"
+		server := httptest.NewServer(fake)
+		defer server.Close()
+		env := map[string]string{
+			EndpointEnv:        server.URL,
+			BucketEnv:          "jenkins-backups",
+			PrefixEnv:          "ns/jenkins/",
+			AccessKeyIDEnv:     "test-key",
+			SecretAccessKeyEnv: "test-secret",
+			ExcludesEnv:        "workspace\n",
+		}
+
+		require.NoError(t, RunBackup(home, terminationLog, func(name string) string { return env[name] }))
+		content, err := ioutil.ReadFile(terminationLog)
+		require.NoError(t, err)
+		result := &archiveResult{}
+		require.NoError(t, json.Unmarshal(content, result))
+		require.True(t, strings.HasPrefix(result.Archive, "ns/jenkins/"+ArchiveKeyPrefix))
+		require.True(t, strings.HasPrefix(result.Checksum, "sha256:"))
+		object, found := fake.Object("jenkins-backups", result.Archive)
+		require.True(t, found)
+		require.Equal(t, int64(len(object.Data)), result.Size)
+		require.Equal(t, map[string]string{"config.xml": "<hudson/>"}, archiveMembers(t, bytes.NewReader(object.Data)))
+	})
+	t.Run("TestFailureIsReported", func(t *testing.T) {
+		home := newTestHome(t, map[string]string{"config.xml": "<hudson/>"})
+		defer os.RemoveAll(home)
+		terminationLog := filepath.Join(home, "..", filepath.Base(home)+"-termination-log")
+		defer os.Remove(terminationLog)
+
+		err := RunBackup(home, terminationLog, func(name string) string { return "" })
+		require.Error(t, err)
+		content, readErr := ioutil.ReadFile(terminationLog)
+		require.NoError(t, readErr)
+		require.Equal(t, err.Error(), string(content))
+	})
+}
@@ -0,0 +1,300 @@
+package s3
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRegion is used when no region is configured, MinIO accepts any region
+	DefaultRegion = "us-east-1"
+	// DefaultPartSize is the size of the parts of the multipart uploads
+	DefaultPartSize = 16 * 1024 * 1024
+	// MinPartSize is the minimum size of the parts of a multipart upload, except the last one
+	MinPartSize = 5 * 1024 * 1024
+	// DefaultTimeout is the timeout of the requests, long enough to send a part of an upload
+	DefaultTimeout = 5 * time.Minute
+
+	checksumAlgorithmHeader = "X-Amz-Checksum-Algorithm"
+	checksumSHA256Header    = "X-Amz-Checksum-Sha256"
+	checksumAlgorithmSHA256 = "SHA256"
+)
+
+// Credentials are the access keys used to sign the requests
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// Client talks to an S3 compatible object storage. Buckets are addressed with path style URLs, which are
+// supported by AWS S3 and MinIO alike.
+type Client struct {
+	endpoint    *url.URL
+	region      string
+	credentials Credentials
+	// HTTPClient sends the requests, its timeout is DefaultTimeout
+	HTTPClient *http.Client
+	// PartSize is the size of the parts of the multipart uploads
+	PartSize int
+	// now returns the time used to sign the requests
+	now func() time.Time
+}
+
+// NewClient returns a client of the object storage served at endpoint
+func NewClient(endpoint, region string, credentials Credentials) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || len(u.Host) == 0 {
+		return nil, fmt.Errorf("invalid object storage endpoint %q, expected http(s)://host[:port]", endpoint)
+	}
+	if len(region) == 0 {
+		region = DefaultRegion
+	}
+	return &Client{
+		endpoint:    u,
+		region:      region,
+		credentials: credentials,
+		HTTPClient:  &http.Client{Timeout: DefaultTimeout},
+		PartSize:    DefaultPartSize,
+		now:         time.Now,
+	}, nil
+}
+
+// Object describes an object of a bucket
+type Object struct {
+	Key          string    `xml:"Key"`
+	Size         int64     `xml:"Size"`
+	LastModified time.Time `xml:"LastModified"`
+	ETag         string    `xml:"ETag"`
+}
+
+// UploadResult describes an object created by a multipart upload
+type UploadResult struct {
+	Key  string
+	Size int64
+	// ETag is the entity tag computed by the server
+	ETag string
+	// Checksum is the sha256 of the whole object, as sha256:<hex>
+	Checksum string
+	// ServerChecksum is the composite checksum of the parts reported by the server, if any
+	ServerChecksum string
+}
+
+// Error is an error response of the object storage
+type Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Code) == 0 {
+		return fmt.Sprintf("object storage returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("object storage returned %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+type initiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type completedPart struct {
+	PartNumber     int    `xml:"PartNumber"`
+	ETag           string `xml:"ETag"`
+	ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	ETag           string `xml:"ETag"`
+	ChecksumSHA256 string `xml:"ChecksumSHA256"`
+}
+
+type listBucketResult struct {
+	Contents              []Object `xml:"Contents"`
+	IsTruncated           bool     `xml:"IsTruncated"`
+	NextContinuationToken string   `xml:"NextContinuationToken"`
+}
+
+// Upload stores the content read from r as the object key of bucket through a multipart upload. Each part is
+// sent with its sha256 checksum, which is verified by the server. The upload is aborted if anything fails.
+func (c *Client) Upload(bucket, key string, r io.Reader) (*UploadResult, error) {
+	partSize := c.PartSize
+	if partSize < MinPartSize {
+		partSize = MinPartSize
+	}
+	initiated := &initiateMultipartUploadResult{}
+	headers := http.Header{checksumAlgorithmHeader: []string{checksumAlgorithmSHA256}}
+	if err := c.do(http.MethodPost, bucket, key, url.Values{"uploads": []string{""}}, headers, nil, initiated); err != nil {
+		return nil, err
+	}
+
+	objectHash := sha256.New()
+	result := &UploadResult{Key: key}
+	parts := []completedPart{}
+	buffer := make([]byte, partSize)
+	for {
+		n, readErr := io.ReadFull(r, buffer)
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			c.abort(bucket, key, initiated.UploadID)
+			return nil, readErr
+		}
+		// The last part can be empty when the content is a multiple of the part size, except for an empty object
+		if n > 0 || len(parts) == 0 {
+			part, err := c.uploadPart(bucket, key, initiated.UploadID, len(parts)+1, buffer[:n])
+			if err != nil {
+				c.abort(bucket, key, initiated.UploadID)
+				return nil, err
+			}
+			parts = append(parts, *part)
+			objectHash.Write(buffer[:n])
+			result.Size += int64(n)
+		}
+		if readErr != nil {
+			break
+		}
+	}
+
+	body, err := xml.Marshal(&completeMultipartUpload{Parts: parts})
+	if err != nil {
+		c.abort(bucket, key, initiated.UploadID)
+		return nil, err
+	}
+	completed := &completeMultipartUploadResult{}
+	query := url.Values{"uploadId": []string{initiated.UploadID}}
+	if err := c.do(http.MethodPost, bucket, key, query, nil, body, completed); err != nil {
+		c.abort(bucket, key, initiated.UploadID)
+		return nil, err
+	}
+	result.ETag = completed.ETag
+	result.ServerChecksum = completed.ChecksumSHA256
+	result.Checksum = "sha256:" + hex.EncodeToString(objectHash.Sum(nil))
+	return result, nil
+}
+
+func (c *Client) uploadPart(bucket, key, uploadID string, number int, data []byte) (*completedPart, error) {
+	sum := sha256.Sum256(data)
+	checksum := base64.StdEncoding.EncodeToString(sum[:])
+	query := url.Values{"partNumber": []string{strconv.Itoa(number)}, "uploadId": []string{uploadID}}
+	headers := http.Header{checksumSHA256Header: []string{checksum}}
+	resp, err := c.request(http.MethodPut, bucket, key, query, headers, data)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+	return &completedPart{PartNumber: number, ETag: resp.Header.Get("ETag"), ChecksumSHA256: checksum}, nil
+}
+
+// abort releases the parts of a failed upload, errors are ignored as the upload failed anyway
+func (c *Client) abort(bucket, key, uploadID string) {
+	c.do(http.MethodDelete, bucket, key, url.Values{"uploadId": []string{uploadID}}, nil, nil, nil)
+}
+
+// List returns the objects of bucket whose key starts with prefix
+func (c *Client) List(bucket, prefix string) ([]Object, error) {
+	objects := []Object{}
+	token := ""
+	for {
+		query := url.Values{"list-type": []string{"2"}, "prefix": []string{prefix}}
+		if len(token) > 0 {
+			query.Set("continuation-token", token)
+		}
+		page := &listBucketResult{}
+		if err := c.do(http.MethodGet, bucket, "", query, nil, nil, page); err != nil {
+			return nil, err
+		}
+		objects = append(objects, page.Contents...)
+		if !page.IsTruncated || len(page.NextContinuationToken) == 0 {
+			return objects, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// Get returns the content of the object key of bucket, it must be closed by the caller
+func (c *Client) Get(bucket, key string) (io.ReadCloser, error) {
+	resp, err := c.request(http.MethodGet, bucket, key, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// do sends a request and decodes the XML response into out when it is not nil
+func (c *Client) do(method, bucket, key string, query url.Values, headers http.Header, body []byte, out interface{}) error {
+	resp, err := c.request(method, bucket, key, query, headers, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return xml.NewDecoder(resp.Body).Decode(out)
+}
+
+// request sends a signed request to the object storage
+func (c *Client) request(method, bucket, key string, query url.Values, headers http.Header, body []byte) (*http.Response, error) {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket
+	if len(key) > 0 {
+		u.Path += "/" + key
+	}
+	u.RawPath = escapePath(u.Path)
+	u.RawQuery = encodeQuery(query)
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	if len(body) > 0 && len(req.Header.Get("Content-Type")) == 0 {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	sign(req, body, c.credentials, c.region, c.now())
+	return c.HTTPClient.Do(req)
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	s3Err := &Error{StatusCode: resp.StatusCode}
+	if content, err := ioutil.ReadAll(resp.Body); err == nil {
+		xml.Unmarshal(content, s3Err)
+	}
+	return s3Err
+}
+
+// encodeQuery encodes the query parameters sorted by name, as expected by the signature
+func encodeQuery(query url.Values) string {
+	return strings.Replace(query.Encode(), "+", "%20", -1)
+}
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// accessReviewer creates the SubjectAccessReviews, the decision of the apiserver is set in their status
+type accessReviewer interface {
+	Create(ctx context.Context, obj runtime.Object) error
+}
+
+// roleBinding is a namespace where the role of the ServiceAccount of an instance is bound, the empty namespace
+// stands for the namespaces matching the namespaceSelector
+type roleBinding struct {
+	namespace string
+	path      *field.Path
+}
+
+// roleAuthorizer rejects the instances whose role would be bound by the operator in namespaces where the
+// requester cannot bind it, the requester would otherwise gain the role through Jenkins
+type roleAuthorizer struct {
+	reviewer accessReviewer
+}
+
+func (a *roleAuthorizer) authorize(ctx context.Context, user authenticationv1.UserInfo, obj, old runtime.Object) (field.ErrorList, error) {
+	cr := obj.(*jenkinsv1alpha1.Jenkins)
+	var oldCR *jenkinsv1alpha1.Jenkins
+	if old != nil {
+		oldCR = old.(*jenkinsv1alpha1.Jenkins)
+	}
+	errs := field.ErrorList{}
+	if cr.DeletionTimestamp != nil {
+		return errs, nil
+	}
+	role := jenkins.JenkinsRoleRef(cr)
+	for _, binding := range newRoleBindings(cr, oldCR) {
+		allowed, err := a.canBind(ctx, user, role, binding.namespace)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			continue
+		}
+		where := "in the namespace " + binding.namespace
+		if len(binding.namespace) == 0 {
+			where = "in every namespace"
+		}
+		errs = append(errs, field.Forbidden(binding.path, fmt.Sprintf("%s cannot bind the %s %s %s", user.Username, role.Kind, role.Name, where)))
+	}
+	return errs, nil
+}
+
+// newRoleBindings returns where the role of the instance is bound, and was not bound with the same role by the
+// previous instance on updates. The default role is always bound in the namespace of the instance.
+func newRoleBindings(cr, old *jenkinsv1alpha1.Jenkins) []roleBinding {
+	bindings := roleBindings(cr)
+	if old == nil || jenkins.JenkinsRoleRef(old) != jenkins.JenkinsRoleRef(cr) {
+		return bindings
+	}
+	bound := map[string]bool{}
+	for _, binding := range roleBindings(old) {
+		bound[binding.namespace] = true
+	}
+	if !reflect.DeepEqual(namespaceSelector(cr), namespaceSelector(old)) {
+		delete(bound, "")
+	}
+	added := []roleBinding{}
+	for _, binding := range bindings {
+		if !bound[binding.namespace] {
+			added = append(added, binding)
+		}
+	}
+	return added
+}
+
+func roleBindings(cr *jenkinsv1alpha1.Jenkins) []roleBinding {
+	bindings := []roleBinding{}
+	serviceAccount := cr.Spec.ServiceAccount
+	if serviceAccount == nil {
+		return bindings
+	}
+	path := field.NewPath("spec", "serviceAccount")
+	if serviceAccount.Role != nil {
+		bindings = append(bindings, roleBinding{namespace: cr.Namespace, path: path.Child("role")})
+	}
+	for i, namespace := range serviceAccount.Namespaces {
+		bindings = append(bindings, roleBinding{namespace: namespace, path: path.Child("namespaces").Index(i)})
+	}
+	if serviceAccount.NamespaceSelector != nil {
+		bindings = append(bindings, roleBinding{path: path.Child("namespaceSelector")})
+	}
+	return bindings
+}
+
+func namespaceSelector(cr *jenkinsv1alpha1.Jenkins) interface{} {
+	if cr.Spec.ServiceAccount == nil {
+		return nil
+	}
+	return cr.Spec.ServiceAccount.NamespaceSelector
+}
+
+// canBind asks the apiserver whether the user may bind the role in the namespace, or in every namespace when it
+// is empty
+func (a *roleAuthorizer) canBind(ctx context.Context, user authenticationv1.UserInfo, role rbacv1.RoleRef, namespace string) (bool, error) {
+	resource := "clusterroles"
+	if role.Kind == "Role" {
+		resource = "roles"
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "bind",
+				Group:     rbacv1.GroupName,
+				Resource:  resource,
+				Name:      role.Name,
+			},
+			User:   user.Username,
+			Groups: user.Groups,
+			UID:    user.UID,
+		},
+	}
+	if len(user.Extra) > 0 {
+		review.Spec.Extra = map[string]authorizationv1.ExtraValue{}
+		for key, value := range user.Extra {
+			review.Spec.Extra[key] = authorizationv1.ExtraValue(value)
+		}
+	}
+	if err := a.reviewer.Create(ctx, review); err != nil {
+		return false, fmt.Errorf("cannot review the access of %s to the %s %s: %v", user.Username, role.Kind, role.Name, err)
+	}
+	return review.Status.Allowed, nil
+}
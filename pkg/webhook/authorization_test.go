@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// fakeReviewer allows the requester to bind the roles except in the denied namespaces, "" standing for every
+// namespace, and records the reviewed attributes
+type fakeReviewer struct {
+	denied   map[string]bool
+	err      error
+	reviewed []authorizationv1.ResourceAttributes
+}
+
+func (r *fakeReviewer) Create(ctx context.Context, obj runtime.Object) error {
+	if r.err != nil {
+		return r.err
+	}
+	review := obj.(*authorizationv1.SubjectAccessReview)
+	r.reviewed = append(r.reviewed, *review.Spec.ResourceAttributes)
+	review.Status.Allowed = !r.denied[review.Spec.ResourceAttributes.Namespace]
+	return nil
+}
+
+func TestRoleBindingsAreAuthorized(t *testing.T) {
+	t.Run("TestDefaultRoleIsNotReviewed", func(t *testing.T) {
+		reviewer := &fakeReviewer{denied: map[string]bool{test_ns: true}}
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+
+		response := handleReviewed(t, jenkins.DiscoveredAPIs{}, reviewer, ValidateJenkinsPath, cr, nil)
+		require.True(t, response.Response.Allowed)
+		require.Empty(t, reviewer.reviewed)
+	})
+	t.Run("TestRoleOfTheTargetNamespacesIsReviewed", func(t *testing.T) {
+		reviewer := &fakeReviewer{}
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.ServiceAccount = &jenkinsv1alpha1.JenkinsServiceAccount{
+			Role:       &jenkinsv1alpha1.JenkinsRoleRef{Kind: "Role", Name: "deployer"},
+			Namespaces: []string{"staging"},
+		}
+
+		response := handleReviewed(t, jenkins.DiscoveredAPIs{}, reviewer, ValidateJenkinsPath, cr, nil)
+		require.True(t, response.Response.Allowed)
+		require.Equal(t, []authorizationv1.ResourceAttributes{
+			{Namespace: test_ns, Verb: "bind", Group: "rbac.authorization.k8s.io", Resource: "roles", Name: "deployer"},
+			{Namespace: "staging", Verb: "bind", Group: "rbac.authorization.k8s.io", Resource: "roles", Name: "deployer"},
+		}, reviewer.reviewed)
+	})
+	t.Run("TestEscalationIsRejected", func(t *testing.T) {
+		reviewer := &fakeReviewer{denied: map[string]bool{"production": true, "": true}}
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.ServiceAccount = &jenkinsv1alpha1.JenkinsServiceAccount{
+			Namespaces:        []string{"staging", "production"},
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+		}
+
+		response := handleReviewed(t, jenkins.DiscoveredAPIs{}, reviewer, ValidateJenkinsPath, cr, nil)
+		require.False(t, response.Response.Allowed)
+		require.Equal(t, int32(http.StatusForbidden), response.Response.Result.Code)
+		require.Contains(t, response.Response.Result.Message, "spec.serviceAccount.namespaces[1]: Forbidden: developer cannot bind the ClusterRole edit in the namespace production")
+		require.Contains(t, response.Response.Result.Message, "spec.serviceAccount.namespaceSelector: Forbidden: developer cannot bind the ClusterRole edit in every namespace")
+		require.NotContains(t, response.Response.Result.Message, "staging")
+	})
+	t.Run("TestOnlyTheAddedNamespacesAreReviewed", func(t *testing.T) {
+		old := mocks.JenkinsCRMock(test_ns, test_name)
+		old.Spec.ServiceAccount = &jenkinsv1alpha1.JenkinsServiceAccount{Namespaces: []string{"production"}}
+		cr := old.DeepCopy()
+		cr.Spec.ServiceAccount.Namespaces = append(cr.Spec.ServiceAccount.Namespaces, "staging")
+		reviewer := &fakeReviewer{denied: map[string]bool{"production": true}}
+
+		response := handleReviewed(t, jenkins.DiscoveredAPIs{}, reviewer, ValidateJenkinsPath, cr, old)
+		require.True(t, response.Response.Allowed)
+		require.Len(t, reviewer.reviewed, 1)
+		require.Equal(t, "staging", reviewer.reviewed[0].Namespace)
+
+		// every namespace is reviewed again when the role changes
+		cr.Spec.ServiceAccount.Role = &jenkinsv1alpha1.JenkinsRoleRef{Kind: "ClusterRole", Name: "admin"}
+		response = handleReviewed(t, jenkins.DiscoveredAPIs{}, reviewer, ValidateJenkinsPath, cr, old)
+		require.False(t, response.Response.Allowed)
+		require.Contains(t, response.Response.Result.Message, "cannot bind the ClusterRole admin in the namespace production")
+	})
+	t.Run("TestReviewFailureIsReported", func(t *testing.T) {
+		reviewer := &fakeReviewer{err: fmt.Errorf("subjectaccessreviews.authorization.k8s.io is forbidden")}
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.ServiceAccount = &jenkinsv1alpha1.JenkinsServiceAccount{Namespaces: []string{"staging"}}
+
+		response := handleReviewed(t, jenkins.DiscoveredAPIs{}, reviewer, ValidateJenkinsPath, cr, nil)
+		require.False(t, response.Response.Allowed)
+		require.Equal(t, int32(http.StatusInternalServerError), response.Response.Result.Code)
+		require.Contains(t, response.Response.Result.Message, "cannot review the access of developer to the ClusterRole edit")
+	})
+}
@@ -0,0 +1,153 @@
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// CertName and KeyName are the files of the serving certificate, as written by the OpenShift service CA
+	CertName = "tls.crt"
+	KeyName  = "tls.key"
+	// ServiceCAInjectAnnotation asks the OpenShift service CA operator to inject its bundle in a webhook configuration
+	ServiceCAInjectAnnotation = "service.beta.openshift.io/inject-cabundle"
+
+	certificateValidity = 10 * 365 * 24 * time.Hour
+)
+
+// bootstrapCertificates returns the directory holding the serving certificate of the webhook server, and the CA
+// bundle to set in the webhook configurations. The certificate generated by the OpenShift service CA is used when
+// it is mounted in certDir: its bundle is injected by the service CA operator and no bundle is returned. Otherwise
+// a self-signed CA and a serving certificate for the webhook Service are generated in a temporary directory.
+func bootstrapCertificates(certDir, service, namespace string) (string, []byte, error) {
+	if fileExists(filepath.Join(certDir, CertName)) && fileExists(filepath.Join(certDir, KeyName)) {
+		return certDir, nil, nil
+	}
+	dnsNames := []string{
+		service,
+		service + "." + namespace,
+		service + "." + namespace + ".svc",
+		service + "." + namespace + ".svc.cluster.local",
+	}
+	caPEM, certPEM, keyPEM, err := generateCertificates(dnsNames)
+	if err != nil {
+		return "", nil, err
+	}
+	dir, err := ioutil.TempDir("", "webhook-certs")
+	if err != nil {
+		return "", nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, CertName), certPEM, 0600); err != nil {
+		return "", nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, KeyName), keyPEM, 0600); err != nil {
+		return "", nil, err
+	}
+	return dir, caPEM, nil
+}
+
+// generateCertificates returns a self-signed CA, and a serving certificate for the DNS names signed by this CA
+// with its private key, PEM encoded
+func generateCertificates(dnsNames []string) ([]byte, []byte, []byte, error) {
+	now := time.Now()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	caTemplate := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: ServiceName + "-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(certificateValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	if caTemplate.SerialNumber, err = serialNumber(); err != nil {
+		return nil, nil, nil, err
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:    dnsNames,
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(certificateValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if template.SerialNumber, err = serialNumber(); err != nil {
+		return nil, nil, nil, err
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		nil
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// keyPairReloader serves the latest serving certificate, which the OpenShift service CA renews in place
+type keyPairReloader struct {
+	certFile string
+	keyFile  string
+
+	mutex   sync.Mutex
+	modTime time.Time
+	keyPair *tls.Certificate
+}
+
+// GetCertificate loads the serving certificate again when its file changed. The previous certificate is kept
+// while the new one cannot be loaded.
+func (k *keyPairReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	info, err := os.Stat(k.certFile)
+	if err == nil && (k.keyPair == nil || !info.ModTime().Equal(k.modTime)) {
+		var keyPair tls.Certificate
+		if keyPair, err = tls.LoadX509KeyPair(k.certFile, k.keyFile); err == nil {
+			k.keyPair, k.modTime = &keyPair, info.ModTime()
+		}
+	}
+	if k.keyPair == nil {
+		return nil, err
+	}
+	return k.keyPair, nil
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestBootstrapCertificates(t *testing.T) {
+	t.Run("TestSelfSignedCertificateIsGenerated", func(t *testing.T) {
+		emptyDir, err := ioutil.TempDir("", "empty")
+		require.NoError(t, err)
+		defer os.RemoveAll(emptyDir)
+
+		dir, caBundle, err := bootstrapCertificates(emptyDir, ServiceName, test_ns)
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		require.NotEqual(t, emptyDir, dir)
+
+		roots := x509.NewCertPool()
+		require.True(t, roots.AppendCertsFromPEM(caBundle))
+		certPEM, err := ioutil.ReadFile(filepath.Join(dir, CertName))
+		require.NoError(t, err)
+		block, _ := pem.Decode(certPEM)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		_, err = cert.Verify(x509.VerifyOptions{DNSName: ServiceName + "." + test_ns + ".svc", Roots: roots})
+		require.NoError(t, err)
+
+		keyPair := &keyPairReloader{certFile: filepath.Join(dir, CertName), keyFile: filepath.Join(dir, KeyName)}
+		loaded, err := keyPair.GetCertificate(nil)
+		require.NoError(t, err)
+		require.Equal(t, block.Bytes, loaded.Certificate[0])
+
+		// the certificate of the service CA is used as is
+		serviceCADir, caBundle, err := bootstrapCertificates(dir, ServiceName, test_ns)
+		require.NoError(t, err)
+		require.Equal(t, dir, serviceCADir)
+		require.Nil(t, caBundle)
+	})
+}
+
+func TestEnsureWebhookConfigurations(t *testing.T) {
+	webhooks := newWebhooks(jenkins.DiscoveredAPIs{}, &fakeReviewer{})
+	c := mocks.NewFakeClient(mocks.NewScheme())
+	key := types.NamespacedName{Name: ConfigurationName}
+
+	require.NoError(t, ensureWebhookConfigurations(c, webhooks, test_ns, []byte("bundle")))
+	validating := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{}
+	require.NoError(t, c.Get(context.TODO(), key, validating))
+	require.Len(t, validating.Webhooks, 2)
+	service := validating.Webhooks[0].ClientConfig.Service
+	require.Equal(t, test_ns+"/"+ServiceName+ValidateJenkinsPath, service.Namespace+"/"+service.Name+*service.Path)
+	require.Equal(t, []byte("bundle"), validating.Webhooks[0].ClientConfig.CABundle)
+	require.NotContains(t, validating.Annotations, ServiceCAInjectAnnotation)
+
+	// the bundle injected by the service CA is kept
+	require.NoError(t, ensureWebhookConfigurations(c, webhooks, test_ns, nil))
+	mutating := &admissionregistrationv1beta1.MutatingWebhookConfiguration{}
+	require.NoError(t, c.Get(context.TODO(), key, mutating))
+	require.Len(t, mutating.Webhooks, 2)
+	require.Equal(t, MutateJenkinsPath, *mutating.Webhooks[0].ClientConfig.Service.Path)
+	require.Equal(t, []byte("bundle"), mutating.Webhooks[0].ClientConfig.CABundle)
+	require.Equal(t, "true", mutating.Annotations[ServiceCAInjectAnnotation])
+}
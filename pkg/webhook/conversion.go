@@ -0,0 +1,323 @@
+package webhook
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	jenkinsv1beta1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ConvertPath is the path of the conversion webhook of the custom resources
+	ConvertPath = "/convert"
+)
+
+// ConvertedCRDs are the custom resource definitions whose versions are converted by the webhook server
+var ConvertedCRDs = []string{"jenkins.jenkins.dev", "jenkinsimages.jenkins.dev"}
+
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition"}
+
+// conversionReview is the apiextensions.k8s.io/v1beta1 ConversionReview sent by the API server, the
+// apiextensions API is not a dependency of the operator
+type conversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Request         *conversionRequest  `json:"request,omitempty"`
+	Response        *conversionResponse `json:"response,omitempty"`
+}
+
+type conversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+type conversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+// conversionHandler serves the ConversionReviews sent by the API server for the Jenkins and JenkinsImage resources
+type conversionHandler struct{}
+
+func (h *conversionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	review := &conversionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil || review.Request == nil {
+		http.Error(w, fmt.Sprintf("cannot decode the ConversionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	review.Response = convertObjects(review.Request)
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		log.Error(err, "Cannot write the ConversionReview response")
+	}
+}
+
+// convertObjects converts the objects of the request to the desired version. The whole request fails when one
+// of the objects cannot be converted.
+func convertObjects(request *conversionRequest) *conversionResponse {
+	response := &conversionResponse{
+		UID:              request.UID,
+		ConvertedObjects: []runtime.RawExtension{},
+		Result:           metav1.Status{Status: metav1.StatusSuccess},
+	}
+	for _, obj := range request.Objects {
+		converted, err := convertObject(obj.Raw, request.DesiredAPIVersion)
+		if err != nil {
+			return &conversionResponse{
+				UID:              request.UID,
+				ConvertedObjects: []runtime.RawExtension{},
+				Result:           metav1.Status{Status: metav1.StatusFailure, Message: err.Error()},
+			}
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	return response
+}
+
+// convertObject converts a serialized Jenkins or JenkinsImage to the desired version, through v1alpha1
+func convertObject(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+	switch typeMeta.Kind {
+	case "Jenkins":
+		hub, spoke := &jenkinsv1alpha1.Jenkins{}, &jenkinsv1beta1.Jenkins{}
+		return convertVersions(raw, typeMeta.APIVersion, desiredAPIVersion, hub, spoke,
+			func() error { return spoke.ConvertTo(hub) }, func() error { return spoke.ConvertFrom(hub) })
+	case "JenkinsImage":
+		hub, spoke := &jenkinsv1alpha1.JenkinsImage{}, &jenkinsv1beta1.JenkinsImage{}
+		return convertVersions(raw, typeMeta.APIVersion, desiredAPIVersion, hub, spoke,
+			func() error { return spoke.ConvertTo(hub) }, func() error { return spoke.ConvertFrom(hub) })
+	}
+	return nil, fmt.Errorf("cannot convert the unknown kind %s", typeMeta.Kind)
+}
+
+// convertVersions decodes raw in the v1alpha1 hub, from the v1beta1 spoke when the object is in v1beta1, and
+// encodes the hub or the spoke converted from the hub in the desired version. The fields of raw which are not
+// decoded are kept in the fields annotation of its version, the fields kept in the annotation of the desired
+// version are restored.
+func convertVersions(raw []byte, apiVersion, desiredAPIVersion string, hub, spoke runtime.Object, toHub, fromHub func() error) ([]byte, error) {
+	var source, converted runtime.Object
+	switch apiVersion {
+	case jenkinsv1alpha1.SchemeGroupVersion.String():
+		source = hub
+	case jenkinsv1beta1.SchemeGroupVersion.String():
+		source = spoke
+	default:
+		return nil, fmt.Errorf("cannot convert from the unknown version %s", apiVersion)
+	}
+	switch desiredAPIVersion {
+	case jenkinsv1alpha1.SchemeGroupVersion.String():
+		converted = hub
+	case jenkinsv1beta1.SchemeGroupVersion.String():
+		converted = spoke
+	default:
+		return nil, fmt.Errorf("cannot convert to the unknown version %s", desiredAPIVersion)
+	}
+
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+	restored, err := popFields(object, fieldsAnnotations[desiredAPIVersion])
+	if err != nil {
+		return nil, err
+	}
+	if err := convertJSON(object, source); err != nil {
+		return nil, err
+	}
+	decoded := map[string]interface{}{}
+	if err := convertJSON(source, &decoded); err != nil {
+		return nil, err
+	}
+	unknown := unknownFields(object, decoded)
+
+	if source == spoke {
+		if err := toHub(); err != nil {
+			return nil, err
+		}
+	}
+	if converted == spoke {
+		if err := fromHub(); err != nil {
+			return nil, err
+		}
+	}
+	result := map[string]interface{}{}
+	if err := convertJSON(converted, &result); err != nil {
+		return nil, err
+	}
+	if len(unknown) > 0 {
+		if err := setFields(result, fieldsAnnotations[apiVersion], unknown); err != nil {
+			return nil, err
+		}
+	}
+	mergeFields(result, restored)
+	return json.Marshal(result)
+}
+
+// fieldsAnnotations are the annotations keeping the unknown fields of the objects of each version
+var fieldsAnnotations = map[string]string{
+	jenkinsv1alpha1.SchemeGroupVersion.String(): jenkinsv1beta1.V1alpha1FieldsAnnotation,
+	jenkinsv1beta1.SchemeGroupVersion.String():  jenkinsv1beta1.V1beta1FieldsAnnotation,
+}
+
+func convertJSON(in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// unknownFields returns the fields of object which are missing from decoded, the object decoded in its type and
+// encoded again. The empty values are skipped, the optional fields of the types are omitted when empty.
+func unknownFields(object, decoded map[string]interface{}) map[string]interface{} {
+	unknown := map[string]interface{}{}
+	for key, value := range object {
+		decodedValue, found := decoded[key]
+		if !found {
+			if !isEmpty(value) {
+				unknown[key] = value
+			}
+			continue
+		}
+		valueMap, isMap := value.(map[string]interface{})
+		decodedMap, isDecodedMap := decodedValue.(map[string]interface{})
+		if isMap && isDecodedMap {
+			if fields := unknownFields(valueMap, decodedMap); len(fields) > 0 {
+				unknown[key] = fields
+			}
+		}
+	}
+	return unknown
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// mergeFields sets the fields in object, the values already set in object are kept
+func mergeFields(object, fields map[string]interface{}) {
+	for key, value := range fields {
+		objectValue, found := object[key]
+		if !found {
+			object[key] = value
+			continue
+		}
+		objectMap, isMap := objectValue.(map[string]interface{})
+		valueMap, isValueMap := value.(map[string]interface{})
+		if isMap && isValueMap {
+			mergeFields(objectMap, valueMap)
+		}
+	}
+}
+
+// setFields keeps the fields in the annotation of object
+func setFields(object map[string]interface{}, annotation string, fields map[string]interface{}) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	annotations, _, err := unstructured.NestedStringMap(object, "metadata", "annotations")
+	if err != nil {
+		return err
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[annotation] = string(data)
+	return unstructured.SetNestedStringMap(object, annotations, "metadata", "annotations")
+}
+
+// popFields decodes the fields kept in the annotation of object and removes it, it returns nil when the
+// annotation is not set
+func popFields(object map[string]interface{}, annotation string) (map[string]interface{}, error) {
+	annotations, _, err := unstructured.NestedStringMap(object, "metadata", "annotations")
+	if err != nil {
+		return nil, err
+	}
+	data, found := annotations[annotation]
+	if !found {
+		return nil, nil
+	}
+	delete(annotations, annotation)
+	if len(annotations) == 0 {
+		unstructured.RemoveNestedField(object, "metadata", "annotations")
+	} else if err := unstructured.SetNestedStringMap(object, annotations, "metadata", "annotations"); err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return nil, fmt.Errorf("cannot decode the %s annotation: %v", annotation, err)
+	}
+	return fields, nil
+}
+
+// ensureConversionWebhooks points the conversion of the custom resource definitions to the webhook Service.
+// Without a CA bundle, the bundle injected by the OpenShift service CA operator is kept.
+func ensureConversionWebhooks(c client.Client, namespace string, caBundle []byte) error {
+	for _, name := range ConvertedCRDs {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(crdGVK)
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: name}, crd); err != nil {
+			return err
+		}
+		bundle := base64.StdEncoding.EncodeToString(caBundle)
+		if caBundle == nil {
+			bundle, _, _ = unstructured.NestedString(crd.Object, "spec", "conversion", "webhookClientConfig", "caBundle")
+		}
+		clientConfig := map[string]interface{}{
+			"service": map[string]interface{}{"namespace": namespace, "name": ServiceName, "path": ConvertPath},
+		}
+		if len(bundle) > 0 {
+			clientConfig["caBundle"] = bundle
+		}
+		conversion := map[string]interface{}{"strategy": "Webhook", "webhookClientConfig": clientConfig}
+		if err := unstructured.SetNestedField(crd.Object, conversion, "spec", "conversion"); err != nil {
+			return err
+		}
+		annotations := crd.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		delete(annotations, ServiceCAInjectAnnotation)
+		if caBundle == nil {
+			annotations[ServiceCAInjectAnnotation] = "true"
+		}
+		crd.SetAnnotations(annotations)
+		if err := c.Update(context.TODO(), crd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
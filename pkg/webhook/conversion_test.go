@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	jenkinsv1beta1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// review sends a ConversionReview of the objects to the conversion webhook
+func review(t *testing.T, desiredAPIVersion string, objs ...runtime.Object) *conversionResponse {
+	raws := [][]byte{}
+	for _, obj := range objs {
+		raw, err := json.Marshal(obj)
+		require.NoError(t, err)
+		raws = append(raws, raw)
+	}
+	return reviewRaw(t, desiredAPIVersion, raws...)
+}
+
+// reviewRaw sends a ConversionReview of the serialized objects to the conversion webhook
+func reviewRaw(t *testing.T, desiredAPIVersion string, raws ...[]byte) *conversionResponse {
+	request := &conversionRequest{UID: "uid", DesiredAPIVersion: desiredAPIVersion}
+	for _, raw := range raws {
+		request.Objects = append(request.Objects, runtime.RawExtension{Raw: raw})
+	}
+	body, err := json.Marshal(&conversionReview{Request: request})
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	(&conversionHandler{}).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, ConvertPath, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	response := &conversionReview{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), response))
+	require.Equal(t, types.UID("uid"), response.Response.UID)
+	return response.Response
+}
+
+func newJenkinsV1beta1(spec jenkinsv1beta1.JenkinsSpec) *jenkinsv1beta1.Jenkins {
+	return &jenkinsv1beta1.Jenkins{
+		TypeMeta:   metav1.TypeMeta{APIVersion: jenkinsv1beta1.SchemeGroupVersion.String(), Kind: "Jenkins"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name},
+		Spec:       spec,
+	}
+}
+
+func TestConversionWebhook(t *testing.T) {
+	t.Run("TestObjectsAreConverted", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.TypeMeta = metav1.TypeMeta{APIVersion: jenkinsv1alpha1.SchemeGroupVersion.String(), Kind: "Jenkins"}
+		cr.Spec.UseDeploymentConfig = true
+		image := &jenkinsv1alpha1.JenkinsImage{
+			TypeMeta:   metav1.TypeMeta{APIVersion: jenkinsv1alpha1.SchemeGroupVersion.String(), Kind: "JenkinsImage"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name},
+			Spec:       jenkinsv1alpha1.JenkinsImageSpec{Plugins: []jenkinsv1alpha1.JenkinsPlugin{{Name: "git"}}},
+		}
+
+		response := review(t, jenkinsv1beta1.SchemeGroupVersion.String(), cr, image)
+		require.Equal(t, metav1.StatusSuccess, response.Result.Status)
+		require.Len(t, response.ConvertedObjects, 2)
+		converted := &jenkinsv1beta1.Jenkins{}
+		require.NoError(t, json.Unmarshal(response.ConvertedObjects[0].Raw, converted))
+		require.Equal(t, jenkinsv1beta1.SchemeGroupVersion.String(), converted.APIVersion)
+		require.Equal(t, jenkinsv1beta1.JenkinsWorkloadDeploymentConfig, converted.Spec.Workload.Kind)
+		require.NotContains(t, converted.Annotations, jenkinsv1beta1.V1alpha1FieldsAnnotation)
+		convertedImage := &jenkinsv1beta1.JenkinsImage{}
+		require.NoError(t, json.Unmarshal(response.ConvertedObjects[1].Raw, convertedImage))
+		require.Equal(t, []jenkinsv1beta1.JenkinsPlugin{{Name: "git"}}, convertedImage.Spec.Plugins)
+
+		// and back
+		response = review(t, jenkinsv1alpha1.SchemeGroupVersion.String(), converted)
+		roundTripped := &jenkinsv1alpha1.Jenkins{}
+		require.NoError(t, json.Unmarshal(response.ConvertedObjects[0].Raw, roundTripped))
+		require.Equal(t, cr.Spec, roundTripped.Spec)
+	})
+	t.Run("TestUnknownFieldsAreKept", func(t *testing.T) {
+		raw := []byte(`{
+			"apiVersion": "jenkins.dev/v1alpha1",
+			"kind": "Jenkins",
+			"metadata": {"namespace": "` + test_ns + `", "name": "` + test_name + `", "annotations": {"team": "ci"}},
+			"spec": {
+				"useDeploymentConfig": true,
+				"persistence": {"enabled": true, "size": "1Gi", "snapshotClass": "csi"},
+				"plugins": [{"name": "git"}]
+			}
+		}`)
+
+		response := reviewRaw(t, jenkinsv1beta1.SchemeGroupVersion.String(), raw)
+		require.Equal(t, metav1.StatusSuccess, response.Result.Status)
+		converted := &jenkinsv1beta1.Jenkins{}
+		require.NoError(t, json.Unmarshal(response.ConvertedObjects[0].Raw, converted))
+		require.JSONEq(t, `{"spec": {"persistence": {"snapshotClass": "csi"}, "plugins": [{"name": "git"}]}}`,
+			converted.Annotations[jenkinsv1beta1.V1alpha1FieldsAnnotation])
+
+		// and back
+		response = reviewRaw(t, jenkinsv1alpha1.SchemeGroupVersion.String(), response.ConvertedObjects[0].Raw)
+		require.Equal(t, metav1.StatusSuccess, response.Result.Status)
+		roundTripped := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(response.ConvertedObjects[0].Raw, &roundTripped))
+		annotations, _, _ := unstructured.NestedStringMap(roundTripped, "metadata", "annotations")
+		require.Equal(t, map[string]string{"team": "ci"}, annotations)
+		snapshotClass, _, _ := unstructured.NestedString(roundTripped, "spec", "persistence", "snapshotClass")
+		require.Equal(t, "csi", snapshotClass)
+		plugins, _, _ := unstructured.NestedSlice(roundTripped, "spec", "plugins")
+		require.Equal(t, []interface{}{map[string]interface{}{"name": "git"}}, plugins)
+		useDeploymentConfig, _, _ := unstructured.NestedBool(roundTripped, "spec", "useDeploymentConfig")
+		require.True(t, useDeploymentConfig)
+	})
+	t.Run("TestUnknownVersionFails", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.TypeMeta = metav1.TypeMeta{APIVersion: "jenkins.dev/v2", Kind: "Jenkins"}
+
+		response := review(t, jenkinsv1beta1.SchemeGroupVersion.String(), cr)
+		require.Equal(t, metav1.StatusFailure, response.Result.Status)
+		require.Contains(t, response.Result.Message, "jenkins.dev/v2")
+		require.Empty(t, response.ConvertedObjects)
+	})
+}
+
+func TestV1beta1AdmissionWebhooks(t *testing.T) {
+	t.Run("TestDefaultsArePatchedInV1beta1", func(t *testing.T) {
+		cr := newJenkinsV1beta1(jenkinsv1beta1.JenkinsSpec{
+			Workload:    jenkinsv1beta1.JenkinsWorkload{Kind: jenkinsv1beta1.JenkinsWorkloadDeploymentConfig},
+			Persistence: jenkinsv1beta1.JenkinsPersistence{Enabled: true},
+		})
+
+		response := handle(t, jenkins.DiscoveredAPIs{}, MutateJenkinsPath, cr, nil)
+		require.True(t, response.Response.Allowed)
+		require.Equal(t, map[string]interface{}{
+			"/spec/persistence/size": jenkins.JenkinsPvcDefaultSize,
+			"/spec/workload/kind":    nil,
+		}, patchedValues(t, response))
+	})
+	t.Run("TestInvalidV1beta1IsRejected", func(t *testing.T) {
+		cr := newJenkinsV1beta1(jenkinsv1beta1.JenkinsSpec{
+			Persistence: jenkinsv1beta1.JenkinsPersistence{Enabled: true, Size: "ten gigabytes"},
+		})
+
+		response := handle(t, jenkins.DiscoveredAPIs{}, ValidateJenkinsPath, cr, nil)
+		require.False(t, response.Response.Allowed)
+		require.Contains(t, response.Response.Result.Message, "spec.persistence.size")
+	})
+}
+
+func TestEnsureConversionWebhooks(t *testing.T) {
+	objs := []runtime.Object{}
+	for _, name := range ConvertedCRDs {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(crdGVK)
+		crd.SetName(name)
+		objs = append(objs, crd)
+	}
+	c := mocks.NewFakeClient(mocks.NewScheme(), objs...)
+
+	require.NoError(t, ensureConversionWebhooks(c, test_ns, []byte("bundle")))
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(crdGVK)
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: ConvertedCRDs[0]}, crd))
+	clientConfig, _, _ := unstructured.NestedMap(crd.Object, "spec", "conversion", "webhookClientConfig")
+	require.Equal(t, map[string]interface{}{
+		"service":  map[string]interface{}{"namespace": test_ns, "name": ServiceName, "path": ConvertPath},
+		"caBundle": base64.StdEncoding.EncodeToString([]byte("bundle")),
+	}, clientConfig)
+	require.NotContains(t, crd.GetAnnotations(), ServiceCAInjectAnnotation)
+
+	// the bundle injected by the service CA is kept
+	require.NoError(t, ensureConversionWebhooks(c, test_ns, nil))
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: ConvertedCRDs[1]}, crd))
+	bundle, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "webhookClientConfig", "caBundle")
+	require.Equal(t, base64.StdEncoding.EncodeToString([]byte("bundle")), bundle)
+	require.Equal(t, "true", crd.GetAnnotations()[ServiceCAInjectAnnotation])
+}
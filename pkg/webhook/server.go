@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	webhooktypes "sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+// Server serves the admission and conversion webhooks over TLS. It is run by the manager and registers the
+// webhooks in the mutating and validating webhook configurations and in the custom resource definitions when it
+// starts.
+type Server struct {
+	Options
+	Config   *rest.Config
+	Scheme   *runtime.Scheme
+	Webhooks []*admission.Webhook
+}
+
+var _ manager.Runnable = &Server{}
+
+// Start serves the webhooks until the stop channel is closed
+func (s *Server) Start(stop <-chan struct{}) error {
+	certDir, caBundle, err := bootstrapCertificates(s.CertDir, ServiceName, s.Namespace)
+	if err != nil {
+		return fmt.Errorf("cannot bootstrap the certificates of the webhook server: %v", err)
+	}
+	// The cache of the manager is not started yet, the configurations are cluster scoped anyway
+	c, err := client.New(s.Config, client.Options{Scheme: s.Scheme})
+	if err != nil {
+		return err
+	}
+	if err := ensureWebhookConfigurations(c, s.Webhooks, s.Namespace, caBundle); err != nil {
+		return fmt.Errorf("cannot register the webhooks: %v", err)
+	}
+	if err := ensureConversionWebhooks(c, s.Namespace, caBundle); err != nil {
+		return fmt.Errorf("cannot register the conversion webhook: %v", err)
+	}
+
+	keyPair := &keyPairReloader{certFile: filepath.Join(certDir, CertName), keyFile: filepath.Join(certDir, KeyName)}
+	if _, err := keyPair.GetCertificate(nil); err != nil {
+		return fmt.Errorf("cannot load the certificate of the webhook server: %v", err)
+	}
+	mux := http.NewServeMux()
+	for _, webhook := range s.Webhooks {
+		mux.Handle(webhook.GetPath(), webhook.Handler())
+	}
+	mux.Handle(ConvertPath, &conversionHandler{})
+	server := &http.Server{
+		Addr:      fmt.Sprintf(":%d", s.Port),
+		Handler:   mux,
+		TLSConfig: &tls.Config{GetCertificate: keyPair.GetCertificate},
+	}
+	errs := make(chan error, 1)
+	go func() {
+		log.Info("Starting the webhook server", "port", s.Port, "certDir", certDir)
+		errs <- server.ListenAndServeTLS("", "")
+	}()
+	select {
+	case <-stop:
+		return server.Shutdown(context.TODO())
+	case err := <-errs:
+		return err
+	}
+}
+
+// ensureWebhookConfigurations creates or updates the webhook configurations pointing to the webhook Service.
+// Without a CA bundle, the bundle injected by the OpenShift service CA operator is kept.
+func ensureWebhookConfigurations(c client.Client, webhooks []*admission.Webhook, namespace string, caBundle []byte) error {
+	annotations := map[string]string{}
+	if caBundle == nil {
+		annotations[ServiceCAInjectAnnotation] = "true"
+	}
+	key := types.NamespacedName{Name: ConfigurationName}
+
+	mutating := &admissionregistrationv1beta1.MutatingWebhookConfiguration{}
+	err := c.Get(context.TODO(), key, mutating)
+	if err != nil && !kubeerrors.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+	mutating.ObjectMeta = mergeConfigurationMeta(mutating.ObjectMeta, annotations)
+	mutating.Webhooks = webhookEntries(webhooks, webhooktypes.WebhookTypeMutating, namespace, caBundle, mutating.Webhooks)
+	if err := createOrUpdate(c, mutating, found); err != nil {
+		return err
+	}
+
+	validating := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{}
+	err = c.Get(context.TODO(), key, validating)
+	if err != nil && !kubeerrors.IsNotFound(err) {
+		return err
+	}
+	found = err == nil
+	validating.ObjectMeta = mergeConfigurationMeta(validating.ObjectMeta, annotations)
+	validating.Webhooks = webhookEntries(webhooks, webhooktypes.WebhookTypeValidating, namespace, caBundle, validating.Webhooks)
+	return createOrUpdate(c, validating, found)
+}
+
+func createOrUpdate(c client.Client, obj runtime.Object, found bool) error {
+	if found {
+		return c.Update(context.TODO(), obj)
+	}
+	return c.Create(context.TODO(), obj)
+}
+
+func mergeConfigurationMeta(meta metav1.ObjectMeta, annotations map[string]string) metav1.ObjectMeta {
+	meta.Name = ConfigurationName
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	delete(meta.Annotations, ServiceCAInjectAnnotation)
+	for key, value := range annotations {
+		meta.Annotations[key] = value
+	}
+	return meta
+}
+
+// webhookEntries returns the entries of the webhook configuration of the given type. The CA bundle of the
+// existing entries is kept when no bundle is given.
+func webhookEntries(webhooks []*admission.Webhook, webhookType webhooktypes.WebhookType, namespace string, caBundle []byte,
+	existing []admissionregistrationv1beta1.Webhook) []admissionregistrationv1beta1.Webhook {
+	existingBundles := map[string][]byte{}
+	for _, entry := range existing {
+		existingBundles[entry.Name] = entry.ClientConfig.CABundle
+	}
+	entries := []admissionregistrationv1beta1.Webhook{}
+	for _, webhook := range webhooks {
+		if webhook.GetType() != webhookType {
+			continue
+		}
+		path := webhook.GetPath()
+		entry := admissionregistrationv1beta1.Webhook{
+			Name:          webhook.GetName(),
+			Rules:         webhook.Rules,
+			FailurePolicy: webhook.FailurePolicy,
+			ClientConfig: admissionregistrationv1beta1.WebhookClientConfig{
+				Service:  &admissionregistrationv1beta1.ServiceReference{Namespace: namespace, Name: ServiceName, Path: &path},
+				CABundle: caBundle,
+			},
+		}
+		if caBundle == nil {
+			entry.ClientConfig.CABundle = existingBundles[entry.Name]
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,254 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	jenkinsv1beta1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1beta1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkinsimage"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+const (
+	// Paths served by the webhook server
+	MutateJenkinsPath        = "/mutate-jenkins"
+	ValidateJenkinsPath      = "/validate-jenkins"
+	MutateJenkinsImagePath   = "/mutate-jenkinsimages"
+	ValidateJenkinsImagePath = "/validate-jenkinsimages"
+
+	// DefaultPort is the port of the webhook server in the operator pod
+	DefaultPort = 9443
+	// DefaultCertDir is where the serving certificate generated by the OpenShift service CA is mounted
+	DefaultCertDir = "/etc/webhook/certs"
+	// ServiceName is the name of the Service in front of the webhook server
+	ServiceName = "openshift-jenkins-operator-webhook"
+	// ConfigurationName is the name of the mutating and validating webhook configurations
+	ConfigurationName = "openshift-jenkins-operator"
+)
+
+var log = logf.Log.WithName("webhook")
+
+// Options configures the webhook server
+type Options struct {
+	// Port is the port of the webhook server, 0 disables it
+	Port int32
+	// CertDir holds tls.crt and tls.key, they are generated when the directory does not contain them
+	CertDir string
+	// Namespace is the namespace of the operator and of the webhook Service
+	Namespace string
+}
+
+// AddToManager adds the server of the defaulting, validating and conversion webhooks of Jenkins and JenkinsImage
+// to the manager
+func AddToManager(mgr manager.Manager, options Options) error {
+	if options.Port == 0 {
+		log.Info("The webhook server is disabled")
+		return nil
+	}
+	webhooks := newWebhooks(jenkins.VerifyOpenshiftAPIs(), mgr.GetClient())
+	for _, webhook := range webhooks {
+		if err := webhook.Validate(); err != nil {
+			return err
+		}
+	}
+	return mgr.Add(&Server{
+		Options:  options,
+		Config:   mgr.GetConfig(),
+		Scheme:   mgr.GetScheme(),
+		Webhooks: webhooks,
+	})
+}
+
+// newWebhooks returns the webhooks served for the Jenkins and JenkinsImage resources, in every version. The
+// reviewer checks that the requesters may bind the role of the ServiceAccount of the Jenkins instances.
+func newWebhooks(apis jenkins.DiscoveredAPIs, reviewer accessReviewer) []*admission.Webhook {
+	failurePolicy := admissionregistrationv1beta1.Fail
+	rules := func(resource string) []admissionregistrationv1beta1.RuleWithOperations {
+		return []admissionregistrationv1beta1.RuleWithOperations{{
+			Operations: []admissionregistrationv1beta1.OperationType{admissionregistrationv1beta1.Create, admissionregistrationv1beta1.Update},
+			Rule: admissionregistrationv1beta1.Rule{
+				APIGroups:   []string{jenkinsv1alpha1.SchemeGroupVersion.Group},
+				APIVersions: []string{jenkinsv1alpha1.SchemeGroupVersion.Version, jenkinsv1beta1.SchemeGroupVersion.Version},
+				Resources:   []string{resource},
+			},
+		}}
+	}
+	newJenkins := func() runtime.Object { return &jenkinsv1alpha1.Jenkins{} }
+	newJenkinsImage := func() runtime.Object { return &jenkinsv1alpha1.JenkinsImage{} }
+	return []*admission.Webhook{
+		{
+			Name:          "mutate.jenkins.jenkins.dev",
+			Type:          types.WebhookTypeMutating,
+			Path:          MutateJenkinsPath,
+			Rules:         rules("jenkins"),
+			FailurePolicy: &failurePolicy,
+			Handlers: []admission.Handler{&defaulter{newObject: newJenkins, setDefaults: func(obj runtime.Object) {
+				jenkins.SetJenkinsDefaults(obj.(*jenkinsv1alpha1.Jenkins), apis)
+			}}},
+		},
+		{
+			Name:          "validate.jenkins.jenkins.dev",
+			Type:          types.WebhookTypeValidating,
+			Path:          ValidateJenkinsPath,
+			Rules:         rules("jenkins"),
+			FailurePolicy: &failurePolicy,
+			Handlers: []admission.Handler{&validator{newObject: newJenkins, validate: validateJenkins,
+				authorize: (&roleAuthorizer{reviewer: reviewer}).authorize}},
+		},
+		{
+			Name:          "mutate.jenkinsimages.jenkins.dev",
+			Type:          types.WebhookTypeMutating,
+			Path:          MutateJenkinsImagePath,
+			Rules:         rules("jenkinsimages"),
+			FailurePolicy: &failurePolicy,
+			Handlers: []admission.Handler{&defaulter{newObject: newJenkinsImage, setDefaults: func(obj runtime.Object) {
+				jenkinsimage.SetJenkinsImageDefaults(obj.(*jenkinsv1alpha1.JenkinsImage))
+			}}},
+		},
+		{
+			Name:          "validate.jenkinsimages.jenkins.dev",
+			Type:          types.WebhookTypeValidating,
+			Path:          ValidateJenkinsImagePath,
+			Rules:         rules("jenkinsimages"),
+			FailurePolicy: &failurePolicy,
+			Handlers:      []admission.Handler{&validator{newObject: newJenkinsImage, validate: validateJenkinsImage}},
+		},
+	}
+}
+
+// defaulter patches the object of the request with the defaults of its kind. The defaults are set on v1alpha1,
+// the patch applies to the version of the request.
+type defaulter struct {
+	newObject   func() runtime.Object
+	setDefaults func(runtime.Object)
+}
+
+func (d *defaulter) Handle(ctx context.Context, req atypes.Request) atypes.Response {
+	apiVersion := requestAPIVersion(req)
+	obj := d.newObject()
+	if err := decodeHub(req.AdmissionRequest.Object.Raw, apiVersion, obj); err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+	defaulted := obj.DeepCopyObject()
+	d.setDefaults(defaulted)
+	if apiVersion == jenkinsv1alpha1.SchemeGroupVersion.String() {
+		return admission.PatchResponse(obj, defaulted)
+	}
+	original, err := encodeVersion(obj, apiVersion)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusInternalServerError, err)
+	}
+	current, err := encodeVersion(defaulted, apiVersion)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponse(original, current)
+}
+
+// validator rejects the objects which cannot be reconciled, and the valid ones the requester is not allowed to
+// create when authorize is set. The previous object is passed on updates. Both are validated in v1alpha1.
+type validator struct {
+	newObject func() runtime.Object
+	validate  func(obj, old runtime.Object) field.ErrorList
+	authorize func(ctx context.Context, user authenticationv1.UserInfo, obj, old runtime.Object) (field.ErrorList, error)
+}
+
+func (v *validator) Handle(ctx context.Context, req atypes.Request) atypes.Response {
+	apiVersion := requestAPIVersion(req)
+	obj := v.newObject()
+	if err := decodeHub(req.AdmissionRequest.Object.Raw, apiVersion, obj); err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+	var old runtime.Object
+	if req.AdmissionRequest.Operation == admissionv1beta1.Update {
+		old = v.newObject()
+		if err := decodeHub(req.AdmissionRequest.OldObject.Raw, apiVersion, old); err != nil {
+			return admission.ErrorResponse(http.StatusBadRequest, fmt.Errorf("cannot decode the previous object: %v", err))
+		}
+	}
+	if errs := v.validate(obj, old); len(errs) > 0 {
+		return admission.ErrorResponse(http.StatusUnprocessableEntity, errs.ToAggregate())
+	}
+	if v.authorize != nil {
+		errs, err := v.authorize(ctx, req.AdmissionRequest.UserInfo, obj, old)
+		if err != nil {
+			return admission.ErrorResponse(http.StatusInternalServerError, err)
+		}
+		if len(errs) > 0 {
+			return admission.ErrorResponse(http.StatusForbidden, errs.ToAggregate())
+		}
+	}
+	return admission.ValidationResponse(true, "")
+}
+
+func requestAPIVersion(req atypes.Request) string {
+	return schema.GroupVersion{Group: req.AdmissionRequest.Kind.Group, Version: req.AdmissionRequest.Kind.Version}.String()
+}
+
+// decodeHub decodes raw, an object of the given version, in obj of version v1alpha1
+func decodeHub(raw []byte, apiVersion string, obj runtime.Object) error {
+	if apiVersion != jenkinsv1alpha1.SchemeGroupVersion.String() {
+		var err error
+		if raw, err = convertObject(raw, jenkinsv1alpha1.SchemeGroupVersion.String()); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(raw, obj)
+}
+
+// encodeVersion converts obj of version v1alpha1 to the given version
+func encodeVersion(obj runtime.Object, apiVersion string) (runtime.Object, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	if raw, err = convertObject(raw, apiVersion); err != nil {
+		return nil, err
+	}
+	converted := &unstructured.Unstructured{}
+	return converted, converted.UnmarshalJSON(raw)
+}
+
+// validateJenkins checks a new instance, or the changes of the spec of an existing one. Changes of the metadata
+// only, such as the finalizer set by the operator, are allowed on instances which are invalid or being deleted.
+func validateJenkins(obj, old runtime.Object) field.ErrorList {
+	cr := obj.(*jenkinsv1alpha1.Jenkins)
+	if old == nil {
+		if len(cr.Name) == 0 {
+			// The name is generated after the admission, the derived names are checked with the longest one
+			cr = cr.DeepCopy()
+			cr.Name = cr.GenerateName + "xxxxx"
+		}
+		return jenkins.ValidateJenkins(cr)
+	}
+	oldCR := old.(*jenkinsv1alpha1.Jenkins)
+	if cr.DeletionTimestamp != nil || reflect.DeepEqual(cr.Spec, oldCR.Spec) {
+		return nil
+	}
+	return append(jenkins.ValidateJenkins(cr), jenkins.ValidateJenkinsUpdate(cr, oldCR)...)
+}
+
+// validateJenkinsImage checks a new JenkinsImage, or the changes of the spec of an existing one
+func validateJenkinsImage(obj, old runtime.Object) field.ErrorList {
+	image := obj.(*jenkinsv1alpha1.JenkinsImage)
+	if old != nil && (image.DeletionTimestamp != nil || reflect.DeepEqual(image.Spec, old.(*jenkinsv1alpha1.JenkinsImage).Spec)) {
+		return nil
+	}
+	return jenkinsimage.ValidateJenkinsImage(image)
+}
@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/appscode/jsonpatch"
+	jenkinsv1alpha1 "github.com/redhat-developer/openshift-jenkins-operator/pkg/apis/jenkins/v1alpha1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/controller/jenkins"
+	"github.com/redhat-developer/openshift-jenkins-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+const (
+	test_ns   = "test"
+	test_name = "test-jenkins"
+)
+
+// handle sends the object, and the previous object on updates, to the webhook served at path. The requester
+// may bind every role.
+func handle(t *testing.T, apis jenkins.DiscoveredAPIs, path string, obj, old runtime.Object) atypes.Response {
+	return handleReviewed(t, apis, &fakeReviewer{}, path, obj, old)
+}
+
+// handleReviewed sends the request of handle, the reviewer decides whether the requester may bind the roles
+func handleReviewed(t *testing.T, apis jenkins.DiscoveredAPIs, reviewer accessReviewer, path string, obj, old runtime.Object) atypes.Response {
+	request := &admissionv1beta1.AdmissionRequest{UID: "uid", Operation: admissionv1beta1.Create}
+	request.UserInfo = authenticationv1.UserInfo{Username: "developer", Groups: []string{"system:authenticated"}}
+	request.Kind = metav1.GroupVersionKind{Group: jenkinsv1alpha1.SchemeGroupVersion.Group, Version: jenkinsv1alpha1.SchemeGroupVersion.Version}
+	if gvk := obj.GetObjectKind().GroupVersionKind(); !gvk.Empty() {
+		request.Kind = metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind}
+	}
+	var err error
+	request.Object.Raw, err = json.Marshal(obj)
+	require.NoError(t, err)
+	if old != nil {
+		request.Operation = admissionv1beta1.Update
+		request.OldObject.Raw, err = json.Marshal(old)
+		require.NoError(t, err)
+	}
+	for _, webhook := range newWebhooks(apis, reviewer) {
+		require.NoError(t, webhook.Validate())
+		if webhook.GetPath() == path {
+			return webhook.Handle(context.TODO(), atypes.Request{AdmissionRequest: request})
+		}
+	}
+	t.Fatalf("no webhook served at %s", path)
+	return atypes.Response{}
+}
+
+// patchedValues returns the values set by the JSON patch of the response by path
+func patchedValues(t *testing.T, response atypes.Response) map[string]interface{} {
+	operations := []jsonpatch.JsonPatchOperation{}
+	require.NoError(t, json.Unmarshal(response.Response.Patch, &operations))
+	values := map[string]interface{}{}
+	for _, operation := range operations {
+		values[operation.Path] = operation.Value
+	}
+	return values
+}
+
+func TestDefaultingWebhooks(t *testing.T) {
+	t.Run("TestJenkinsDefaults", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Persistence.Enabled = true
+		cr.Spec.UseDeploymentConfig = true
+
+		response := handle(t, jenkins.DiscoveredAPIs{}, MutateJenkinsPath, cr, nil)
+		require.True(t, response.Response.Allowed)
+		patch := patchedValues(t, response)
+		require.Equal(t, map[string]interface{}{
+			"/spec/persistence/size":    jenkins.JenkinsPvcDefaultSize,
+			"/spec/useDeploymentConfig": nil,
+		}, patch)
+
+		// the DeploymentConfig is kept on OpenShift
+		response = handle(t, jenkins.DiscoveredAPIs{DeploymentConfig: true}, MutateJenkinsPath, cr, nil)
+		require.Len(t, patchedValues(t, response), 1)
+	})
+	t.Run("TestJenkinsImageDefaults", func(t *testing.T) {
+		image := &jenkinsv1alpha1.JenkinsImage{
+			TypeMeta:   metav1.TypeMeta{APIVersion: jenkinsv1alpha1.SchemeGroupVersion.String(), Kind: "JenkinsImage"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name},
+			Spec:       jenkinsv1alpha1.JenkinsImageSpec{Plugins: []jenkinsv1alpha1.JenkinsPlugin{{Name: " git ", Version: "4.0 "}}},
+		}
+		response := handle(t, jenkins.DiscoveredAPIs{}, MutateJenkinsImagePath, image, nil)
+		require.True(t, response.Response.Allowed)
+		require.Equal(t, map[string]interface{}{
+			"/spec/plugins/0/name":    "git",
+			"/spec/plugins/0/version": "4.0",
+		}, patchedValues(t, response))
+	})
+}
+
+func TestValidatingWebhooks(t *testing.T) {
+	t.Run("TestInvalidJenkinsIsRejected", func(t *testing.T) {
+		cr := mocks.JenkinsCRMock(test_ns, test_name)
+		cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, Size: "ten gigabytes"}
+
+		response := handle(t, jenkins.DiscoveredAPIs{}, ValidateJenkinsPath, cr, nil)
+		require.False(t, response.Response.Allowed)
+		require.Equal(t, int32(http.StatusUnprocessableEntity), response.Response.Result.Code)
+		require.Contains(t, response.Response.Result.Message, "spec.persistence.size")
+	})
+	t.Run("TestShrinkingTheClaimIsRejected", func(t *testing.T) {
+		old := mocks.JenkinsCRMock(test_ns, test_name)
+		old.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, Size: "10Gi"}
+		cr := old.DeepCopy()
+		cr.Spec.Persistence.Size = "5Gi"
+		storageClass := "fast"
+		cr.Spec.Persistence.StorageClassName = &storageClass
+
+		response := handle(t, jenkins.DiscoveredAPIs{}, ValidateJenkinsPath, cr, old)
+		require.False(t, response.Response.Allowed)
+		require.Contains(t, response.Response.Result.Message, "spec.persistence.size: Forbidden: cannot be decreased from 10Gi to 5Gi")
+		require.Contains(t, response.Response.Result.Message, "spec.persistence.storageClassName: Invalid value: \"fast\": field is immutable")
+
+		cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, Size: "20Gi"}
+		response = handle(t, jenkins.DiscoveredAPIs{}, ValidateJenkinsPath, cr, old)
+		require.True(t, response.Response.Allowed)
+	})
+	t.Run("TestDisablingPersistenceIsRejected", func(t *testing.T) {
+		old := mocks.JenkinsCRMock(test_ns, test_name)
+		old.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, Size: "10Gi"}
+		cr := old.DeepCopy()
+		cr.Spec.Persistence.Enabled = false
+
+		// enabling it again would recreate a smaller claim
+		response := handle(t, jenkins.DiscoveredAPIs{}, ValidateJenkinsPath, cr, old)
+		require.False(t, response.Response.Allowed)
+		require.Contains(t, response.Response.Result.Message, "spec.persistence.enabled: Forbidden: cannot be disabled")
+
+		cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, ExistingClaim: "jenkins-home"}
+		response = handle(t, jenkins.DiscoveredAPIs{}, ValidateJenkinsPath, cr, old)
+		require.False(t, response.Response.Allowed)
+		require.Contains(t, response.Response.Result.Message, "spec.persistence.existingClaim: Invalid value: \"jenkins-home\": field is immutable")
+
+		// an ephemeral instance can be made persistent
+		old.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{}
+		cr.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, Size: "5Gi"}
+		response = handle(t, jenkins.DiscoveredAPIs{}, ValidateJenkinsPath, cr, old)
+		require.True(t, response.Response.Allowed)
+	})
+	t.Run("TestMetadataOfInvalidJenkinsCanBeUpdated", func(t *testing.T) {
+		old := mocks.JenkinsCRMock(test_ns, test_name)
+		old.Spec.Persistence = jenkinsv1alpha1.JenkinsPersistence{Enabled: true, Size: "ten gigabytes"}
+		cr := old.DeepCopy()
+		cr.Finalizers = []string{jenkins.JenkinsFinalizer}
+
+		response := handle(t, jenkins.DiscoveredAPIs{}, ValidateJenkinsPath, cr, old)
+		require.True(t, response.Response.Allowed)
+	})
+	t.Run("TestDuplicatePluginsAreRejected", func(t *testing.T) {
+		old := &jenkinsv1alpha1.JenkinsImage{
+			TypeMeta:   metav1.TypeMeta{APIVersion: jenkinsv1alpha1.SchemeGroupVersion.String(), Kind: "JenkinsImage"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: test_ns, Name: test_name},
+			Spec:       jenkinsv1alpha1.JenkinsImageSpec{Plugins: []jenkinsv1alpha1.JenkinsPlugin{{Name: "git"}, {Name: "blueocean"}}},
+		}
+		image := old.DeepCopy()
+		image.Spec.Plugins[1].Name = "git"
+
+		response := handle(t, jenkins.DiscoveredAPIs{}, ValidateJenkinsImagePath, image, old)
+		require.False(t, response.Response.Allowed)
+		require.Contains(t, response.Response.Result.Message, "spec.plugins[1].name: Duplicate value: \"git\"")
+	})
+}
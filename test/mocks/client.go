@@ -0,0 +1,315 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	buildv1 "github.com/openshift/api/build/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/redhat-developer/openshift-jenkins-operator/pkg/apis"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// NewScheme returns a scheme knowing about the kubernetes, openshift and jenkins.dev types
+func NewScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		scheme.AddToScheme,
+		apis.AddToScheme,
+		appsv1.AddToScheme,
+		buildv1.AddToScheme,
+		imagev1.AddToScheme,
+		routev1.AddToScheme,
+	} {
+		if err := addToScheme(s); err != nil {
+			panic(err)
+		}
+	}
+	return s
+}
+
+// Verbs of the requests of the FakeClient, used to inject failures
+const (
+	VerbGet          = "get"
+	VerbList         = "list"
+	VerbCreate       = "create"
+	VerbUpdate       = "update"
+	VerbUpdateStatus = "update-status"
+	VerbDelete       = "delete"
+)
+
+// FakeClient is a thread safe in-memory client.Client used to test the controllers
+type FakeClient struct {
+	scheme   *runtime.Scheme
+	lock     sync.Mutex
+	objects  map[fakeKey]runtime.Object
+	version  int
+	failures []fakeFailure
+}
+
+// fakeFailure is an error returned by the requests matching the verb, kind and name
+type fakeFailure struct {
+	verb string
+	kind string
+	name string
+	err  error
+	once bool
+}
+
+type fakeKey struct {
+	gvk schema.GroupVersionKind
+	types.NamespacedName
+}
+
+var _ client.Client = &FakeClient{}
+
+// NewFakeClient returns a FakeClient holding a copy of the given objects
+func NewFakeClient(s *runtime.Scheme, objs ...runtime.Object) *FakeClient {
+	c := &FakeClient{scheme: s, objects: map[fakeKey]runtime.Object{}}
+	for _, obj := range objs {
+		if err := c.Create(context.TODO(), obj); err != nil {
+			panic(err)
+		}
+	}
+	return c
+}
+
+// InjectFailure makes the requests of the verb on the objects of the kind and name fail with err until
+// ClearFailures is called. An empty kind or name matches every object, the kind of a list is the kind of
+// its items.
+func (c *FakeClient) InjectFailure(verb, kind, name string, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.failures = append(c.failures, fakeFailure{verb: verb, kind: kind, name: name, err: err})
+}
+
+// InjectFailureOnce makes the next request of the verb on the objects of the kind and name fail with err
+func (c *FakeClient) InjectFailureOnce(verb, kind, name string, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.failures = append(c.failures, fakeFailure{verb: verb, kind: kind, name: name, err: err, once: true})
+}
+
+// ClearFailures removes the failures injected so far
+func (c *FakeClient) ClearFailures() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.failures = nil
+}
+
+// failure returns the error injected for the request, if any. The lock must be held.
+func (c *FakeClient) failure(verb string, key fakeKey) error {
+	for i, f := range c.failures {
+		if f.verb == verb && (len(f.kind) == 0 || f.kind == key.gvk.Kind) && (len(f.name) == 0 || f.name == key.Name) {
+			if f.once {
+				c.failures = append(c.failures[:i], c.failures[i+1:]...)
+			}
+			return f.err
+		}
+	}
+	return nil
+}
+
+func (c *FakeClient) keyFor(obj runtime.Object) (fakeKey, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return fakeKey{}, err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return fakeKey{}, err
+	}
+	return fakeKey{gvk: gvk, NamespacedName: types.NamespacedName{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}}, nil
+}
+
+func notFound(key fakeKey) error {
+	return kubeerrors.NewNotFound(schema.GroupResource{Group: key.gvk.Group, Resource: strings.ToLower(key.gvk.Kind)}, key.Name)
+}
+
+func copyInto(src, dst runtime.Object) {
+	reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(src.DeepCopyObject()).Elem())
+}
+
+// Get retrieves the object stored under key
+func (c *FakeClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	k := fakeKey{gvk: gvk, NamespacedName: key}
+	if err := c.failure(VerbGet, k); err != nil {
+		return err
+	}
+	stored, found := c.objects[k]
+	if !found {
+		return notFound(k)
+	}
+	copyInto(stored, obj)
+	return nil
+}
+
+// List retrieves the objects matching the namespace and label selector of opts
+func (c *FakeClient) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failure(VerbList, fakeKey{gvk: gvk}); err != nil {
+		return err
+	}
+	items := []runtime.Object{}
+	for key, obj := range c.objects {
+		if key.gvk != gvk {
+			continue
+		}
+		if opts != nil && len(opts.Namespace) > 0 && opts.Namespace != key.Namespace {
+			continue
+		}
+		accessor, _ := meta.Accessor(obj)
+		if opts != nil && opts.LabelSelector != nil && !opts.LabelSelector.Matches(labels.Set(accessor.GetLabels())) {
+			continue
+		}
+		items = append(items, obj.DeepCopyObject())
+	}
+	return meta.SetList(list, items)
+}
+
+// Create stores a copy of obj
+func (c *FakeClient) Create(ctx context.Context, obj runtime.Object) error {
+	key, err := c.keyFor(obj)
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failure(VerbCreate, key); err != nil {
+		return err
+	}
+	if _, found := c.objects[key]; found {
+		return kubeerrors.NewAlreadyExists(schema.GroupResource{Group: key.gvk.Group, Resource: strings.ToLower(key.gvk.Kind)}, key.Name)
+	}
+	accessor, _ := meta.Accessor(obj)
+	accessor.SetUID(uuid.NewUUID())
+	accessor.SetGeneration(1)
+	accessor.SetCreationTimestamp(metav1.Now())
+	c.bumpResourceVersion(accessor)
+	c.objects[key] = obj.DeepCopyObject()
+	return nil
+}
+
+// Delete removes obj, or only marks it as being deleted while it holds finalizers
+func (c *FakeClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOptionFunc) error {
+	key, err := c.keyFor(obj)
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failure(VerbDelete, key); err != nil {
+		return err
+	}
+	stored, found := c.objects[key]
+	if !found {
+		return notFound(key)
+	}
+	accessor, _ := meta.Accessor(stored)
+	if len(accessor.GetFinalizers()) == 0 {
+		delete(c.objects, key)
+		return nil
+	}
+	if accessor.GetDeletionTimestamp() == nil {
+		now := metav1.Now()
+		accessor.SetDeletionTimestamp(&now)
+		c.bumpResourceVersion(accessor)
+	}
+	return nil
+}
+
+// Update replaces the stored copy of obj
+func (c *FakeClient) Update(ctx context.Context, obj runtime.Object) error {
+	return c.update(obj, false)
+}
+
+// Status returns a writer only updating the status of the objects
+func (c *FakeClient) Status() client.StatusWriter {
+	return &fakeStatusWriter{client: c}
+}
+
+type fakeStatusWriter struct {
+	client *FakeClient
+}
+
+func (w *fakeStatusWriter) Update(ctx context.Context, obj runtime.Object) error {
+	return w.client.update(obj, true)
+}
+
+func (c *FakeClient) update(obj runtime.Object, statusOnly bool) error {
+	key, err := c.keyFor(obj)
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	verb := VerbUpdate
+	if statusOnly {
+		verb = VerbUpdateStatus
+	}
+	if err := c.failure(verb, key); err != nil {
+		return err
+	}
+	stored, found := c.objects[key]
+	if !found {
+		return notFound(key)
+	}
+	accessor, _ := meta.Accessor(obj)
+	storedAccessor, _ := meta.Accessor(stored)
+	if len(accessor.GetResourceVersion()) > 0 && accessor.GetResourceVersion() != storedAccessor.GetResourceVersion() {
+		return kubeerrors.NewConflict(schema.GroupResource{Group: key.gvk.Group, Resource: strings.ToLower(key.gvk.Kind)}, key.Name,
+			fmt.Errorf("the object has been modified"))
+	}
+	updated := obj.DeepCopyObject()
+	if statusOnly {
+		updated = stored.DeepCopyObject()
+		status := reflect.ValueOf(obj).Elem().FieldByName("Status")
+		if status.IsValid() {
+			reflect.ValueOf(updated).Elem().FieldByName("Status").Set(status)
+		}
+	}
+	updatedAccessor, _ := meta.Accessor(updated)
+	updatedAccessor.SetDeletionTimestamp(storedAccessor.GetDeletionTimestamp())
+	c.bumpResourceVersion(updatedAccessor)
+	accessor.SetResourceVersion(updatedAccessor.GetResourceVersion())
+	if updatedAccessor.GetDeletionTimestamp() != nil && len(updatedAccessor.GetFinalizers()) == 0 {
+		// the last finalizer was removed from an object being deleted
+		delete(c.objects, key)
+		return nil
+	}
+	c.objects[key] = updated.DeepCopyObject()
+	return nil
+}
+
+func (c *FakeClient) bumpResourceVersion(accessor metav1.Object) {
+	c.version++
+	accessor.SetResourceVersion(strconv.Itoa(c.version))
+}
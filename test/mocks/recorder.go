@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// FakeRecorder is an EventRecorder keeping the events in memory. Unlike record.FakeRecorder it never blocks,
+// whatever the number of events recorded.
+type FakeRecorder struct {
+	lock   sync.Mutex
+	events []string
+}
+
+// NewFakeRecorder returns an empty FakeRecorder
+func NewFakeRecorder() *FakeRecorder {
+	return &FakeRecorder{events: []string{}}
+}
+
+// Events returns the events recorded so far, formatted as "<type> <reason> <message>"
+func (r *FakeRecorder) Events() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]string{}, r.events...)
+}
+
+// Reasons returns the types and reasons of the events recorded so far, formatted as "<type> <reason>"
+func (r *FakeRecorder) Reasons() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	reasons := []string{}
+	for _, event := range r.events {
+		var eventType, reason string
+		fmt.Sscanf(event, "%s %s", &eventType, &reason)
+		reasons = append(reasons, eventType+" "+reason)
+	}
+	return reasons
+}
+
+func (r *FakeRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, eventtype+" "+reason+" "+message)
+}
+
+func (r *FakeRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *FakeRecorder) PastEventf(object runtime.Object, timestamp metav1.Time, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+func (r *FakeRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
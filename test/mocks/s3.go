@@ -0,0 +1,223 @@
+package mocks
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeObject is an object stored by the FakeS3 handler
+type FakeObject struct {
+	Data         []byte
+	LastModified time.Time
+}
+
+// FakeS3 is an in memory http.Handler implementing the subset of the S3 API used by the operator: multipart
+// uploads with sha256 checksums, ListObjectsV2 and GetObject. Buckets are addressed with path style URLs.
+type FakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]FakeObject
+	uploads map[string]map[int][]byte
+	nextID  int
+	// AccessKeyID is the only access key accepted when it is set
+	AccessKeyID string
+	// PageSize is the maximum number of objects returned by a list request, 1000 by default
+	PageSize int
+	// FailPart makes the upload of the part with this number fail when it is set
+	FailPart int
+	// Aborted counts the aborted multipart uploads
+	Aborted int
+}
+
+// NewFakeS3 returns an empty fake object storage
+func NewFakeS3() *FakeS3 {
+	return &FakeS3{objects: map[string]FakeObject{}, uploads: map[string]map[int][]byte{}}
+}
+
+// PutObject stores an object in the bucket
+func (f *FakeS3) PutObject(bucket, key string, data []byte, lastModified time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[bucket+"/"+key] = FakeObject{Data: data, LastModified: lastModified}
+}
+
+// Object returns an object of the bucket
+func (f *FakeS3) Object(bucket, key string) (FakeObject, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	object, found := f.objects[bucket+"/"+key]
+	return object, found
+}
+
+// PendingUploads returns the number of multipart uploads neither completed nor aborted
+func (f *FakeS3) PendingUploads() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.uploads)
+}
+
+func (f *FakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="+f.AccessKeyID) {
+		writeS3Error(w, http.StatusForbidden, "AccessDenied", "Access Denied")
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	bucket, key := path, ""
+	if i := strings.Index(path, "/"); i >= 0 {
+		bucket, key = path[:i], path[i+1:]
+	}
+	query := r.URL.Query()
+	body, _ := ioutil.ReadAll(r.Body)
+	_, uploads := query["uploads"]
+	uploadID := query.Get("uploadId")
+
+	switch {
+	case r.Method == http.MethodPost && uploads:
+		f.nextID++
+		id := strconv.Itoa(f.nextID)
+		f.uploads[id] = map[int][]byte{}
+		writeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Bucket   string
+			Key      string
+			UploadID string `xml:"UploadId"`
+		}{Bucket: bucket, Key: key, UploadID: id})
+	case r.Method == http.MethodPut && len(uploadID) > 0:
+		parts, found := f.uploads[uploadID]
+		if !found {
+			writeS3Error(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist")
+			return
+		}
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		if number == f.FailPart {
+			writeS3Error(w, http.StatusInternalServerError, "InternalError", "Injected failure")
+			return
+		}
+		sum := sha256.Sum256(body)
+		if checksum := r.Header.Get("X-Amz-Checksum-Sha256"); len(checksum) > 0 && checksum != base64.StdEncoding.EncodeToString(sum[:]) {
+			writeS3Error(w, http.StatusBadRequest, "BadDigest", "The SHA256 you specified did not match the calculated checksum")
+			return
+		}
+		parts[number] = body
+		w.Header().Set("ETag", etag(body))
+	case r.Method == http.MethodPost && len(uploadID) > 0:
+		parts, found := f.uploads[uploadID]
+		if !found {
+			writeS3Error(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist")
+			return
+		}
+		completed := struct {
+			Parts []struct {
+				PartNumber int
+				ETag       string
+			} `xml:"Part"`
+		}{}
+		if err := xml.Unmarshal(body, &completed); err != nil || len(completed.Parts) == 0 {
+			writeS3Error(w, http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed")
+			return
+		}
+		data := bytes.Buffer{}
+		for i, part := range completed.Parts {
+			content, found := parts[part.PartNumber]
+			if !found || part.PartNumber != i+1 || part.ETag != etag(content) {
+				writeS3Error(w, http.StatusBadRequest, "InvalidPart", "One or more of the specified parts could not be found")
+				return
+			}
+			data.Write(content)
+		}
+		delete(f.uploads, uploadID)
+		f.objects[bucket+"/"+key] = FakeObject{Data: data.Bytes(), LastModified: time.Now()}
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Key     string
+			ETag    string
+		}{Key: key, ETag: fmt.Sprintf("%q", fmt.Sprintf("%x-%d", md5.Sum(data.Bytes()), len(completed.Parts)))})
+	case r.Method == http.MethodDelete && len(uploadID) > 0:
+		delete(f.uploads, uploadID)
+		f.Aborted++
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && len(key) == 0:
+		f.list(w, bucket, query.Get("prefix"), query.Get("continuation-token"))
+	case r.Method == http.MethodGet:
+		object, found := f.objects[bucket+"/"+key]
+		if !found {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist")
+			return
+		}
+		w.Write(object.Data)
+	default:
+		writeS3Error(w, http.StatusNotImplemented, "NotImplemented", r.Method+" is not implemented")
+	}
+}
+
+// list writes a page of the objects of the bucket, the continuation token is the last key of the previous page
+func (f *FakeS3) list(w http.ResponseWriter, bucket, prefix, token string) {
+	pageSize := f.PageSize
+	if pageSize == 0 {
+		pageSize = 1000
+	}
+	keys := []string{}
+	for name := range f.objects {
+		if key := strings.TrimPrefix(name, bucket+"/"); key != name && strings.HasPrefix(key, prefix) && key > token {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	type content struct {
+		Key          string
+		Size         int
+		LastModified string
+		ETag         string
+	}
+	result := struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Contents              []content
+		IsTruncated           bool
+		NextContinuationToken string `xml:",omitempty"`
+	}{}
+	for _, key := range keys {
+		if len(result.Contents) == pageSize {
+			result.IsTruncated = true
+			result.NextContinuationToken = result.Contents[pageSize-1].Key
+			break
+		}
+		object := f.objects[bucket+"/"+key]
+		result.Contents = append(result.Contents, content{
+			Key:          key,
+			Size:         len(object.Data),
+			LastModified: object.LastModified.UTC().Format(time.RFC3339),
+			ETag:         etag(object.Data),
+		})
+	}
+	writeXML(w, result)
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+	return fmt.Sprintf("%q", hex.EncodeToString(sum[:]))
+}
+
+func writeXML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	content, _ := xml.Marshal(v)
+	w.Write(content)
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, message)
+}